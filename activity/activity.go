@@ -0,0 +1,516 @@
+package activity
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_RETENTION is default period of keeping snapshots
+const DEFAULT_RETENTION = 90 * 24 * time.Hour
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by activity tracker
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+}
+
+// Store is storage for activity snapshots
+type Store interface {
+	// Add saves snapshot
+	Add(snapshot *Snapshot) error
+
+	// List returns snapshots taken in given period sorted by time
+	List(from, to time.Time) ([]*Snapshot, error)
+
+	// Prune removes snapshots taken before given time
+	Prune(before time.Time) error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Snapshot contains last activity time of users at some moment
+type Snapshot struct {
+	Time     time.Time          `json:"time"`
+	Activity map[uint]time.Time `json:"activity"`
+}
+
+// DayCount contains number of active users for one day
+type DayCount struct {
+	Date  time.Time `json:"date"`
+	Users int       `json:"users"`
+}
+
+// Report contains activity report
+type Report struct {
+	Time        time.Time    // Report generation time
+	Days        int          // Inactivity period in days
+	Users       int          // Number of active accounts
+	Seats       int          // Number of paid seats
+	Dormant     pachca.Users // Accounts without activity in given period
+	IdleSeats   pachca.Users // Paid seats held by dormant accounts
+	DailyActive []DayCount   // Daily active users
+}
+
+// Tracker takes snapshots of users activity and builds reports
+type Tracker struct {
+	// Retention is period of keeping snapshots (90 days by default)
+	Retention time.Duration
+
+	// Location is time zone for splitting activity into days (UTC by default)
+	Location *time.Location
+
+	// OnError is callback for errors which occurred during background snapshots
+	OnError func(err error)
+
+	client Client
+	store  Store
+}
+
+// MemoryStore is in-memory snapshots store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data []*Snapshot
+}
+
+// FileStore is snapshots store which keeps data in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data []*Snapshot
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient       = errors.New("client is nil")
+	ErrNilStore        = errors.New("store is nil")
+	ErrNilTracker      = errors.New("tracker is nil")
+	ErrNilSnapshot     = errors.New("snapshot is nil")
+	ErrEmptyFilePath   = errors.New("store file path is empty")
+	ErrInvalidDays     = errors.New("number of days must be greater than 0")
+	ErrInvalidPeriod   = errors.New("period end must be after period start")
+	ErrInvalidInterval = errors.New("snapshot interval must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Take creates snapshot with last activity time of active users. Bots and
+// users who have never been active are ignored.
+func Take(users pachca.Users, now time.Time) *Snapshot {
+	snapshot := &Snapshot{Time: now, Activity: map[uint]time.Time{}}
+
+	for _, user := range users.Active().People() {
+		if !user.LastActivityAt.IsZero() {
+			snapshot.Activity[user.ID] = user.LastActivityAt.Time
+		}
+	}
+
+	return snapshot
+}
+
+// Dormant returns active users without any activity in the last given number
+// of days sorted by last activity time. Creation date is used for users who
+// have never been active, so new accounts are not treated as dormant.
+func Dormant(users pachca.Users, days int, now time.Time) pachca.Users {
+	if days <= 0 {
+		return nil
+	}
+
+	since := now.AddDate(0, 0, -days)
+
+	result := users.Active().People().Filter(func(u *pachca.User) bool {
+		return LastSeen(u).Before(since)
+	})
+
+	slices.SortStableFunc(result, func(u1, u2 *pachca.User) int {
+		return LastSeen(u1).Compare(LastSeen(u2))
+	})
+
+	return result
+}
+
+// IdleSeats returns paid users without any activity in the last given number
+// of days
+func IdleSeats(users pachca.Users, days int, now time.Time) pachca.Users {
+	return Dormant(users, days, now).Paid()
+}
+
+// DailyActive returns number of active users for every day in given period.
+// Activity is collected from all snapshots, so user is counted for every day
+// with activity observed by at least one snapshot.
+func DailyActive(snapshots []*Snapshot, from, to time.Time, loc *time.Location) []DayCount {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	from, to = startOfDay(from, loc), startOfDay(to, loc)
+
+	if to.Before(from) {
+		return nil
+	}
+
+	active := map[time.Time]map[uint]bool{}
+
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+
+		for userID, t := range snapshot.Activity {
+			day := startOfDay(t, loc)
+
+			if day.Before(from) || day.After(to) {
+				continue
+			}
+
+			if active[day] == nil {
+				active[day] = map[uint]bool{}
+			}
+
+			active[day][userID] = true
+		}
+	}
+
+	var result []DayCount
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		result = append(result, DayCount{Date: day, Users: len(active[day])})
+	}
+
+	return result
+}
+
+// LastSeen returns time of the last user activity or creation date if user
+// has never been active
+func LastSeen(user *pachca.User) time.Time {
+	switch {
+	case user == nil:
+		return time.Time{}
+	case user.LastActivityAt.IsZero():
+		return user.CreatedAt.Time
+	}
+
+	return user.LastActivityAt.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new activity tracker
+func New(client Client, store Store) (*Tracker, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	}
+
+	return &Tracker{client: client, store: store}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// NewFileStore creates new file store and loads snapshots from given file if
+// it exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	sortSnapshots(s.data)
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Snapshot takes snapshot of users activity, saves it to the store and removes
+// snapshots older than retention period
+func (t *Tracker) Snapshot(now time.Time) (*Snapshot, error) {
+	if t == nil {
+		return nil, ErrNilTracker
+	}
+
+	users, err := t.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	snapshot := Take(users, now)
+	err = t.store.Add(snapshot)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save snapshot: %w", err)
+	}
+
+	err = t.store.Prune(now.Add(-t.getRetention()))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't prune snapshots: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// DailyActive returns number of active users for every day in given period
+// using saved snapshots
+func (t *Tracker) DailyActive(from, to time.Time) ([]DayCount, error) {
+	switch {
+	case t == nil:
+		return nil, ErrNilTracker
+	case to.Before(from):
+		return nil, ErrInvalidPeriod
+	}
+
+	// Snapshots taken after the end of period can contain activity from the
+	// last day of period
+	snapshots, err := t.store.List(from, to.AddDate(0, 0, 1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return DailyActive(snapshots, from, to, t.Location), nil
+}
+
+// Report returns report with dormant accounts, idle paid seats and daily active
+// users for the last given number of days
+func (t *Tracker) Report(days int, now time.Time) (*Report, error) {
+	switch {
+	case t == nil:
+		return nil, ErrNilTracker
+	case days <= 0:
+		return nil, ErrInvalidDays
+	}
+
+	users, err := t.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	daily, err := t.DailyActive(now.AddDate(0, 0, -days+1), now)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get daily activity: %w", err)
+	}
+
+	active := users.Active().People()
+	dormant := Dormant(active, days, now)
+
+	return &Report{
+		Time:        now,
+		Days:        days,
+		Users:       len(active),
+		Seats:       len(active.Paid()),
+		Dormant:     dormant,
+		IdleSeats:   dormant.Paid(),
+		DailyActive: daily,
+	}, nil
+}
+
+// Run periodically takes snapshots until stop channel is closed
+func (t *Tracker) Run(interval time.Duration, stop <-chan struct{}) error {
+	switch {
+	case t == nil:
+		return ErrNilTracker
+	case interval <= 0:
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			_, err := t.Snapshot(now)
+
+			if err != nil && t.OnError != nil {
+				t.OnError(err)
+			}
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add saves snapshot
+func (s *MemoryStore) Add(snapshot *Snapshot) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case snapshot == nil:
+		return ErrNilSnapshot
+	}
+
+	s.mu.Lock()
+	s.data = append(s.data, snapshot)
+	sortSnapshots(s.data)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns snapshots taken in given period sorted by time
+func (s *MemoryStore) List(from, to time.Time) ([]*Snapshot, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return filterSnapshots(s.data, from, to), nil
+}
+
+// Prune removes snapshots taken before given time
+func (s *MemoryStore) Prune(before time.Time) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	s.data = pruneSnapshots(s.data, before)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add saves snapshot
+func (s *FileStore) Add(snapshot *Snapshot) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case snapshot == nil:
+		return ErrNilSnapshot
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = append(s.data, snapshot)
+	sortSnapshots(s.data)
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// List returns snapshots taken in given period sorted by time
+func (s *FileStore) List(from, to time.Time) ([]*Snapshot, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return filterSnapshots(s.data, from, to), nil
+}
+
+// Prune removes snapshots taken before given time
+func (s *FileStore) Prune(before time.Time) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := len(s.data)
+	s.data = pruneSnapshots(s.data, before)
+
+	if len(s.data) == size {
+		return nil
+	}
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getRetention returns retention period
+func (t *Tracker) getRetention() time.Duration {
+	if t.Retention <= 0 {
+		return DEFAULT_RETENTION
+	}
+
+	return t.Retention
+}
+
+// startOfDay returns start of the day in given location
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// sortSnapshots sorts snapshots by time
+func sortSnapshots(snapshots []*Snapshot) {
+	slices.SortStableFunc(snapshots, func(s1, s2 *Snapshot) int {
+		return s1.Time.Compare(s2.Time)
+	})
+}
+
+// filterSnapshots returns snapshots taken in given period
+func filterSnapshots(snapshots []*Snapshot, from, to time.Time) []*Snapshot {
+	var result []*Snapshot
+
+	for _, snapshot := range snapshots {
+		if !snapshot.Time.Before(from) && !snapshot.Time.After(to) {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result
+}
+
+// pruneSnapshots removes snapshots taken before given time
+func pruneSnapshots(snapshots []*Snapshot, before time.Time) []*Snapshot {
+	index, _ := slices.BinarySearchFunc(snapshots, before, func(s *Snapshot, t time.Time) int {
+		return s.Time.Compare(t)
+	})
+
+	return slices.Clone(snapshots[index:])
+}
@@ -0,0 +1,273 @@
+package activity
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ActivitySuite struct{}
+
+type fakeClient struct {
+	users pachca.Users
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ActivitySuite{})
+
+var _ Client = (*pachca.Client)(nil)
+
+var now = time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ActivitySuite) TestDormant(c *C) {
+	users := testUsers()
+
+	c.Assert(userIDs(Dormant(users, 30, now)), DeepEquals, []uint{4, 3})
+	c.Assert(userIDs(Dormant(users, 3, now)), DeepEquals, []uint{4, 3, 2})
+	c.Assert(userIDs(IdleSeats(users, 30, now)), DeepEquals, []uint{3})
+	c.Assert(Dormant(users, 0, now), IsNil)
+
+	c.Assert(LastSeen(users[3]), Equals, users[3].CreatedAt.Time)
+	c.Assert(LastSeen(users[0]), Equals, users[0].LastActivityAt.Time)
+	c.Assert(LastSeen(nil).IsZero(), Equals, true)
+}
+
+func (s *ActivitySuite) TestDailyActive(c *C) {
+	snapshots := []*Snapshot{
+		{Time: now.AddDate(0, 0, -2), Activity: map[uint]time.Time{
+			1: now.AddDate(0, 0, -2).Add(-time.Hour),
+			2: now.AddDate(0, 0, -5),
+		}},
+		nil,
+		{Time: now, Activity: map[uint]time.Time{
+			1: now.Add(-time.Hour),
+			2: now.AddDate(0, 0, -5),
+			3: now.AddDate(0, 0, -1),
+		}},
+	}
+
+	counts := DailyActive(snapshots, now.AddDate(0, 0, -2), now, nil)
+	c.Assert(counts, DeepEquals, []DayCount{
+		{Date: time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC), Users: 1},
+		{Date: time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC), Users: 1},
+		{Date: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC), Users: 1},
+	})
+
+	loc := time.FixedZone("UTC+13", 13*3600)
+	counts = DailyActive(snapshots, now, now, loc)
+	c.Assert(counts, HasLen, 1)
+	c.Assert(counts[0].Date.Equal(time.Date(2026, 11, 11, 0, 0, 0, 0, loc)), Equals, true)
+	c.Assert(counts[0].Users, Equals, 1)
+
+	c.Assert(DailyActive(snapshots, now, now.AddDate(0, 0, -1), nil), IsNil)
+}
+
+func (s *ActivitySuite) TestTracker(c *C) {
+	client := &fakeClient{users: testUsers()}
+	store := NewMemoryStore()
+
+	_, err := New(nil, store)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(client, nil)
+	c.Assert(err, Equals, ErrNilStore)
+
+	t, err := New(client, store)
+	c.Assert(err, IsNil)
+
+	t.Retention = 7 * 24 * time.Hour
+
+	old := &Snapshot{Time: now.AddDate(0, 0, -30)}
+	c.Assert(store.Add(old), IsNil)
+
+	snapshot, err := t.Snapshot(now.AddDate(0, 0, -1))
+	c.Assert(err, IsNil)
+	c.Assert(snapshot.Activity, HasLen, 3)
+	c.Assert(snapshot.Activity[1], Equals, client.users[0].LastActivityAt.Time)
+
+	client.users[0].LastActivityAt = pachca.Date{Time: now.Add(-time.Hour)}
+
+	_, err = t.Snapshot(now)
+	c.Assert(err, IsNil)
+
+	snapshots, err := store.List(time.Time{}, now)
+	c.Assert(err, IsNil)
+	c.Assert(snapshots, HasLen, 2)
+
+	report, err := t.Report(3, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Days, Equals, 3)
+	c.Assert(report.Users, Equals, 5)
+	c.Assert(report.Seats, Equals, 4)
+	c.Assert(userIDs(report.Dormant), DeepEquals, []uint{4, 3, 2})
+	c.Assert(userIDs(report.IdleSeats), DeepEquals, []uint{3, 2})
+	c.Assert(report.DailyActive, DeepEquals, []DayCount{
+		{Date: time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC), Users: 0},
+		{Date: time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC), Users: 1},
+		{Date: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC), Users: 1},
+	})
+
+	_, err = t.Report(0, now)
+	c.Assert(err, Equals, ErrInvalidDays)
+	_, err = t.DailyActive(now, now.AddDate(0, 0, -1))
+	c.Assert(err, Equals, ErrInvalidPeriod)
+
+	client.users = nil
+
+	_, err = t.Snapshot(now)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+	_, err = t.Report(3, now)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+
+	c.Assert(t.Run(0, nil), Equals, ErrInvalidInterval)
+
+	var errs []error
+	stop := make(chan struct{})
+
+	t.OnError = func(err error) { errs = append(errs, err) }
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+	}()
+
+	c.Assert(t.Run(10*time.Millisecond, stop), IsNil)
+	c.Assert(errs, Not(HasLen), 0)
+}
+
+func (s *ActivitySuite) TestStores(c *C) {
+	file := c.MkDir() + "/activity.json"
+
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	fs, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	for _, store := range []Store{NewMemoryStore(), fs} {
+		c.Assert(store.Add(nil), Equals, ErrNilSnapshot)
+		c.Assert(store.Add(&Snapshot{Time: now}), IsNil)
+		c.Assert(store.Add(&Snapshot{Time: now.AddDate(0, 0, -2)}), IsNil)
+		c.Assert(store.Add(&Snapshot{Time: now.AddDate(0, 0, -1)}), IsNil)
+
+		snapshots, err := store.List(now.AddDate(0, 0, -1), now)
+		c.Assert(err, IsNil)
+		c.Assert(snapshots, HasLen, 2)
+		c.Assert(snapshots[0].Time, Equals, now.AddDate(0, 0, -1))
+
+		c.Assert(store.Prune(now.AddDate(0, 0, -1)), IsNil)
+		c.Assert(store.Prune(now.AddDate(0, 0, -1)), IsNil)
+
+		snapshots, err = store.List(time.Time{}, now)
+		c.Assert(err, IsNil)
+		c.Assert(snapshots, HasLen, 2)
+	}
+
+	fs, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	snapshots, err := fs.List(time.Time{}, now)
+	c.Assert(err, IsNil)
+	c.Assert(snapshots, HasLen, 2)
+
+	os.WriteFile(file, []byte("{"), 0600)
+	_, err = NewFileStore(file)
+	c.Assert(err, NotNil)
+
+	var ms *MemoryStore
+	var nfs *FileStore
+
+	for _, store := range []Store{ms, nfs} {
+		c.Assert(store.Add(&Snapshot{}), Equals, ErrNilStore)
+		_, err = store.List(time.Time{}, now)
+		c.Assert(err, Equals, ErrNilStore)
+		c.Assert(store.Prune(now), Equals, ErrNilStore)
+	}
+}
+
+func (s *ActivitySuite) TestNil(c *C) {
+	var t *Tracker
+
+	_, err := t.Snapshot(now)
+	c.Assert(err, Equals, ErrNilTracker)
+	_, err = t.DailyActive(now, now)
+	c.Assert(err, Equals, ErrNilTracker)
+	_, err = t.Report(1, now)
+	c.Assert(err, Equals, ErrNilTracker)
+	c.Assert(t.Run(time.Second, nil), Equals, ErrNilTracker)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.users == nil {
+		return nil, errors.New("users error")
+	}
+
+	return c.users, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func testUsers() pachca.Users {
+	created := pachca.Date{Time: now.AddDate(-1, 0, 0)}
+
+	return pachca.Users{
+		{
+			ID: 1, Role: pachca.ROLE_ADMIN, InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt: created, LastActivityAt: pachca.Date{Time: now.AddDate(0, 0, -1)},
+		},
+		{
+			ID: 2, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt: created, LastActivityAt: pachca.Date{Time: now.AddDate(0, 0, -5)},
+		},
+		{
+			ID: 3, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt: created, LastActivityAt: pachca.Date{Time: now.AddDate(0, -3, 0)},
+		},
+		{
+			ID: 4, Role: pachca.ROLE_GUEST, InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt: created,
+		},
+		{
+			ID: 5, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt: pachca.Date{Time: now.AddDate(0, 0, -1)},
+		},
+		{
+			ID: 6, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+			IsSuspended: true, CreatedAt: created,
+		},
+		{
+			ID: 7, IsBot: true, InviteStatus: pachca.INVITE_CONFIRMED, CreatedAt: created,
+		},
+	}
+}
+
+func userIDs(users pachca.Users) []uint {
+	var result []uint
+
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+
+	return result
+}
@@ -0,0 +1,265 @@
+package avatar
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "image/gif"
+
+	"github.com/essentialkaos/ek/v14/errors"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	FORMAT_JPEG = "jpeg"
+	FORMAT_PNG  = "png"
+	FORMAT_GIF  = "gif"
+)
+
+const (
+	// DEFAULT_MAX_SIZE is default maximum width and height of avatar
+	DEFAULT_MAX_SIZE = 512
+
+	// MIN_SIZE is minimum width and height of image
+	MIN_SIZE = 32
+
+	// MAX_DATA_SIZE is maximum size of image data
+	MAX_DATA_SIZE = 20 * 1024 * 1024
+
+	// MAX_PIXELS is maximum number of pixels in image
+	MAX_PIXELS = 50_000_000
+
+	// JPEG_QUALITY is quality of re-encoded JPEG images
+	JPEG_QUALITY = 90
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used for updating avatars
+type Client interface {
+	UpdateUserAvatarFromReader(userID uint, name string, r io.Reader) (string, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Image contains prepared avatar image
+type Image struct {
+	Data   []byte // Image data
+	Format string // Image format (jpeg, png or gif)
+	Size   int    // Image width and height
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient         = errors.New("client is nil")
+	ErrNilReader         = errors.New("reader is nil")
+	ErrNilImage          = errors.New("image is nil")
+	ErrEmptyData         = errors.New("image data is empty")
+	ErrInvalidUserID     = errors.New("user ID must be greater than 0")
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooLarge          = fmt.Errorf("image data size exceeds the limit (%d bytes)", MAX_DATA_SIZE)
+	ErrTooManyPixels     = fmt.Errorf("image has too many pixels (more than %d)", MAX_PIXELS)
+	ErrTooSmall          = fmt.Errorf("image is too small (less than %dx%d)", MIN_SIZE, MIN_SIZE)
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Prepare validates image data, crops image to square around its centre and
+// downscales it if it's larger than maxSize (DEFAULT_MAX_SIZE is used if
+// maxSize is less or equal to 0). Square images which fit into maxSize are
+// kept as is. Other JPEG images are re-encoded as JPEG, PNG and GIF images
+// are re-encoded as PNG.
+func Prepare(data []byte, maxSize int) (*Image, error) {
+	switch {
+	case len(data) == 0:
+		return nil, ErrEmptyData
+	case len(data) > MAX_DATA_SIZE:
+		return nil, ErrTooLarge
+	}
+
+	if maxSize <= 0 {
+		maxSize = DEFAULT_MAX_SIZE
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrUnsupportedFormat
+		}
+
+		return nil, fmt.Errorf("can't decode image: %w", err)
+	}
+
+	switch {
+	case format != FORMAT_JPEG && format != FORMAT_PNG && format != FORMAT_GIF:
+		return nil, ErrUnsupportedFormat
+	case cfg.Width*cfg.Height > MAX_PIXELS:
+		return nil, ErrTooManyPixels
+	case min(cfg.Width, cfg.Height) < MIN_SIZE:
+		return nil, ErrTooSmall
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't decode image: %w", err)
+	}
+
+	if cfg.Width == cfg.Height && cfg.Width <= maxSize {
+		return &Image{Data: data, Format: format, Size: cfg.Width}, nil
+	}
+
+	img = resize(crop(img), maxSize)
+
+	var buf bytes.Buffer
+
+	if format == FORMAT_JPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEG_QUALITY})
+	} else {
+		format = FORMAT_PNG
+		err = png.Encode(&buf, img)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't encode image: %w", err)
+	}
+
+	return &Image{
+		Data:   buf.Bytes(),
+		Format: format,
+		Size:   img.Bounds().Dx(),
+	}, nil
+}
+
+// Read reads image data from given reader and prepares it for upload
+func Read(r io.Reader, maxSize int) (*Image, error) {
+	if r == nil {
+		return nil, ErrNilReader
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MAX_DATA_SIZE+1))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read image data: %w", err)
+	}
+
+	return Prepare(data, maxSize)
+}
+
+// Update prepares image and sets it as avatar of user with given ID
+func Update(client Client, userID uint, data []byte, maxSize int) (string, error) {
+	switch {
+	case client == nil:
+		return "", ErrNilClient
+	case userID == 0:
+		return "", ErrInvalidUserID
+	}
+
+	img, err := Prepare(data, maxSize)
+
+	if err != nil {
+		return "", err
+	}
+
+	return img.Upload(client, userID)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns file name for image
+func (i *Image) Name() string {
+	switch {
+	case i == nil:
+		return ""
+	case i.Format == FORMAT_JPEG:
+		return "avatar.jpg"
+	}
+
+	return "avatar." + i.Format
+}
+
+// Upload sets image as avatar of user with given ID
+func (i *Image) Upload(client Client, userID uint) (string, error) {
+	switch {
+	case i == nil:
+		return "", ErrNilImage
+	case client == nil:
+		return "", ErrNilClient
+	case userID == 0:
+		return "", ErrInvalidUserID
+	}
+
+	return client.UpdateUserAvatarFromReader(userID, i.Name(), bytes.NewReader(i.Data))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// crop crops image to square around its centre
+func crop(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	size := min(b.Dx(), b.Dy())
+	offset := image.Pt(b.Min.X+(b.Dx()-size)/2, b.Min.Y+(b.Dy()-size)/2)
+
+	result := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(result, result.Bounds(), img, offset, draw.Src)
+
+	return result
+}
+
+// resize downscales square image to given size using box filter
+func resize(img *image.RGBA, size int) *image.RGBA {
+	n := img.Bounds().Dx()
+
+	if n <= size {
+		return img
+	}
+
+	result := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := range size {
+		y0, y1 := y*n/size, (y+1)*n/size
+
+		for x := range size {
+			x0, x1 := x*n/size, (x+1)*n/size
+
+			var r, g, b, a uint
+			count := uint((y1 - y0) * (x1 - x0))
+
+			for sy := y0; sy < y1; sy++ {
+				i := img.PixOffset(x0, sy)
+
+				for sx := x0; sx < x1; sx++ {
+					r += uint(img.Pix[i])
+					g += uint(img.Pix[i+1])
+					b += uint(img.Pix[i+2])
+					a += uint(img.Pix[i+3])
+					i += 4
+				}
+			}
+
+			i := result.PixOffset(x, y)
+
+			result.Pix[i] = uint8(r / count)
+			result.Pix[i+1] = uint8(g / count)
+			result.Pix[i+2] = uint8(b / count)
+			result.Pix[i+3] = uint8(a / count)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,334 @@
+package avatar
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type AvatarSuite struct{}
+
+type fakeClient struct {
+	users    pachca.Users
+	uploads  map[uint]*upload
+	failUser uint
+}
+
+type upload struct {
+	name string
+	data []byte
+}
+
+type errReader struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&AvatarSuite{})
+
+var _ SyncClient = (*pachca.Client)(nil)
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *AvatarSuite) TestPrepare(c *C) {
+	_, err := Prepare(nil, 0)
+	c.Assert(err, Equals, ErrEmptyData)
+	_, err = Prepare(make([]byte, MAX_DATA_SIZE+1), 0)
+	c.Assert(err, Equals, ErrTooLarge)
+	_, err = Prepare([]byte("not an image"), 0)
+	c.Assert(err, Equals, ErrUnsupportedFormat)
+
+	data := makeImage(c, FORMAT_PNG, 64, 64)
+
+	_, err = Prepare(data[:40], 0)
+	c.Assert(err, ErrorMatches, `can't decode image: .*`)
+	_, err = Prepare(data[:len(data)-20], 0)
+	c.Assert(err, ErrorMatches, `can't decode image: .*`)
+	_, err = Prepare(makeImage(c, FORMAT_PNG, 64, 16), 0)
+	c.Assert(err, Equals, ErrTooSmall)
+	_, err = Prepare(resizeHeader(data, 10000, 10000), 0)
+	c.Assert(err, Equals, ErrTooManyPixels)
+
+	// Square image which fits into max size is kept as is
+	img, err := Prepare(data, 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Format, Equals, FORMAT_PNG)
+	c.Assert(img.Size, Equals, 64)
+	c.Assert(img.Data, DeepEquals, data)
+	c.Assert(img.Name(), Equals, "avatar.png")
+
+	// Only green centre is kept after cropping
+	img, err = Prepare(makeImage(c, FORMAT_PNG, 200, 100), 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Size, Equals, 100)
+	checkColor(c, img, green)
+
+	img, err = Prepare(makeImage(c, FORMAT_PNG, 100, 200), 40)
+	c.Assert(err, IsNil)
+	c.Assert(img.Size, Equals, 40)
+	checkColor(c, img, green)
+
+	img, err = Prepare(makeImage(c, FORMAT_JPEG, 1000, 600), 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Format, Equals, FORMAT_JPEG)
+	c.Assert(img.Size, Equals, DEFAULT_MAX_SIZE)
+	c.Assert(img.Name(), Equals, "avatar.jpg")
+
+	img, err = Prepare(makeImage(c, FORMAT_GIF, 100, 80), 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Format, Equals, FORMAT_PNG)
+	c.Assert(img.Size, Equals, 80)
+}
+
+func (s *AvatarSuite) TestResize(c *C) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	for y := range 4 {
+		for x := range 4 {
+			if (x+y)%2 == 0 {
+				src.Set(x, y, color.RGBA{200, 100, 0, 255})
+			}
+		}
+	}
+
+	dst := resize(src, 2)
+	c.Assert(dst.Bounds().Dx(), Equals, 2)
+	c.Assert(dst.RGBAAt(1, 1), Equals, color.RGBA{100, 50, 0, 127})
+	c.Assert(resize(src, 8), Equals, src)
+}
+
+func (s *AvatarSuite) TestUpdate(c *C) {
+	client := newFakeClient()
+	data := makeImage(c, FORMAT_PNG, 64, 64)
+
+	_, err := Read(nil, 0)
+	c.Assert(err, Equals, ErrNilReader)
+	_, err = Read(errReader{}, 0)
+	c.Assert(err, ErrorMatches, `can't read image data: read error`)
+
+	img, err := Read(bytes.NewReader(data), 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Size, Equals, 64)
+
+	url, err := Update(client, 1, data, 0)
+	c.Assert(err, IsNil)
+	c.Assert(url, Equals, "https://pachca.com/avatar.png")
+	c.Assert(client.uploads[1].name, Equals, "avatar.png")
+	c.Assert(client.uploads[1].data, DeepEquals, data)
+
+	_, err = Update(nil, 1, data, 0)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = Update(client, 0, data, 0)
+	c.Assert(err, Equals, ErrInvalidUserID)
+	_, err = Update(client, 1, nil, 0)
+	c.Assert(err, Equals, ErrEmptyData)
+
+	_, err = img.Upload(nil, 1)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = img.Upload(client, 0)
+	c.Assert(err, Equals, ErrInvalidUserID)
+
+	img = nil
+
+	c.Assert(img.Name(), Equals, "")
+	_, err = img.Upload(client, 1)
+	c.Assert(err, Equals, ErrNilImage)
+}
+
+func (s *AvatarSuite) TestSync(c *C) {
+	dir := c.MkDir()
+	client := newFakeClient()
+	client.users = pachca.Users{
+		{ID: 1, Email: "john@domain.com"},
+		{ID: 2, Email: "Jane@Domain.com", ImageURL: "https://pachca.com/jane.png"},
+		{ID: 3, Email: "bob@domain.com", IsSuspended: true},
+		{ID: 4, Email: "alice@domain.com"},
+		{ID: 5, Email: "eve@domain.com"},
+	}
+
+	os.WriteFile(dir+"/john@domain.com.png", makeImage(c, FORMAT_PNG, 64, 64), 0644)
+	os.WriteFile(dir+"/jane@domain.com.JPG", makeImage(c, FORMAT_JPEG, 128, 64), 0644)
+	os.WriteFile(dir+"/bob@domain.com.gif", makeImage(c, FORMAT_GIF, 64, 64), 0644)
+	os.WriteFile(dir+"/ghost@domain.com.png", makeImage(c, FORMAT_PNG, 64, 64), 0644)
+	os.WriteFile(dir+"/alice@domain.com.png", []byte("broken"), 0644)
+	os.WriteFile(dir+"/eve@domain.com.png", makeImage(c, FORMAT_PNG, 64, 64), 0644)
+	os.WriteFile(dir+"/avatar.png", makeImage(c, FORMAT_PNG, 64, 64), 0644)
+	os.WriteFile(dir+"/readme@domain.com.txt", []byte("test"), 0644)
+	os.Mkdir(dir+"/dir@domain.com.png", 0755)
+
+	_, err := NewSyncer(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	sc, err := NewSyncer(client)
+	c.Assert(err, IsNil)
+
+	client.failUser = 5
+
+	report, err := sc.SyncDir(dir)
+	c.Assert(err, ErrorMatches, `(?s)can't update avatar of user 4 from ".*/alice@domain.com.png": unsupported image format.*can't update avatar of user 5 from ".*/eve@domain.com.png": upload error`)
+	c.Assert(report.Updated, DeepEquals, []uint{1})
+	c.Assert(report.Skipped, DeepEquals, []uint{3, 2})
+	c.Assert(report.Unknown, DeepEquals, []string{"ghost@domain.com.png"})
+
+	sc.Overwrite = true
+	sc.MaxSize = 32
+	client.failUser = 0
+
+	os.Remove(dir + "/alice@domain.com.png")
+
+	report, err = sc.SyncDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(report.Updated, DeepEquals, []uint{5, 2, 1})
+	c.Assert(client.uploads[2].name, Equals, "avatar.jpg")
+
+	img, err := Prepare(client.uploads[1].data, 0)
+	c.Assert(err, IsNil)
+	c.Assert(img.Size, Equals, 32)
+
+	_, err = sc.SyncDir("")
+	c.Assert(err, Equals, ErrEmptyDir)
+	_, err = sc.SyncDir(dir + "/_unknown_")
+	c.Assert(err, ErrorMatches, `can't read directory ".*": .*`)
+
+	client.users = nil
+
+	_, err = sc.SyncDir(dir)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+
+	sc = nil
+
+	_, err = sc.SyncDir(dir)
+	c.Assert(err, Equals, ErrNilSyncer)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{uploads: map[uint]*upload{}}
+}
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.users == nil {
+		return nil, errors.New("users error")
+	}
+
+	return c.users, nil
+}
+
+func (c *fakeClient) UpdateUserAvatarFromReader(userID uint, name string, r io.Reader) (string, error) {
+	if userID == c.failUser {
+		return "", errors.New("upload error")
+	}
+
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return "", err
+	}
+
+	c.uploads[userID] = &upload{name: name, data: data}
+
+	return "https://pachca.com/" + name, nil
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// makeImage creates image with red, green and blue stripes along its longest side
+func makeImage(c *C, format string, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	side, size := max(w, h), min(w, h)
+
+	for y := range h {
+		for x := range w {
+			pos := x
+
+			if h > w {
+				pos = y
+			}
+
+			switch {
+			case pos < (side-size)/2:
+				img.Set(x, y, red)
+			case pos >= (side+size)/2:
+				img.Set(x, y, blue)
+			default:
+				img.Set(x, y, green)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case FORMAT_JPEG:
+		err = jpeg.Encode(&buf, img, nil)
+	case FORMAT_GIF:
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = png.Encode(&buf, img)
+	}
+
+	c.Assert(err, IsNil)
+
+	return buf.Bytes()
+}
+
+// resizeHeader changes image size in PNG header
+func resizeHeader(data []byte, w, h uint32) []byte {
+	data = bytes.Clone(data)
+
+	binary.BigEndian.PutUint32(data[16:], w)
+	binary.BigEndian.PutUint32(data[20:], h)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	return data
+}
+
+// checkColor checks that all image pixels have given color
+func checkColor(c *C, img *Image, clr color.RGBA) {
+	i, _, err := image.Decode(bytes.NewReader(img.Data))
+	c.Assert(err, IsNil)
+
+	b := i.Bounds()
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c.Assert(color.RGBAModel.Convert(i.At(x, y)), Equals, clr)
+		}
+	}
+}
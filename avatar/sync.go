@@ -0,0 +1,192 @@
+package avatar
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SyncClient is the subset of Pachca API client methods used for syncing
+// avatars
+type SyncClient interface {
+	Client
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Syncer sets avatars of users from directory with images named by users
+// emails (e.g. "john@domain.com.jpg")
+type Syncer struct {
+	// MaxSize is maximum width and height of avatar (512 by default)
+	MaxSize int
+
+	// Overwrite enables replacing of existing avatars
+	Overwrite bool
+
+	client SyncClient
+}
+
+// SyncReport contains info about sync results
+type SyncReport struct {
+	Updated []uint   // Users with updated avatar
+	Skipped []uint   // Users who already have avatar or suspended
+	Unknown []string // Files without matching user
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilSyncer = errors.New("syncer is nil")
+	ErrEmptyDir  = errors.New("directory path is empty")
+)
+
+// imageExts contains supported image file extensions
+var imageExts = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewSyncer creates new avatars syncer
+func NewSyncer(client SyncClient) (*Syncer, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Syncer{client: client}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SyncDir sets avatars of users from images in given directory. Files which
+// names aren't emails or have unsupported extensions are ignored.
+func (s *Syncer) SyncDir(dir string) (*SyncReport, error) {
+	switch {
+	case s == nil:
+		return nil, ErrNilSyncer
+	case dir == "":
+		return nil, ErrEmptyDir
+	}
+
+	files, err := readDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	report := &SyncReport{}
+	errs := errors.NewBundle()
+
+	for _, email := range slices.Sorted(maps.Keys(files)) {
+		file := files[email]
+		user := findUser(users, email)
+
+		switch {
+		case user == nil:
+			report.Unknown = append(report.Unknown, filepath.Base(file))
+			continue
+		case user.IsSuspended, user.HasAvatar() && !s.Overwrite:
+			report.Skipped = append(report.Skipped, user.ID)
+			continue
+		}
+
+		err = s.update(user.ID, file)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't update avatar of user %d from %q: %w", user.ID, file, err))
+			continue
+		}
+
+		report.Updated = append(report.Updated, user.ID)
+	}
+
+	if !errs.IsEmpty() {
+		return report, errs.Join()
+	}
+
+	return report, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// update reads image from file and sets it as avatar of user
+func (s *Syncer) update(userID uint, file string) error {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	img, err := Read(fd, s.MaxSize)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = img.Upload(s.client, userID)
+
+	return err
+}
+
+// readDir returns map email → image file for all images in directory
+func readDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read directory %q: %w", dir, err)
+	}
+
+	result := map[string]string{}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		email := strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+
+		if !slices.Contains(imageExts, ext) || !strings.Contains(email, "@") {
+			continue
+		}
+
+		result[email] = filepath.Join(dir, name)
+	}
+
+	return result, nil
+}
+
+// findUser returns user with given email
+func findUser(users pachca.Users, email string) *pachca.User {
+	for _, user := range users {
+		if user != nil && strings.EqualFold(user.Email, email) {
+			return user
+		}
+	}
+
+	return nil
+}
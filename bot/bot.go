@@ -0,0 +1,323 @@
+package bot
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilWebhook     = errors.New("bot webhook configuration is nil")
+	ErrEmptyTemplate  = errors.New("bot webhook has no template")
+	ErrEmptyEngine    = errors.New("bot webhook template engine is not set")
+	ErrUnknownEngine  = errors.New("unknown template engine")
+	ErrInvalidPayload = errors.New("payload must be an object")
+	ErrTooManySteps   = errors.New("template rendering takes too many steps")
+	ErrValueTooBig    = errors.New("template output or value is too big")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Render renders bot outgoing webhook template with given payload. Payload can
+// be any webhook struct (e.g. webhook.Message), map or raw JSON data. Template
+// variables are named after payload JSON fields.
+func Render(wh *pachca.BotWebhook, payload any) (string, error) {
+	switch {
+	case wh == nil:
+		return "", ErrNilWebhook
+	case wh.Template == "":
+		return "", ErrEmptyTemplate
+	}
+
+	return RenderTemplate(wh.TemplateEngine, wh.Template, payload)
+}
+
+// Validate checks syntax of bot outgoing webhook template. It returns nil if
+// webhook has no template.
+func Validate(wh *pachca.BotWebhook) error {
+	switch {
+	case wh == nil:
+		return ErrNilWebhook
+	case wh.Template == "":
+		return nil
+	}
+
+	return ValidateTemplate(wh.TemplateEngine, wh.Template)
+}
+
+// RenderTemplate renders template using given engine. Rendering of Liquid
+// templates is limited by MAX_RENDER_STEPS and MAX_VALUE_SIZE.
+func RenderTemplate(engine, template string, payload any) (string, error) {
+	data, err := toData(payload)
+
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	switch engine {
+	case "":
+		return "", ErrEmptyEngine
+
+	case pachca.BOT_TEMPLATE_LIQUID:
+		nodes, err := parseLiquid(template)
+
+		if err != nil {
+			return "", fmt.Errorf("invalid liquid template: %w", err)
+		}
+
+		env := &liquidEnv{scopes: []map[string]any{{}, data}}
+		err = renderLiquid(&sb, nodes, env)
+
+		if err != nil {
+			return "", fmt.Errorf("can't render liquid template: %w", err)
+		}
+
+	case pachca.BOT_TEMPLATE_MUSTACHE:
+		nodes, err := parseMustache(template)
+
+		if err != nil {
+			return "", fmt.Errorf("invalid mustache template: %w", err)
+		}
+
+		renderMustache(&sb, nodes, []any{data})
+
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownEngine, engine)
+	}
+
+	return sb.String(), nil
+}
+
+// ValidateTemplate checks syntax of template for given engine
+func ValidateTemplate(engine, template string) error {
+	var err error
+
+	switch engine {
+	case "":
+		return ErrEmptyEngine
+	case pachca.BOT_TEMPLATE_LIQUID:
+		_, err = parseLiquid(template)
+	case pachca.BOT_TEMPLATE_MUSTACHE:
+		_, err = parseMustache(template)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownEngine, engine)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s template: %w", engine, err)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// toData converts payload to generic JSON data
+func toData(payload any) (map[string]any, error) {
+	var raw []byte
+	var err error
+
+	switch t := payload.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return t, nil
+	case []byte:
+		raw = t
+	case json.RawMessage:
+		raw = t
+	case string:
+		raw = []byte(t)
+	default:
+		raw, err = json.Marshal(payload)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't encode payload: %w", err)
+		}
+	}
+
+	var data map[string]any
+
+	err = json.Unmarshal(raw, &data)
+
+	if err != nil || data == nil {
+		return nil, ErrInvalidPayload
+	}
+
+	return data, nil
+}
+
+// getField returns field of given value (map key, slice index or special
+// properties size/first/last)
+func getField(v any, name string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if vv, ok := t[name]; ok {
+			return vv
+		}
+
+		if name == "size" {
+			return float64(len(t))
+		}
+
+	case []any:
+		switch name {
+		case "size":
+			return float64(len(t))
+		case "first":
+			if len(t) > 0 {
+				return t[0]
+			}
+		case "last":
+			if len(t) > 0 {
+				return t[len(t)-1]
+			}
+		default:
+			i, err := strconv.Atoi(name)
+
+			if err == nil && i < 0 {
+				i += len(t)
+			}
+
+			if err == nil && i >= 0 && i < len(t) {
+				return t[i]
+			}
+		}
+
+	case string:
+		if name == "size" {
+			return float64(len([]rune(t)))
+		}
+	}
+
+	return nil
+}
+
+// toString converts value to string
+func toString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(t)
+	case []any:
+		var sb strings.Builder
+
+		for _, item := range t {
+			sb.WriteString(toString(item))
+		}
+
+		return sb.String()
+	case emptyValue:
+		return ""
+	}
+
+	return toJSON(v)
+}
+
+// toJSON encodes value to JSON
+func toJSON(v any) string {
+	if _, ok := v.(emptyValue); ok {
+		v = ""
+	}
+
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
+// toFloat converts value to float
+func toFloat(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case int:
+		return float64(t)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f
+	case bool:
+		if t {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// isNumber returns true if value is a number
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int:
+		return true
+	}
+
+	return false
+}
+
+// isEqual returns true if values are equal
+func isEqual(a, b any) bool {
+	if isNumber(a) && isNumber(b) {
+		return toFloat(a) == toFloat(b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// isEmpty returns true if value is empty
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+
+	return false
+}
+
+// sortedKeys returns sorted map keys
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// getLine returns line number for given position in source
+func getLine(src string, pos int) int {
+	return strings.Count(src[:pos], "\n") + 1
+}
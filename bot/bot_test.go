@@ -0,0 +1,262 @@
+package bot
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type BotSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&BotSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *BotSuite) TestRender(c *C) {
+	msg := &webhook.Message{
+		Basic:     webhook.Basic{Type: webhook.TYPE_MESSAGE},
+		MessageID: 12,
+		Event:     webhook.EVENT_NEW,
+		Content:   "Hello <world>",
+		UserID:    3,
+		Links:     []*webhook.UnfurlLink{{URL: "https://a.com", Domain: "a.com"}},
+	}
+
+	wh := &pachca.BotWebhook{
+		Template:       `{"text":"{{ content | upcase }}","id":{{ id }}}`,
+		TemplateEngine: pachca.BOT_TEMPLATE_LIQUID,
+	}
+
+	c.Assert(Validate(wh), IsNil)
+
+	out, err := Render(wh, msg)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `{"text":"HELLO <WORLD>","id":12}`)
+
+	wh = &pachca.BotWebhook{
+		Template:       `{{type}}: {{content}} / {{{content}}}{{#links}} [{{domain}}]{{/links}}{{^thread}} no thread{{/thread}}`,
+		TemplateEngine: pachca.BOT_TEMPLATE_MUSTACHE,
+	}
+
+	c.Assert(Validate(wh), IsNil)
+
+	out, err = Render(wh, msg)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `message: Hello &lt;world&gt; / Hello <world> [a.com] no thread`)
+
+	_, err = Render(nil, msg)
+	c.Assert(err, Equals, ErrNilWebhook)
+	_, err = Render(&pachca.BotWebhook{}, msg)
+	c.Assert(err, Equals, ErrEmptyTemplate)
+	_, err = Render(&pachca.BotWebhook{Template: "test"}, msg)
+	c.Assert(err, Equals, ErrEmptyEngine)
+	_, err = Render(&pachca.BotWebhook{Template: "test", TemplateEngine: "erb"}, msg)
+	c.Assert(err, ErrorMatches, `unknown template engine "erb"`)
+	_, err = RenderTemplate("liquid", "test", 1)
+	c.Assert(err, Equals, ErrInvalidPayload)
+	_, err = RenderTemplate("liquid", "{{ a", nil)
+	c.Assert(err, NotNil)
+	_, err = RenderTemplate("mustache", "{{#a}}", nil)
+	c.Assert(err, NotNil)
+
+	c.Assert(Validate(nil), Equals, ErrNilWebhook)
+	c.Assert(Validate(&pachca.BotWebhook{}), IsNil)
+	c.Assert(ValidateTemplate("", "a"), Equals, ErrEmptyEngine)
+	c.Assert(ValidateTemplate("erb", "a"), NotNil)
+}
+
+func (s *BotSuite) TestPayloads(c *C) {
+	for _, p := range []any{
+		`{"a":1}`, []byte(`{"a":1}`), map[string]any{"a": 1}, struct {
+			A int `json:"a"`
+		}{1},
+	} {
+		out, err := RenderTemplate("mustache", "{{a}}", p)
+		c.Assert(err, IsNil)
+		c.Assert(out, Equals, "1")
+	}
+
+	out, err := RenderTemplate("mustache", "{{a}}", nil)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "")
+
+	_, err = RenderTemplate("mustache", "{{a}}", make(chan int))
+	c.Assert(err, NotNil)
+}
+
+func (s *BotSuite) TestLiquid(c *C) {
+	data := `{
+		"user": {"name": "John", "tags": ["a","b","c"], "age": 33},
+		"items": [{"n":"x","v":1},{"n":"y","v":2},{"n":"z","v":3}],
+		"nothing": "",
+		"ts": 1767268800
+	}`
+
+	cases := map[string]string{
+		`{{ user.name }}`:                                                                "John",
+		`{{ user["name"] | downcase }}`:                                                  "john",
+		`{{ user.tags[1] }}{{ user.tags.size }}`:                                         "b3",
+		`{{ user.tags | join: "," }}`:                                                    "a,b,c",
+		`{{ user.tags.first }}-{{ user.tags | last }}`:                                   "a-c",
+		`{{ missing | default: "none" }}`:                                                "none",
+		`{{ nothing | default: "none" }}`:                                                "none",
+		`{{ "hello world" | capitalize | append: "!" }}`:                                 "Hello world!",
+		`{{ "a-b-c" | split: "-" | size }}`:                                              "3",
+		`{{ "abcdefgh" | truncate: 5 }}`:                                                 "ab...",
+		`{{ "abcdefgh" | truncate: 5, "" }}`:                                             "abcde",
+		`{{ "<b>x</b>" | strip_html }}|{{ "<b>" | escape }}`:                             "x|&lt;b&gt;",
+		`{{ "  x  " | strip }}|{{ " x" | lstrip }}|{{ "x " | rstrip }}`:                  "x|x|x",
+		`{{ "aXbX" | replace: "X", "-" }}{{ "aXbX" | remove: "X" }}`:                     "a-b-ab",
+		`{{ "a b" | url_encode }}{{ "b" | prepend: "a" }}`:                               "a+bab",
+		`{{ user.age | plus: 2 | minus: 1 | times: 2 | divided_by: 4 }}`:                 "17",
+		`{{ user | json }}`:                                                              `{"age":33,"name":"John","tags":["a","b","c"]}`,
+		`{{ ts | date: "%Y-%m-%d %H:%M:%S %b %a %j %% %y %e %I %p %B %A %s %Z %z %Q" }}`: "2026-01-01 12:00:00 Jan Thu 001 % 26  1 12 PM January Thursday 1767268800 UTC +0000 %Q",
+		`{{ "2026-01-01T12:00:00Z" | date: "%d.%m.%Y" }}{{ "bad" | date: "%Y" }}{{ user | date: "%Y" | size }}`:        "01.01.2026bad3",
+		`{% if user.age > 30 and user.name == "John" %}yes{% else %}no{% endif %}`:                                     "yes",
+		`{% if user.age < 30 or user.name != "John" %}yes{% elsif user.age >= 33 %}elsif{% endif %}`:                   "elsif",
+		`{% if user.age <= 30 %}yes{% else %}no{% endif %}`:                                                            "no",
+		`{% unless user.tags contains "x" %}no x{% endunless %}`:                                                       "no x",
+		`{% if user.name contains "oh" %}oh{% endif %}{% if user contains "age" %}age{% endif %}`:                      "ohage",
+		`{% if nothing == empty %}e{% endif %}{% if user.tags != empty %}ne{% endif %}{% if "a" < "b" %}lt{% endif %}`: "enelt",
+		`{% if missing %}a{% elsif false %}b{% elsif nil %}c{% else %}d{% endif %}`:                                    "d",
+		`{% for i in items %}{{ forloop.index }}:{{ i.n }}{% unless forloop.last %},{% endunless %}{% endfor %}`:       "1:x,2:y,3:z",
+		`{% for i in items reversed limit:2 offset:1 %}{{ i.v }}{% endfor %}`:                                          "32",
+		`{% for i in (1..3) %}{{ i }}{% endfor %}`:                                                                     "123",
+		`{% for i in missing %}x{% else %}empty{% endfor %}`:                                                           "empty",
+		`{% assign n = user.name | upcase %}{{ n }}`:                                                                   "JOHN",
+		`{% capture greeting %}Hi, {{ user.name }}{% endcapture %}{{ greeting }}!`:                                     "Hi, John!",
+		`{% case user.name %}{% when "Bob" %}bob{% when "Ann", "John" %}john{% else %}other{% endcase %}`:              "john",
+		`{% case user.age %}{% when 1 or 2 %}small{% else %}big{% endcase %}`:                                          "big",
+		`a {%- comment %} ignored {% endcomment -%} b`:                                                                 "ab",
+		`{% raw %}{{ user.name }}{% endraw %}`:                                                                         "{{ user.name }}",
+		"{{- user.name -}}  \n {{ true }}{{ nil }}{{ -1.5 }}":                                                          "Johntrue-1.5",
+		`{{ user.tags }}{{ user.tags[-1] }}{{ user.name.size }}{{ user.size }}`:                                        "abcc43",
+	}
+
+	for tmpl, exp := range cases {
+		out, err := RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, tmpl, data)
+		c.Assert(err, IsNil, Commentf("Template: %s", tmpl))
+		c.Assert(out, Equals, exp, Commentf("Template: %s", tmpl))
+	}
+
+	errs := []string{
+		`{{ a`, `{% if a %}`, `{% endif %}`, `{% unknown %}`, `{{ a | unknown }}`,
+		`{{ a | }}`, `{{ }}`, `{{ "a }}`, `{{ a $ b }}`, `{% if %}{% endif %}`,
+		`{% if a = b %}{% endif %}`, `{% if a b %}{% endif %}`, `{% if a and %}{% endif %}`,
+		`{% for a %}{% endfor %}`, `{% for a in b c %}{% endfor %}`, `{% assign = 1 %}`,
+		`{% capture %}{% endcapture %}`, `{% raw %}`, `{% for i in (1..) %}{% endfor %}`,
+		`{% unless a %}{% elsif b %}{% endunless %}`, `{{ a[0 }}`, `{% case a %}{% when %}{% endcase %}`,
+		`{{ a | append: }}`, `{% for a in b limit: %}{% endfor %}`, `{% for i in (1 2) %}{% endfor %}`,
+		`{% for i in (1..2 %}{% endfor %}`, `{% if a %}{% elsif %}{% endif %}`, `{% case %}{% endcase %}`,
+		`{% for i in a %}{% else %}{% endif %}`, `{% assign a = %}`, `{% if a == %}{% endif %}`,
+	}
+
+	for _, tmpl := range errs {
+		c.Assert(ValidateTemplate(pachca.BOT_TEMPLATE_LIQUID, tmpl), NotNil, Commentf("Template: %s", tmpl))
+	}
+
+	err := ValidateTemplate(pachca.BOT_TEMPLATE_LIQUID, "line1\n{% if a %}\n{% endunless %}")
+	c.Assert(err, ErrorMatches, `invalid liquid template: .*`)
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{{ 1 | divided_by: 0 }}`, nil)
+	c.Assert(err, NotNil)
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{{ 1 | append }}`, nil)
+	c.Assert(err, NotNil)
+
+	out, err := RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{% for i in (1..5000) %}{% endfor %}{{ (3..1) | size }}`, nil)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "0")
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{% for i in (1..5001) %}{% endfor %}`, nil)
+	c.Assert(err, ErrorMatches, `.*range is too big \(maximum is 5000 items\)`)
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{% for i in (0..n) %}{% endfor %}`, `{"n":1e300}`)
+	c.Assert(err, NotNil)
+}
+
+func (s *BotSuite) TestLiquidLimits(c *C) {
+	_, err := RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% for a in (1..4999) %}{% for b in (1..4999) %}x{% endfor %}{% endfor %}`, nil,
+	)
+	c.Assert(err, ErrorMatches, `.*`+ErrTooManySteps.Error())
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% assign s = "x" %}{% for i in (1..100) %}{% assign s = s | append: s %}{% endfor %}{{ s | size }}`, nil,
+	)
+	c.Assert(err, ErrorMatches, `.*`+ErrValueTooBig.Error())
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% assign s = "x" %}{% for i in (1..100) %}{% assign s = s | prepend: s %}{% endfor %}`, nil,
+	)
+	c.Assert(err, ErrorMatches, `.*`+ErrValueTooBig.Error())
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% assign s = "xxxxxxxxxxxxxxxx" %}{% for i in (1..10) %}{% assign s = s | replace: "x", "xxxx" %}{% endfor %}`, nil,
+	)
+	c.Assert(err, ErrorMatches, `.*`+ErrValueTooBig.Error())
+
+	data := map[string]any{"s": strings.Repeat("x", 200_000)}
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% capture c %}{% for i in (1..10) %}{{ s }}{% endfor %}{% endcapture %}`, data,
+	)
+	c.Assert(err, ErrorMatches, `.*`+ErrValueTooBig.Error())
+
+	_, err = RenderTemplate(pachca.BOT_TEMPLATE_LIQUID, `{% for i in (1..10) %}{{ s }}{% endfor %}`, data)
+	c.Assert(err, ErrorMatches, `.*`+ErrValueTooBig.Error())
+
+	out, err := RenderTemplate(pachca.BOT_TEMPLATE_LIQUID,
+		`{% for a in (1..100) %}{% for b in (1..100) %}x{% endfor %}{% endfor %}`, nil,
+	)
+	c.Assert(err, IsNil)
+	c.Assert(out, HasLen, 10000)
+}
+
+func (s *BotSuite) TestMustache(c *C) {
+	data := `{"name":"John","html":"<b>","list":[{"v":1},{"v":2}],"nums":[1,2],"obj":{"a":{"b":"deep"}},"f":false,"e":[]}`
+
+	cases := map[string]string{
+		`{{name}} {{ name }}`:                 "John John",
+		`{{html}} {{{html}}} {{& html}}`:      "&lt;b&gt; <b> <b>",
+		`{{#list}}{{v}}{{/list}}`:             "12",
+		`{{#nums}}{{.}},{{/nums}}`:            "1,2,",
+		`{{obj.a.b}} {{#obj}}{{a.b}}{{/obj}}`: "deep deep",
+		`{{#f}}x{{/f}}{{^f}}not f{{/f}}`:      "not f",
+		`{{#e}}x{{/e}}{{^e}}empty{{/e}}`:      "empty",
+		`{{#name}}has {{name}}{{/name}}`:      "has John",
+		`{{! comment }}{{missing}}`:           "",
+		`{{=<% %>=}}<% name %> {{name}}`:      "John {{name}}",
+		`{{#list}}{{name}}{{/list}}`:          "JohnJohn",
+		`{{obj}}`:                             `{&#34;a&#34;:{&#34;b&#34;:&#34;deep&#34;}}`,
+	}
+
+	for tmpl, exp := range cases {
+		out, err := RenderTemplate(pachca.BOT_TEMPLATE_MUSTACHE, tmpl, data)
+		c.Assert(err, IsNil, Commentf("Template: %s", tmpl))
+		c.Assert(out, Equals, exp, Commentf("Template: %s", tmpl))
+	}
+
+	errs := []string{
+		`{{name`, `{{#a}}`, `{{/a}}`, `{{#a}}{{/b}}`, `{{> partial}}`, `{{=<%=}}`,
+		`{{}}`, `{{#}}{{/}}`, `{{{}}}`,
+	}
+
+	for _, tmpl := range errs {
+		c.Assert(ValidateTemplate(pachca.BOT_TEMPLATE_MUSTACHE, tmpl), NotNil, Commentf("Template: %s", tmpl))
+	}
+}
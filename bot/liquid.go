@@ -0,0 +1,1653 @@
+package bot
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	// MAX_RANGE_SIZE is maximum number of items in range expression (e.g. (1..10))
+	MAX_RANGE_SIZE = 5000
+
+	// MAX_RENDER_STEPS is maximum number of rendered nodes and loop iterations
+	// per render
+	MAX_RENDER_STEPS = 100_000
+
+	// MAX_VALUE_SIZE is maximum size of rendered output, captured text and
+	// string values in bytes
+	MAX_VALUE_SIZE = 1024 * 1024 // 1 MB
+)
+
+const (
+	liquidText liquidKind = iota
+	liquidOutput
+	liquidIf
+	liquidFor
+	liquidAssign
+	liquidCapture
+	liquidCase
+)
+
+const (
+	tokText liquidTokenKind = iota
+	tokOutput
+	tokTag
+)
+
+const (
+	lexString lexKind = iota
+	lexNumber
+	lexIdent
+	lexOp
+	lexPunct
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// liquidKind is type of liquid node
+type liquidKind uint8
+
+// liquidTokenKind is type of template token
+type liquidTokenKind uint8
+
+// lexKind is type of expression lexeme
+type lexKind uint8
+
+// liquidToken is template token (text, output or tag)
+type liquidToken struct {
+	kind    liquidTokenKind
+	content string
+	line    int
+}
+
+// liquidNode is node of parsed liquid template
+type liquidNode struct {
+	kind      liquidKind
+	text      string
+	name      string
+	expr      *liquidExpr
+	branches  []*liquidBranch
+	nodes     []*liquidNode
+	elseNodes []*liquidNode
+	limit     *liquidExpr
+	offset    *liquidExpr
+	reversed  bool
+}
+
+// liquidBranch is conditional branch (if/elsif/else/when)
+type liquidBranch struct {
+	cond   *liquidCond // nil for else branch
+	nodes  []*liquidNode
+	negate bool // true for unless
+}
+
+// liquidCond is condition with comparisons joined by and/or
+type liquidCond struct {
+	left  *liquidExpr
+	op    string
+	right *liquidExpr
+	join  string // "and" or "or"
+	next  *liquidCond
+}
+
+// liquidExpr is expression (operand with filters)
+type liquidExpr struct {
+	literal any
+	path    []string
+	rangeTo *liquidExpr
+	isLit   bool
+	filters []*liquidFilter
+}
+
+// liquidFilter is filter call
+type liquidFilter struct {
+	name string
+	args []*liquidExpr
+}
+
+// lexeme is expression lexeme
+type lexeme struct {
+	kind lexKind
+	text string
+}
+
+// liquidEnv is rendering environment
+type liquidEnv struct {
+	scopes []map[string]any
+	steps  int
+}
+
+// liquidFilterFunc is filter function
+type liquidFilterFunc func(v any, args []any) (any, error)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// liquidFilters contains supported filters
+var liquidFilters map[string]liquidFilterFunc
+
+// strftimeRegex is regex for strftime directives
+var strftimeRegex = regexp.MustCompile(`%[a-zA-Z%]`)
+
+// htmlTagRegex is regex for HTML tags
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func init() {
+	liquidFilters = map[string]liquidFilterFunc{
+		"append":     filterAppend,
+		"prepend":    filterPrepend,
+		"capitalize": filterCapitalize,
+		"downcase":   filterDowncase,
+		"upcase":     filterUpcase,
+		"strip":      filterStrip,
+		"lstrip":     filterLStrip,
+		"rstrip":     filterRStrip,
+		"size":       filterSize,
+		"default":    filterDefault,
+		"replace":    filterReplace,
+		"remove":     filterRemove,
+		"truncate":   filterTruncate,
+		"split":      filterSplit,
+		"join":       filterJoin,
+		"first":      filterFirst,
+		"last":       filterLast,
+		"escape":     filterEscape,
+		"url_encode": filterURLEncode,
+		"strip_html": filterStripHTML,
+		"json":       filterJSON,
+		"date":       filterDate,
+		"plus":       filterPlus,
+		"minus":      filterMinus,
+		"times":      filterTimes,
+		"divided_by": filterDividedBy,
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseLiquid parses liquid template
+func parseLiquid(src string) ([]*liquidNode, error) {
+	tokens, err := tokenizeLiquid(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, pos, stop, err := parseLiquidNodes(tokens, 0, nil)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case stop != "":
+		return nil, fmt.Errorf("line %d: unexpected tag %q", tokens[pos].line, stop)
+	}
+
+	return nodes, nil
+}
+
+// renderLiquid renders parsed liquid template
+func renderLiquid(sb *strings.Builder, nodes []*liquidNode, env *liquidEnv) error {
+	for _, n := range nodes {
+		err := env.step()
+
+		if err != nil {
+			return err
+		}
+
+		switch n.kind {
+		case liquidText:
+			sb.WriteString(n.text)
+
+		case liquidOutput:
+			v, err := n.expr.eval(env)
+
+			if err != nil {
+				return err
+			}
+
+			sb.WriteString(toString(v))
+
+		case liquidAssign:
+			v, err := n.expr.eval(env)
+
+			if err != nil {
+				return err
+			}
+
+			env.scopes[0][n.name] = v
+
+		case liquidCapture:
+			var buf strings.Builder
+
+			err := renderLiquid(&buf, n.nodes, env)
+
+			if err != nil {
+				return err
+			}
+
+			env.scopes[0][n.name] = buf.String()
+
+		case liquidIf, liquidCase:
+			for _, b := range n.branches {
+				ok := true
+
+				if b.cond != nil {
+					var err error
+
+					ok, err = b.cond.eval(env)
+
+					if err != nil {
+						return err
+					}
+
+					ok = ok != b.negate
+				}
+
+				if ok {
+					err := renderLiquid(sb, b.nodes, env)
+
+					if err != nil {
+						return err
+					}
+
+					break
+				}
+			}
+
+		case liquidFor:
+			err := renderLiquidFor(sb, n, env)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if sb.Len() > MAX_VALUE_SIZE {
+			return ErrValueTooBig
+		}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// tokenizeLiquid splits template into tokens
+func tokenizeLiquid(src string) ([]*liquidToken, error) {
+	var tokens []*liquidToken
+
+	pos, trimNext := 0, false
+
+	for pos < len(src) {
+		start := indexLiquidTag(src[pos:])
+		text := src[pos:]
+
+		if start != -1 {
+			text = src[pos : pos+start]
+		}
+
+		if trimNext {
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+			trimNext = false
+		}
+
+		if start == -1 {
+			if text != "" {
+				tokens = append(tokens, &liquidToken{kind: tokText, content: text})
+			}
+
+			break
+		}
+
+		tagPos := pos + start
+		isOutput := src[tagPos+1] == '{'
+		closing := "%}"
+
+		if isOutput {
+			closing = "}}"
+		}
+
+		end := strings.Index(src[tagPos+2:], closing)
+
+		if end == -1 {
+			return nil, fmt.Errorf("line %d: unclosed tag", getLine(src, tagPos))
+		}
+
+		content := src[tagPos+2 : tagPos+2+end]
+		pos = tagPos + 2 + end + 2
+
+		if strings.HasPrefix(content, "-") {
+			content = content[1:]
+			text = strings.TrimRightFunc(text, unicode.IsSpace)
+		}
+
+		if strings.HasSuffix(content, "-") {
+			content = content[:len(content)-1]
+			trimNext = true
+		}
+
+		if text != "" {
+			tokens = append(tokens, &liquidToken{kind: tokText, content: text})
+		}
+
+		token := &liquidToken{
+			kind:    tokTag,
+			content: strings.TrimSpace(content),
+			line:    getLine(src, tagPos),
+		}
+
+		if isOutput {
+			token.kind = tokOutput
+		}
+
+		if token.kind == tokTag && (token.content == "raw" || token.content == "comment") {
+			endTag := "end" + token.content
+			re := regexp.MustCompile(`\{%-?\s*` + endTag + `\s*-?%\}`)
+			loc := re.FindStringIndex(src[pos:])
+
+			if loc == nil {
+				return nil, fmt.Errorf("line %d: %q is not closed", token.line, token.content)
+			}
+
+			endText := src[pos+loc[0] : pos+loc[1]]
+
+			if token.content == "raw" && loc[0] > 0 {
+				raw := src[pos : pos+loc[0]]
+
+				if strings.HasPrefix(endText, "{%-") {
+					raw = strings.TrimRightFunc(raw, unicode.IsSpace)
+				}
+
+				tokens = append(tokens, &liquidToken{kind: tokText, content: raw})
+			}
+
+			pos += loc[1]
+			trimNext = strings.HasSuffix(endText, "-%}")
+
+			continue
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// indexLiquidTag returns index of the next output or tag
+func indexLiquidTag(src string) int {
+	o, t := strings.Index(src, "{{"), strings.Index(src, "{%")
+
+	switch {
+	case o == -1:
+		return t
+	case t == -1:
+		return o
+	}
+
+	return min(o, t)
+}
+
+// parseLiquidNodes parses tokens until one of stop tags
+func parseLiquidNodes(tokens []*liquidToken, pos int, stops []string) ([]*liquidNode, int, string, error) {
+	var nodes []*liquidNode
+
+	for pos < len(tokens) {
+		t := tokens[pos]
+
+		switch t.kind {
+		case tokText:
+			nodes = append(nodes, &liquidNode{kind: liquidText, text: t.content})
+			pos++
+			continue
+
+		case tokOutput:
+			expr, err := parseLiquidExpr(t.content)
+
+			if err != nil {
+				return nil, pos, "", fmt.Errorf("line %d: %w", t.line, err)
+			}
+
+			nodes = append(nodes, &liquidNode{kind: liquidOutput, expr: expr})
+			pos++
+			continue
+		}
+
+		name, args, _ := strings.Cut(t.content, " ")
+		args = strings.TrimSpace(args)
+
+		if slices.Contains(stops, name) {
+			return nodes, pos, name, nil
+		}
+
+		var node *liquidNode
+		var err error
+
+		switch name {
+		case "if", "unless":
+			node, pos, err = parseLiquidIf(tokens, pos, name == "unless")
+		case "case":
+			node, pos, err = parseLiquidCase(tokens, pos)
+		case "for":
+			node, pos, err = parseLiquidFor(tokens, pos)
+		case "capture":
+			node, pos, err = parseLiquidCapture(tokens, pos)
+		case "assign":
+			node, err = parseLiquidAssign(args)
+			pos++
+		default:
+			return nil, pos, "", fmt.Errorf("line %d: unknown tag %q", t.line, name)
+		}
+
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), "line ") {
+				err = fmt.Errorf("line %d: %w", t.line, err)
+			}
+
+			return nil, pos, "", err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(stops) != 0 {
+		return nil, pos, "", fmt.Errorf("%q is not closed", stops[len(stops)-1])
+	}
+
+	return nodes, pos, "", nil
+}
+
+// parseLiquidIf parses if/unless tag
+func parseLiquidIf(tokens []*liquidToken, pos int, unless bool) (*liquidNode, int, error) {
+	endTag := "endif"
+
+	if unless {
+		endTag = "endunless"
+	}
+
+	node := &liquidNode{kind: liquidIf}
+	_, args, _ := strings.Cut(tokens[pos].content, " ")
+	cond, err := parseLiquidCond(args)
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	negate := unless
+	pos++
+
+	for {
+		nodes, next, stop, err := parseLiquidNodes(tokens, pos, []string{"elsif", "else", endTag})
+
+		if err != nil {
+			return nil, next, err
+		}
+
+		node.branches = append(node.branches, &liquidBranch{cond: cond, nodes: nodes, negate: negate})
+		pos, negate = next+1, false
+
+		switch stop {
+		case endTag:
+			return node, pos, nil
+		case "else":
+			cond = nil
+		case "elsif":
+			if unless {
+				return nil, pos, fmt.Errorf("line %d: elsif is not allowed in unless", tokens[next].line)
+			}
+
+			_, args, _ := strings.Cut(tokens[next].content, " ")
+			cond, err = parseLiquidCond(args)
+
+			if err != nil {
+				return nil, pos, fmt.Errorf("line %d: %w", tokens[next].line, err)
+			}
+		}
+	}
+}
+
+// parseLiquidCase parses case tag
+func parseLiquidCase(tokens []*liquidToken, pos int) (*liquidNode, int, error) {
+	_, args, _ := strings.Cut(tokens[pos].content, " ")
+	subject, err := parseLiquidExpr(args)
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	node := &liquidNode{kind: liquidCase}
+	_, pos, stop, err := parseLiquidNodes(tokens, pos+1, []string{"when", "else", "endcase"})
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	for stop != "endcase" {
+		var cond *liquidCond
+
+		if stop == "when" {
+			_, args, _ := strings.Cut(tokens[pos].content, " ")
+
+			for v := range strings.SplitSeq(strings.ReplaceAll(args, " or ", ","), ",") {
+				val, err := parseLiquidExpr(v)
+
+				if err != nil {
+					return nil, pos, fmt.Errorf("line %d: %w", tokens[pos].line, err)
+				}
+
+				cond = &liquidCond{left: subject, op: "==", right: val, join: "or", next: cond}
+			}
+		}
+
+		nodes, next, nextStop, err := parseLiquidNodes(tokens, pos+1, []string{"when", "else", "endcase"})
+
+		if err != nil {
+			return nil, next, err
+		}
+
+		node.branches = append(node.branches, &liquidBranch{cond: cond, nodes: nodes})
+		pos, stop = next, nextStop
+	}
+
+	return node, pos + 1, nil
+}
+
+// parseLiquidFor parses for tag
+func parseLiquidFor(tokens []*liquidToken, pos int) (*liquidNode, int, error) {
+	_, args, _ := strings.Cut(tokens[pos].content, " ")
+	lex, err := lexLiquid(args)
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	if len(lex) < 3 || lex[0].kind != lexIdent || lex[1].text != "in" {
+		return nil, pos, fmt.Errorf("invalid for syntax %q", args)
+	}
+
+	node := &liquidNode{kind: liquidFor, name: lex[0].text}
+	expr, rest, err := parseLiquidOperand(lex[2:])
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	node.expr = expr
+
+	for len(rest) > 0 {
+		switch {
+		case rest[0].text == "reversed":
+			node.reversed = true
+			rest = rest[1:]
+		case (rest[0].text == "limit" || rest[0].text == "offset") &&
+			len(rest) > 2 && rest[1].text == ":":
+			v, r, err := parseLiquidOperand(rest[2:])
+
+			if err != nil {
+				return nil, pos, err
+			}
+
+			if rest[0].text == "limit" {
+				node.limit = v
+			} else {
+				node.offset = v
+			}
+
+			rest = r
+		default:
+			return nil, pos, fmt.Errorf("unexpected %q in for", rest[0].text)
+		}
+	}
+
+	nodes, next, stop, err := parseLiquidNodes(tokens, pos+1, []string{"else", "endfor"})
+
+	if err != nil {
+		return nil, next, err
+	}
+
+	node.nodes = nodes
+
+	if stop == "else" {
+		node.elseNodes, next, _, err = parseLiquidNodes(tokens, next+1, []string{"endfor"})
+
+		if err != nil {
+			return nil, next, err
+		}
+	}
+
+	return node, next + 1, nil
+}
+
+// parseLiquidCapture parses capture tag
+func parseLiquidCapture(tokens []*liquidToken, pos int) (*liquidNode, int, error) {
+	_, name, _ := strings.Cut(tokens[pos].content, " ")
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return nil, pos, fmt.Errorf("capture variable name is empty")
+	}
+
+	nodes, next, _, err := parseLiquidNodes(tokens, pos+1, []string{"endcapture"})
+
+	if err != nil {
+		return nil, next, err
+	}
+
+	return &liquidNode{kind: liquidCapture, name: name, nodes: nodes}, next + 1, nil
+}
+
+// parseLiquidAssign parses assign tag
+func parseLiquidAssign(args string) (*liquidNode, error) {
+	name, value, ok := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid assign syntax %q", args)
+	}
+
+	expr, err := parseLiquidExpr(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &liquidNode{kind: liquidAssign, name: name, expr: expr}, nil
+}
+
+// parseLiquidCond parses condition
+func parseLiquidCond(src string) (*liquidCond, error) {
+	lex, err := lexLiquid(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lex) == 0 {
+		return nil, fmt.Errorf("condition is empty")
+	}
+
+	var first, last *liquidCond
+
+	for len(lex) > 0 {
+		cond := &liquidCond{}
+
+		cond.left, lex, err = parseLiquidOperand(lex)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(lex) > 0 && (lex[0].kind == lexOp || lex[0].text == "contains") {
+			cond.op = lex[0].text
+			cond.right, lex, err = parseLiquidOperand(lex[1:])
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if first == nil {
+			first = cond
+		} else {
+			last.next = cond
+		}
+
+		last = cond
+
+		if len(lex) == 0 {
+			break
+		}
+
+		if lex[0].text != "and" && lex[0].text != "or" {
+			return nil, fmt.Errorf("unexpected %q in condition", lex[0].text)
+		}
+
+		cond.join = lex[0].text
+		lex = lex[1:]
+
+		if len(lex) == 0 {
+			return nil, fmt.Errorf("condition is incomplete")
+		}
+	}
+
+	return first, nil
+}
+
+// parseLiquidExpr parses expression with filters
+func parseLiquidExpr(src string) (*liquidExpr, error) {
+	lex, err := lexLiquid(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lex) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+
+	expr, lex, err := parseLiquidOperand(lex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for len(lex) > 0 {
+		if lex[0].text != "|" || len(lex) < 2 || lex[1].kind != lexIdent {
+			return nil, fmt.Errorf("unexpected %q in expression", lex[0].text)
+		}
+
+		f := &liquidFilter{name: lex[1].text}
+
+		if liquidFilters[f.name] == nil {
+			return nil, fmt.Errorf("unknown filter %q", f.name)
+		}
+
+		lex = lex[2:]
+
+		if len(lex) > 0 && lex[0].text == ":" {
+			lex = lex[1:]
+
+			for {
+				var arg *liquidExpr
+
+				arg, lex, err = parseLiquidOperand(lex)
+
+				if err != nil {
+					return nil, err
+				}
+
+				f.args = append(f.args, arg)
+
+				if len(lex) == 0 || lex[0].text != "," {
+					break
+				}
+
+				lex = lex[1:]
+			}
+		}
+
+		expr.filters = append(expr.filters, f)
+	}
+
+	return expr, nil
+}
+
+// parseLiquidOperand parses single operand (literal, variable or range)
+func parseLiquidOperand(lex []lexeme) (*liquidExpr, []lexeme, error) {
+	if len(lex) == 0 {
+		return nil, nil, fmt.Errorf("operand is missing")
+	}
+
+	l := lex[0]
+
+	switch l.kind {
+	case lexString:
+		return &liquidExpr{isLit: true, literal: l.text}, lex[1:], nil
+
+	case lexNumber:
+		f, err := strconv.ParseFloat(l.text, 64)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid number %q", l.text)
+		}
+
+		return &liquidExpr{isLit: true, literal: f}, lex[1:], nil
+
+	case lexIdent:
+		switch l.text {
+		case "true":
+			return &liquidExpr{isLit: true, literal: true}, lex[1:], nil
+		case "false":
+			return &liquidExpr{isLit: true, literal: false}, lex[1:], nil
+		case "nil", "null":
+			return &liquidExpr{isLit: true}, lex[1:], nil
+		case "empty", "blank":
+			return &liquidExpr{isLit: true, literal: emptyValue{}}, lex[1:], nil
+		}
+
+		path, err := parseLiquidPath(l.text)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &liquidExpr{path: path}, lex[1:], nil
+
+	case lexPunct:
+		if l.text != "(" {
+			break
+		}
+
+		from, rest, err := parseLiquidOperand(lex[1:])
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(rest) < 3 || rest[0].text != ".." {
+			return nil, nil, fmt.Errorf("invalid range")
+		}
+
+		to, rest, err := parseLiquidOperand(rest[1:])
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(rest) == 0 || rest[0].text != ")" {
+			return nil, nil, fmt.Errorf("invalid range")
+		}
+
+		from.rangeTo = to
+
+		return from, rest[1:], nil
+	}
+
+	return nil, nil, fmt.Errorf("unexpected %q", l.text)
+}
+
+// parseLiquidPath parses variable path (a.b[0]["c"])
+func parseLiquidPath(src string) ([]string, error) {
+	var path []string
+
+	for src != "" {
+		switch src[0] {
+		case '.':
+			src = src[1:]
+
+		case '[':
+			end := strings.IndexByte(src, ']')
+
+			if end == -1 {
+				return nil, fmt.Errorf("invalid variable %q", src)
+			}
+
+			key := strings.Trim(src[1:end], `"'`)
+			path = append(path, key)
+			src = src[end+1:]
+
+		default:
+			end := strings.IndexAny(src, ".[")
+
+			if end == -1 {
+				end = len(src)
+			}
+
+			path = append(path, src[:end])
+			src = src[end:]
+		}
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("variable name is empty")
+	}
+
+	return path, nil
+}
+
+// lexLiquid splits expression into lexemes
+func lexLiquid(src string) ([]lexeme, error) {
+	var result []lexeme
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed string literal")
+			}
+
+			result = append(result, lexeme{lexString, src[i+1 : i+1+end]})
+			i += end + 2
+
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i + 1
+
+			for j < len(src) && (isDigit(src[j]) || (src[j] == '.' && j+1 < len(src) && isDigit(src[j+1]))) {
+				j++
+			}
+
+			result = append(result, lexeme{lexNumber, src[i:j]})
+			i = j
+
+		case isIdentStart(c):
+			j := i
+
+			for j < len(src) {
+				switch {
+				case isIdentChar(src[j]):
+					j++
+				case src[j] == '.' && j+1 < len(src) && src[j+1] != '.':
+					j++
+				case src[j] == '[':
+					end := strings.IndexByte(src[j:], ']')
+
+					if end == -1 {
+						return nil, fmt.Errorf("unclosed bracket")
+					}
+
+					j += end + 1
+				default:
+					goto done
+				}
+			}
+		done:
+			result = append(result, lexeme{lexIdent, src[i:j]})
+			i = j
+
+		case strings.HasPrefix(src[i:], ".."):
+			result = append(result, lexeme{lexPunct, ".."})
+			i += 2
+
+		case strings.ContainsRune("=!<>", rune(c)):
+			op := src[i : i+1]
+
+			if i+1 < len(src) && strings.ContainsRune("=>", rune(src[i+1])) {
+				op = src[i : i+2]
+			}
+
+			switch op {
+			case "==", "!=", "<>", "<", ">", "<=", ">=":
+				result = append(result, lexeme{lexOp, op})
+			default:
+				return nil, fmt.Errorf("unknown operator %q", op)
+			}
+
+			i += len(op)
+
+		case strings.ContainsRune("|:,()", rune(c)):
+			result = append(result, lexeme{lexPunct, string(c)})
+			i++
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// emptyValue is special value for empty/blank literal
+type emptyValue struct{}
+
+// eval evaluates expression
+func (e *liquidExpr) eval(env *liquidEnv) (any, error) {
+	var v any
+
+	switch {
+	case e.rangeTo != nil:
+		from, err := (&liquidExpr{isLit: e.isLit, literal: e.literal, path: e.path}).eval(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := e.rangeTo.eval(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		start, end := math.Trunc(toFloat(from)), math.Trunc(toFloat(to))
+
+		switch {
+		case math.IsNaN(start), math.IsNaN(end), math.IsInf(start, 0), math.IsInf(end, 0):
+			return nil, fmt.Errorf("invalid range")
+		case end-start >= MAX_RANGE_SIZE:
+			return nil, fmt.Errorf("range is too big (maximum is %d items)", MAX_RANGE_SIZE)
+		}
+
+		var items []any
+
+		for i := start; i <= end; i++ {
+			items = append(items, i)
+		}
+
+		v = items
+
+	case e.isLit:
+		v = e.literal
+
+	default:
+		v = env.lookup(e.path)
+	}
+
+	for _, f := range e.filters {
+		args := make([]any, 0, len(f.args))
+
+		for _, a := range f.args {
+			av, err := a.eval(env)
+
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, av)
+		}
+
+		var err error
+
+		v, err = liquidFilters[f.name](v, args)
+
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", f.name, err)
+		}
+
+		if sv, ok := v.(string); ok && len(sv) > MAX_VALUE_SIZE {
+			return nil, fmt.Errorf("filter %q: %w", f.name, ErrValueTooBig)
+		}
+	}
+
+	return v, nil
+}
+
+// eval evaluates condition (right-to-left without precedence, like liquid does)
+func (c *liquidCond) eval(env *liquidEnv) (bool, error) {
+	left, err := c.left.eval(env)
+
+	if err != nil {
+		return false, err
+	}
+
+	var ok bool
+
+	if c.op == "" {
+		ok = isLiquidTruthy(left)
+	} else {
+		right, err := c.right.eval(env)
+
+		if err != nil {
+			return false, err
+		}
+
+		ok = compareLiquid(left, c.op, right)
+	}
+
+	if c.next == nil {
+		return ok, nil
+	}
+
+	if c.join == "and" && !ok {
+		return false, nil
+	}
+
+	if c.join == "or" && ok {
+		return true, nil
+	}
+
+	return c.next.eval(env)
+}
+
+// lookup looks up variable with given path
+func (e *liquidEnv) lookup(path []string) any {
+	var v any
+
+	found := false
+
+	for i := len(e.scopes) - 1; i >= 0; i-- {
+		if vv, ok := e.scopes[i][path[0]]; ok {
+			v, found = vv, true
+			break
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	for _, p := range path[1:] {
+		v = getField(v, p)
+	}
+
+	return v
+}
+
+// step counts rendering step and returns error if steps limit is exceeded
+func (e *liquidEnv) step() error {
+	e.steps++
+
+	if e.steps > MAX_RENDER_STEPS {
+		return ErrTooManySteps
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// renderLiquidFor renders for loop
+func renderLiquidFor(sb *strings.Builder, n *liquidNode, env *liquidEnv) error {
+	v, err := n.expr.eval(env)
+
+	if err != nil {
+		return err
+	}
+
+	var items []any
+
+	switch t := v.(type) {
+	case []any:
+		items = slices.Clone(t)
+	case map[string]any:
+		for _, k := range sortedKeys(t) {
+			items = append(items, []any{k, t[k]})
+		}
+	case string:
+		if t != "" {
+			items = []any{t}
+		}
+	}
+
+	if n.offset != nil {
+		o, err := n.offset.eval(env)
+
+		if err != nil {
+			return err
+		}
+
+		items = items[min(max(int(toFloat(o)), 0), len(items)):]
+	}
+
+	if n.limit != nil {
+		l, err := n.limit.eval(env)
+
+		if err != nil {
+			return err
+		}
+
+		items = items[:min(max(int(toFloat(l)), 0), len(items))]
+	}
+
+	if n.reversed {
+		slices.Reverse(items)
+	}
+
+	if len(items) == 0 {
+		return renderLiquid(sb, n.elseNodes, env)
+	}
+
+	scope := map[string]any{}
+	env.scopes = append(env.scopes, scope)
+
+	defer func() { env.scopes = env.scopes[:len(env.scopes)-1] }()
+
+	for i, item := range items {
+		err = env.step()
+
+		if err != nil {
+			return err
+		}
+
+		scope[n.name] = item
+		scope["forloop"] = map[string]any{
+			"index":   float64(i + 1),
+			"index0":  float64(i),
+			"rindex":  float64(len(items) - i),
+			"rindex0": float64(len(items) - i - 1),
+			"first":   i == 0,
+			"last":    i == len(items)-1,
+			"length":  float64(len(items)),
+		}
+
+		err = renderLiquid(sb, n.nodes, env)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLiquidTruthy returns true if value is truthy in terms of liquid
+// (only nil and false are falsy)
+func isLiquidTruthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	}
+
+	return true
+}
+
+// compareLiquid compares two values with given operator
+func compareLiquid(left any, op string, right any) bool {
+	if _, ok := right.(emptyValue); ok {
+		left, right = right, left
+	}
+
+	if _, ok := left.(emptyValue); ok {
+		empty := isEmpty(right)
+
+		switch op {
+		case "==":
+			return empty
+		case "!=", "<>":
+			return !empty
+		}
+
+		return false
+	}
+
+	switch op {
+	case "==":
+		return isEqual(left, right)
+	case "!=", "<>":
+		return !isEqual(left, right)
+	case "contains":
+		switch t := left.(type) {
+		case string:
+			return strings.Contains(t, toString(right))
+		case []any:
+			return slices.ContainsFunc(t, func(v any) bool { return isEqual(v, right) })
+		case map[string]any:
+			_, ok := t[toString(right)]
+			return ok
+		}
+
+		return false
+	}
+
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+
+	if lok && rok {
+		return compareOrdered(strings.Compare(ls, rs), op)
+	}
+
+	if !isNumber(left) || !isNumber(right) {
+		return false
+	}
+
+	lf, rf := toFloat(left), toFloat(right)
+
+	switch {
+	case lf < rf:
+		return compareOrdered(-1, op)
+	case lf > rf:
+		return compareOrdered(1, op)
+	}
+
+	return compareOrdered(0, op)
+}
+
+// compareOrdered checks comparison result against operator
+func compareOrdered(cmp int, op string) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case ">=":
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func filterAppend(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	s1, s2 := toString(v), toString(args[0])
+
+	if len(s1)+len(s2) > MAX_VALUE_SIZE {
+		return nil, ErrValueTooBig
+	}
+
+	return s1 + s2, nil
+}
+
+func filterPrepend(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	s1, s2 := toString(args[0]), toString(v)
+
+	if len(s1)+len(s2) > MAX_VALUE_SIZE {
+		return nil, ErrValueTooBig
+	}
+
+	return s1 + s2, nil
+}
+
+func filterCapitalize(v any, _ []any) (any, error) {
+	s := toString(v)
+
+	if s == "" {
+		return s, nil
+	}
+
+	r := []rune(s)
+
+	return string(unicode.ToUpper(r[0])) + strings.ToLower(string(r[1:])), nil
+}
+
+func filterDowncase(v any, _ []any) (any, error) {
+	return strings.ToLower(toString(v)), nil
+}
+
+func filterUpcase(v any, _ []any) (any, error) {
+	return strings.ToUpper(toString(v)), nil
+}
+
+func filterStrip(v any, _ []any) (any, error) {
+	return strings.TrimSpace(toString(v)), nil
+}
+
+func filterLStrip(v any, _ []any) (any, error) {
+	return strings.TrimLeftFunc(toString(v), unicode.IsSpace), nil
+}
+
+func filterRStrip(v any, _ []any) (any, error) {
+	return strings.TrimRightFunc(toString(v), unicode.IsSpace), nil
+}
+
+func filterSize(v any, _ []any) (any, error) {
+	switch t := v.(type) {
+	case []any:
+		return float64(len(t)), nil
+	case map[string]any:
+		return float64(len(t)), nil
+	case nil:
+		return float64(0), nil
+	}
+
+	return float64(len([]rune(toString(v)))), nil
+}
+
+func filterDefault(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	if !isLiquidTruthy(v) || isEmpty(v) {
+		return args[0], nil
+	}
+
+	return v, nil
+}
+
+func filterReplace(v any, args []any) (any, error) {
+	if len(args) != 2 {
+		return nil, errArgs(2)
+	}
+
+	s, from, to := toString(v), toString(args[0]), toString(args[1])
+
+	if len(to) > len(from) {
+		num := strings.Count(s, from)
+
+		if len(s)+num*(len(to)-len(from)) > MAX_VALUE_SIZE {
+			return nil, ErrValueTooBig
+		}
+	}
+
+	return strings.ReplaceAll(s, from, to), nil
+}
+
+func filterRemove(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	return strings.ReplaceAll(toString(v), toString(args[0]), ""), nil
+}
+
+func filterTruncate(v any, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errArgs(1)
+	}
+
+	s, ellipsis := []rune(toString(v)), "..."
+	size := int(toFloat(args[0]))
+
+	if len(args) == 2 {
+		ellipsis = toString(args[1])
+	}
+
+	if len(s) <= size {
+		return string(s), nil
+	}
+
+	size = max(size-len([]rune(ellipsis)), 0)
+
+	return string(s[:size]) + ellipsis, nil
+}
+
+func filterSplit(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	var result []any
+
+	for _, p := range strings.Split(toString(v), toString(args[0])) {
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+func filterJoin(v any, args []any) (any, error) {
+	sep := " "
+
+	if len(args) > 0 {
+		sep = toString(args[0])
+	}
+
+	items, ok := v.([]any)
+
+	if !ok {
+		return toString(v), nil
+	}
+
+	size := len(sep) * max(len(items)-1, 0)
+	result := make([]string, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, toString(item))
+		size += len(result[len(result)-1])
+	}
+
+	if size > MAX_VALUE_SIZE {
+		return nil, ErrValueTooBig
+	}
+
+	return strings.Join(result, sep), nil
+}
+
+func filterFirst(v any, _ []any) (any, error) {
+	return getField(v, "first"), nil
+}
+
+func filterLast(v any, _ []any) (any, error) {
+	return getField(v, "last"), nil
+}
+
+func filterEscape(v any, _ []any) (any, error) {
+	return html.EscapeString(toString(v)), nil
+}
+
+func filterURLEncode(v any, _ []any) (any, error) {
+	return url.QueryEscape(toString(v)), nil
+}
+
+func filterStripHTML(v any, _ []any) (any, error) {
+	return htmlTagRegex.ReplaceAllString(toString(v), ""), nil
+}
+
+func filterJSON(v any, _ []any) (any, error) {
+	return toJSON(v), nil
+}
+
+func filterDate(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	var t time.Time
+
+	switch tv := v.(type) {
+	case float64:
+		t = time.Unix(int64(tv), 0).UTC()
+	case string:
+		if tv == "now" || tv == "today" {
+			t = time.Now().UTC()
+			break
+		}
+
+		var err error
+
+		t, err = time.Parse(time.RFC3339Nano, tv)
+
+		if err != nil {
+			return v, nil
+		}
+	default:
+		return v, nil
+	}
+
+	return strftime(t, toString(args[0])), nil
+}
+
+func filterPlus(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	return toFloat(v) + toFloat(args[0]), nil
+}
+
+func filterMinus(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	return toFloat(v) - toFloat(args[0]), nil
+}
+
+func filterTimes(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	return toFloat(v) * toFloat(args[0]), nil
+}
+
+func filterDividedBy(v any, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, errArgs(1)
+	}
+
+	d := toFloat(args[0])
+
+	if d == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+
+	return math.Floor(toFloat(v) / d), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// strftime formats time using strftime directives
+func strftime(t time.Time, layout string) string {
+	return strftimeRegex.ReplaceAllStringFunc(layout, func(d string) string {
+		switch d[1] {
+		case 'Y':
+			return strconv.Itoa(t.Year())
+		case 'y':
+			return t.Format("06")
+		case 'm':
+			return t.Format("01")
+		case 'd':
+			return t.Format("02")
+		case 'e':
+			return fmt.Sprintf("%2d", t.Day())
+		case 'H':
+			return t.Format("15")
+		case 'I':
+			return t.Format("03")
+		case 'M':
+			return t.Format("04")
+		case 'S':
+			return t.Format("05")
+		case 'p':
+			return t.Format("PM")
+		case 'b':
+			return t.Format("Jan")
+		case 'B':
+			return t.Format("January")
+		case 'a':
+			return t.Format("Mon")
+		case 'A':
+			return t.Format("Monday")
+		case 'j':
+			return fmt.Sprintf("%03d", t.YearDay())
+		case 'Z':
+			return t.Format("MST")
+		case 'z':
+			return t.Format("-0700")
+		case 's':
+			return strconv.FormatInt(t.Unix(), 10)
+		case '%':
+			return "%"
+		}
+
+		return d
+	})
+}
+
+// errArgs returns error about wrong number of filter arguments
+func errArgs(num int) error {
+	return fmt.Errorf("filter requires %d argument(s)", num)
+}
+
+// isDigit returns true if given char is a digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentStart returns true if char can start identifier
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentChar returns true if char can be a part of identifier
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '-' || c == '?'
+}
@@ -0,0 +1,221 @@
+package bot
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	mustacheText mustacheKind = iota
+	mustacheVar
+	mustacheRaw
+	mustacheSection
+	mustacheInverted
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// mustacheKind is type of mustache node
+type mustacheKind uint8
+
+// mustacheNode is node of parsed mustache template
+type mustacheNode struct {
+	kind  mustacheKind
+	text  string
+	nodes []*mustacheNode
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseMustache parses mustache template
+func parseMustache(src string) ([]*mustacheNode, error) {
+	root := &mustacheNode{kind: mustacheSection}
+	stack := []*mustacheNode{root}
+	otag, ctag := "{{", "}}"
+	pos := 0
+
+	for pos < len(src) {
+		cur := stack[len(stack)-1]
+		start := strings.Index(src[pos:], otag)
+
+		if start == -1 {
+			cur.nodes = append(cur.nodes, &mustacheNode{kind: mustacheText, text: src[pos:]})
+			break
+		}
+
+		if start > 0 {
+			cur.nodes = append(cur.nodes, &mustacheNode{kind: mustacheText, text: src[pos : pos+start]})
+		}
+
+		tagPos := pos + start
+		pos = tagPos + len(otag)
+		closing := ctag
+
+		if otag == "{{" && strings.HasPrefix(src[pos:], "{") {
+			closing = "}" + ctag
+		}
+
+		end := strings.Index(src[pos:], closing)
+
+		if end == -1 {
+			return nil, fmt.Errorf("line %d: unclosed tag", getLine(src, tagPos))
+		}
+
+		tag := src[pos : pos+end]
+		pos += end + len(closing)
+
+		if tag == "" {
+			return nil, fmt.Errorf("line %d: empty tag", getLine(src, tagPos))
+		}
+
+		typ, name := tag[0], strings.TrimSpace(tag[1:])
+
+		switch typ {
+		case '!':
+			// comment
+		case '{', '&':
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty tag", getLine(src, tagPos))
+			}
+			cur.nodes = append(cur.nodes, &mustacheNode{kind: mustacheRaw, text: name})
+		case '#', '^':
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty section name", getLine(src, tagPos))
+			}
+			node := &mustacheNode{kind: mustacheSection, text: name}
+			if typ == '^' {
+				node.kind = mustacheInverted
+			}
+			cur.nodes = append(cur.nodes, node)
+			stack = append(stack, node)
+		case '/':
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected closing tag %q", getLine(src, tagPos), name)
+			}
+			if cur.text != name {
+				return nil, fmt.Errorf(
+					"line %d: section %q closed with %q",
+					getLine(src, tagPos), cur.text, name,
+				)
+			}
+			stack = stack[:len(stack)-1]
+		case '>':
+			return nil, fmt.Errorf("line %d: partials are not supported", getLine(src, tagPos))
+		case '=':
+			delims := strings.Fields(strings.TrimSuffix(name, "="))
+			if !strings.HasSuffix(name, "=") || len(delims) != 2 {
+				return nil, fmt.Errorf("line %d: invalid delimiters tag", getLine(src, tagPos))
+			}
+			otag, ctag = delims[0], delims[1]
+		default:
+			name = strings.TrimSpace(tag)
+			cur.nodes = append(cur.nodes, &mustacheNode{kind: mustacheVar, text: name})
+		}
+	}
+
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("unclosed section %q", stack[len(stack)-1].text)
+	}
+
+	return root.nodes, nil
+}
+
+// renderMustache renders parsed mustache template
+func renderMustache(sb *strings.Builder, nodes []*mustacheNode, stack []any) {
+	for _, n := range nodes {
+		switch n.kind {
+		case mustacheText:
+			sb.WriteString(n.text)
+
+		case mustacheVar:
+			sb.WriteString(html.EscapeString(toString(lookupMustache(stack, n.text))))
+
+		case mustacheRaw:
+			sb.WriteString(toString(lookupMustache(stack, n.text)))
+
+		case mustacheSection:
+			v := lookupMustache(stack, n.text)
+
+			if !isMustacheTruthy(v) {
+				continue
+			}
+
+			switch t := v.(type) {
+			case []any:
+				for _, item := range t {
+					renderMustache(sb, n.nodes, append(stack, item))
+				}
+			case map[string]any:
+				renderMustache(sb, n.nodes, append(stack, t))
+			default:
+				renderMustache(sb, n.nodes, stack)
+			}
+
+		case mustacheInverted:
+			if !isMustacheTruthy(lookupMustache(stack, n.text)) {
+				renderMustache(sb, n.nodes, stack)
+			}
+		}
+	}
+}
+
+// lookupMustache looks up value with given name in context stack
+func lookupMustache(stack []any, name string) any {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	if name == "." {
+		return stack[len(stack)-1]
+	}
+
+	parts := strings.Split(name, ".")
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		m, ok := stack[i].(map[string]any)
+
+		if !ok {
+			continue
+		}
+
+		v, ok := m[parts[0]]
+
+		if !ok {
+			continue
+		}
+
+		for _, p := range parts[1:] {
+			v = getField(v, p)
+		}
+
+		return v
+	}
+
+	return nil
+}
+
+// isMustacheTruthy returns true if value is truthy in terms of mustache
+func isMustacheTruthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []any:
+		return len(t) != 0
+	}
+
+	return true
+}
@@ -0,0 +1,421 @@
+package chatsync
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	EVENT_NEW EventType = iota + 1
+	EVENT_EDIT
+	EVENT_DELETE
+)
+
+// DEFAULT_LOOKBACK is default period which is re-checked for edits and deletions
+const DEFAULT_LOOKBACK = 24 * time.Hour
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by syncer
+type Client interface {
+	PaginateMessages(chatID uint, limit int, order pachca.SortOrder) *pachca.MessagePaginator
+}
+
+// Sink receives sync events
+type Sink interface {
+	// Write writes events for chat. Events are sorted by message creation date.
+	// If sink returns error, checkpoint is not updated and the same events will
+	// be emitted on next sync.
+	Write(chatID uint, events Events) error
+}
+
+// Store is storage for sync checkpoints
+type Store interface {
+	// Get returns checkpoint for given chat or nil if chat was never synced
+	Get(chatID uint) (*Checkpoint, error)
+
+	// Set saves checkpoint for given chat
+	Set(chatID uint, cp *Checkpoint) error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// EventType is type of sync event
+type EventType uint8
+
+// Event is sync event
+type Event struct {
+	Type    EventType
+	Message *pachca.Message
+}
+
+// Events is a slice of events
+type Events []*Event
+
+// Stats contains info about sync results
+type Stats struct {
+	New     int
+	Edited  int
+	Deleted int
+}
+
+// Checkpoint contains info about the latest sync of chat
+type Checkpoint struct {
+	LastMessageID uint      `json:"last_message_id"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	SyncedAt      time.Time `json:"synced_at"`
+}
+
+// Syncer fetches new and changed messages from chats
+type Syncer struct {
+	// Lookback is period before the latest synced message which is re-checked
+	// for edits and deletions (24 hours by default). Edits and deletions of
+	// older messages are not detected. Zero value disables re-checking.
+	Lookback time.Duration
+
+	// BatchSize is number of messages per page
+	BatchSize int
+
+	client Client
+	store  Store
+	sink   Sink
+}
+
+// MemoryStore is in-memory checkpoints store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[uint]*Checkpoint
+}
+
+// FileStore is checkpoints store which keeps data in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data map[string]*Checkpoint
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient     = errors.New("client is nil")
+	ErrNilStore      = errors.New("store is nil")
+	ErrNilSink       = errors.New("sink is nil")
+	ErrNilSyncer     = errors.New("syncer is nil")
+	ErrEmptyFilePath = errors.New("store file path is empty")
+	ErrInvalidChatID = errors.New("chat ID must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new syncer
+func New(client Client, store Store, sink Sink) (*Syncer, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	case sink == nil:
+		return nil, ErrNilSink
+	}
+
+	return &Syncer{
+		Lookback:  DEFAULT_LOOKBACK,
+		BatchSize: pachca.MAX_PER_PAGE,
+		client:    client,
+		store:     store,
+		sink:      sink,
+	}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[uint]*Checkpoint{}}
+}
+
+// NewFileStore creates new file store and loads checkpoints from given file
+// if it exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file, data: map[string]*Checkpoint{}}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Sync fetches messages created, edited or deleted since the previous sync of
+// chat and writes them to the sink
+func (s *Syncer) Sync(chatID uint) (*Stats, error) {
+	switch {
+	case s == nil:
+		return nil, ErrNilSyncer
+	case chatID == 0:
+		return nil, ErrInvalidChatID
+	}
+
+	cp, err := s.store.Get(chatID)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get checkpoint for chat %d: %w", chatID, err)
+	}
+
+	if cp == nil {
+		cp = &Checkpoint{}
+	}
+
+	// Start time is used as sync time, so changes made during the sync will be
+	// detected next time
+	syncedAt := time.Now()
+	events, stats, err := s.fetchEvents(chatID, cp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) != 0 {
+		err = s.sink.Write(chatID, events)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't write events for chat %d: %w", chatID, err)
+		}
+	}
+
+	for _, e := range events {
+		if e.Type == EVENT_NEW && e.Message.ID > cp.LastMessageID {
+			cp.LastMessageID = e.Message.ID
+			cp.LastMessageAt = e.Message.CreatedAt.Time
+		}
+	}
+
+	cp.SyncedAt = syncedAt
+	err = s.store.Set(chatID, cp)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save checkpoint for chat %d: %w", chatID, err)
+	}
+
+	return stats, nil
+}
+
+// SyncAll syncs all given chats. Sync continues if some of chats can't be
+// synced, all errors are returned at the end.
+func (s *Syncer) SyncAll(chatIDs ...uint) (*Stats, error) {
+	if s == nil {
+		return nil, ErrNilSyncer
+	}
+
+	total := &Stats{}
+	errs := errors.NewBundle()
+
+	for _, chatID := range chatIDs {
+		stats, err := s.Sync(chatID)
+
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+
+		total.New += stats.New
+		total.Edited += stats.Edited
+		total.Deleted += stats.Deleted
+	}
+
+	if !errs.IsEmpty() {
+		return total, errs.Join()
+	}
+
+	return total, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// String returns name of event type
+func (t EventType) String() string {
+	switch t {
+	case EVENT_NEW:
+		return "new"
+	case EVENT_EDIT:
+		return "edit"
+	case EVENT_DELETE:
+		return "delete"
+	}
+
+	return "unknown"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns checkpoint for given chat or nil if chat was never synced
+func (s *MemoryStore) Get(chatID uint) (*Checkpoint, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cp := s.data[chatID]
+
+	if cp == nil {
+		return nil, nil
+	}
+
+	cpCopy := *cp
+
+	return &cpCopy, nil
+}
+
+// Set saves checkpoint for given chat
+func (s *MemoryStore) Set(chatID uint, cp *Checkpoint) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	cpCopy := *cp
+
+	s.mu.Lock()
+	s.data[chatID] = &cpCopy
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns checkpoint for given chat or nil if chat was never synced
+func (s *FileStore) Get(chatID uint) (*Checkpoint, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp := s.data[getStoreKey(chatID)]
+
+	if cp == nil {
+		return nil, nil
+	}
+
+	cpCopy := *cp
+
+	return &cpCopy, nil
+}
+
+// Set saves checkpoint for given chat
+func (s *FileStore) Set(chatID uint, cp *Checkpoint) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cpCopy := *cp
+	s.data[getStoreKey(chatID)] = &cpCopy
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// fetchEvents fetches messages from newest to oldest until the checkpoint
+// (minus lookback period) is reached
+func (s *Syncer) fetchEvents(chatID uint, cp *Checkpoint) (Events, *Stats, error) {
+	var events Events
+
+	stats := &Stats{}
+	isFirstSync := cp.LastMessageID == 0
+	lookbackBorder := cp.LastMessageAt.Add(-s.Lookback)
+
+	paginator := s.client.PaginateMessages(
+		chatID, min(max(s.BatchSize, 1), pachca.MAX_PER_PAGE), pachca.SORT_ORDER_DESC,
+	)
+
+	var isDone bool
+
+	for messages := range paginator.Pages {
+		for _, msg := range messages {
+			if msg.ID > cp.LastMessageID {
+				// New message which was deleted before we saw it
+				if !msg.DeletedAt.IsZero() {
+					continue
+				}
+
+				events = append(events, &Event{EVENT_NEW, msg})
+				stats.New++
+				continue
+			}
+
+			if isFirstSync || msg.CreatedAt.Before(lookbackBorder) {
+				isDone = true
+				break
+			}
+
+			switch {
+			case !msg.DeletedAt.IsZero() && msg.DeletedAt.After(cp.SyncedAt):
+				events = append(events, &Event{EVENT_DELETE, msg})
+				stats.Deleted++
+			case !msg.ChangedAt.IsZero() && msg.ChangedAt.After(cp.SyncedAt):
+				events = append(events, &Event{EVENT_EDIT, msg})
+				stats.Edited++
+			}
+		}
+
+		if isDone {
+			break
+		}
+	}
+
+	if paginator.Error() != nil {
+		return nil, nil, fmt.Errorf("can't fetch messages of chat %d: %w", chatID, paginator.Error())
+	}
+
+	slices.Reverse(events)
+
+	return events, stats, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getStoreKey returns key for storing checkpoint
+func getStoreKey(chatID uint) string {
+	return strconv.FormatUint(uint64(chatID), 10)
+}
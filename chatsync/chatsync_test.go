@@ -0,0 +1,332 @@
+package chatsync
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const TOKEN = "YQlf-6Vce7jM1RMZZUs_iWKYPt24PeR4c7k_RwzqjI5"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ChatSyncSuite struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	messages pachca.Messages
+	requests int
+}
+
+type testSink struct {
+	events Events
+	fail   bool
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ChatSyncSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ChatSyncSuite) SetUpSuite(c *C) {
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		query := r.URL.Query()
+
+		if query.Get("chat_id") != "1" {
+			w.WriteHeader(404)
+			return
+		}
+
+		s.requests++
+
+		messages := slices.Clone(s.messages)
+
+		if query.Get("order") == "desc" {
+			slices.Reverse(messages)
+		}
+
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("cursor"))
+		end := min(offset+limit, len(messages))
+
+		resp := map[string]any{"data": messages[offset:end]}
+
+		if end < len(messages) {
+			resp["meta"] = map[string]any{
+				"paginate": map[string]any{"next_page": strconv.Itoa(end), "has_next": true},
+			}
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func (s *ChatSyncSuite) TearDownSuite(c *C) {
+	s.srv.Close()
+}
+
+func (s *ChatSyncSuite) SetUpTest(c *C) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	s.messages = pachca.Messages{}
+	s.requests = 0
+
+	for i := range 6 {
+		s.messages = append(s.messages, &pachca.Message{
+			ID:        uint(i + 1),
+			ChatID:    1,
+			Content:   fmt.Sprintf("Message %d", i+1),
+			CreatedAt: pachca.Date{Time: now.Add(time.Duration(i-6) * time.Hour)},
+		})
+	}
+}
+
+func (s *ChatSyncSuite) TestSync(c *C) {
+	sink := &testSink{}
+	store := NewMemoryStore()
+	syncer, err := New(newClient(s.srv), store, sink)
+	c.Assert(err, IsNil)
+
+	syncer.BatchSize = 2
+	syncer.Lookback = 150 * time.Minute
+
+	stats, err := syncer.Sync(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &Stats{New: 6})
+	c.Assert(sink.events, HasLen, 6)
+	c.Assert(sink.events[0].Message.ID, Equals, uint(1))
+	c.Assert(sink.events[5].Message.ID, Equals, uint(6))
+	c.Assert(s.requests, Equals, 3)
+
+	cp, err := store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(cp.LastMessageID, Equals, uint(6))
+	c.Assert(cp.SyncedAt.IsZero(), Equals, false)
+
+	// Nothing changed
+	sink.events, s.requests = nil, 0
+	stats, err = syncer.Sync(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &Stats{})
+	c.Assert(sink.events, HasLen, 0)
+	c.Assert(s.requests, Equals, 2)
+
+	later := pachca.Date{Time: time.Now().Add(time.Minute)}
+
+	s.mu.Lock()
+	s.messages[5].ChangedAt = later // edited, inside lookback window
+	s.messages[4].DeletedAt = later // deleted, inside lookback window
+	s.messages[0].ChangedAt = later // edited, outside lookback window
+	s.messages = append(s.messages, &pachca.Message{
+		ID: 7, ChatID: 1, CreatedAt: later,
+	}, &pachca.Message{
+		ID: 8, ChatID: 1, CreatedAt: later, DeletedAt: later,
+	})
+	s.mu.Unlock()
+
+	sink.events = nil
+	stats, err = syncer.Sync(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &Stats{New: 1, Edited: 1, Deleted: 1})
+	c.Assert(sink.events, HasLen, 3)
+	c.Assert(sink.events[0].Type, Equals, EVENT_DELETE)
+	c.Assert(sink.events[0].Message.ID, Equals, uint(5))
+	c.Assert(sink.events[1].Type, Equals, EVENT_EDIT)
+	c.Assert(sink.events[1].Message.ID, Equals, uint(6))
+	c.Assert(sink.events[2].Type, Equals, EVENT_NEW)
+	c.Assert(sink.events[2].Message.ID, Equals, uint(7))
+
+	cp, _ = store.Get(1)
+	c.Assert(cp.LastMessageID, Equals, uint(7))
+}
+
+func (s *ChatSyncSuite) TestSyncDefaultLookback(c *C) {
+	sink := &testSink{}
+	syncer, err := New(newClient(s.srv), NewMemoryStore(), sink)
+	c.Assert(err, IsNil)
+	c.Assert(syncer.Lookback, Equals, DEFAULT_LOOKBACK)
+
+	_, err = syncer.Sync(1)
+	c.Assert(err, IsNil)
+
+	later := pachca.Date{Time: time.Now().Add(time.Minute)}
+
+	s.mu.Lock()
+	s.messages[0].ChangedAt = later
+	s.messages[2].DeletedAt = later
+	s.mu.Unlock()
+
+	sink.events = nil
+	stats, err := syncer.Sync(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &Stats{Edited: 1, Deleted: 1})
+	c.Assert(sink.events, HasLen, 2)
+	c.Assert(sink.events[0].Type, Equals, EVENT_EDIT)
+	c.Assert(sink.events[0].Message.ID, Equals, uint(1))
+	c.Assert(sink.events[1].Type, Equals, EVENT_DELETE)
+	c.Assert(sink.events[1].Message.ID, Equals, uint(3))
+}
+
+func (s *ChatSyncSuite) TestSinkFailure(c *C) {
+	sink := &testSink{fail: true}
+	store := NewMemoryStore()
+	syncer, _ := New(newClient(s.srv), store, sink)
+
+	_, err := syncer.Sync(1)
+	c.Assert(err, ErrorMatches, "can't write events for chat 1: sink error")
+
+	cp, err := store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(cp, IsNil)
+
+	sink.fail = false
+
+	stats, err := syncer.Sync(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats.New, Equals, 6)
+}
+
+func (s *ChatSyncSuite) TestSyncAll(c *C) {
+	sink := &testSink{}
+	syncer, _ := New(newClient(s.srv), NewMemoryStore(), sink)
+
+	stats, err := syncer.SyncAll(1, 2, 0)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, `(?s)can't fetch messages of chat 2: .*chat ID must be greater than 0`)
+	c.Assert(stats.New, Equals, 6)
+
+	stats, err = syncer.SyncAll(1)
+	c.Assert(err, IsNil)
+	c.Assert(stats.New, Equals, 0)
+}
+
+func (s *ChatSyncSuite) TestFileStore(c *C) {
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	file := c.MkDir() + "/checkpoints.json"
+
+	store, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	cp, err := store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(cp, IsNil)
+
+	syncer, _ := New(newClient(s.srv), store, &testSink{})
+	_, err = syncer.Sync(1)
+	c.Assert(err, IsNil)
+
+	store, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	cp, err = store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(cp.LastMessageID, Equals, uint(6))
+
+	os.WriteFile(file, []byte("{broken"), 0600)
+	_, err = NewFileStore(file)
+	c.Assert(err, NotNil)
+
+	os.Chmod(file, 0)
+	_, err = NewFileStore(file)
+	c.Assert(err, NotNil)
+}
+
+func (s *ChatSyncSuite) TestErrors(c *C) {
+	client, sink, store := newClient(s.srv), &testSink{}, NewMemoryStore()
+
+	_, err := New(nil, store, sink)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(client, nil, sink)
+	c.Assert(err, Equals, ErrNilStore)
+	_, err = New(client, store, nil)
+	c.Assert(err, Equals, ErrNilSink)
+
+	syncer, _ := New(client, store, sink)
+	_, err = syncer.Sync(0)
+	c.Assert(err, Equals, ErrInvalidChatID)
+
+	var ns *Syncer
+	_, err = ns.Sync(1)
+	c.Assert(err, Equals, ErrNilSyncer)
+	_, err = ns.SyncAll(1)
+	c.Assert(err, Equals, ErrNilSyncer)
+
+	var nms *MemoryStore
+	_, err = nms.Get(1)
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nms.Set(1, &Checkpoint{}), Equals, ErrNilStore)
+
+	var nfs *FileStore
+	_, err = nfs.Get(1)
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nfs.Set(1, &Checkpoint{}), Equals, ErrNilStore)
+
+	c.Assert(EVENT_NEW.String(), Equals, "new")
+	c.Assert(EVENT_EDIT.String(), Equals, "edit")
+	c.Assert(EVENT_DELETE.String(), Equals, "delete")
+	c.Assert(EventType(0).String(), Equals, "unknown")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *testSink) Write(chatID uint, events Events) error {
+	if s.fail {
+		return fmt.Errorf("sink error")
+	}
+
+	s.events = append(s.events, events...)
+
+	return nil
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newClient(srv *httptest.Server) *pachca.Client {
+	client, _ := pachca.NewClient(TOKEN)
+	target, _ := url.Parse(srv.URL)
+
+	client.Engine().Client = &http.Client{Transport: &rewriteTransport{target}}
+
+	return client
+}
@@ -0,0 +1,622 @@
+package directory
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	ACTION_ADD ActionType = iota + 1
+	ACTION_EDIT
+	ACTION_SUSPEND
+	ACTION_UNSUSPEND
+)
+
+// DEFAULT_MAX_SUSPEND_RATIO is default maximum share of managed users which
+// can be suspended at once
+const DEFAULT_MAX_SUSPEND_RATIO = 0.1
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by reconciler
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+	GetProperties() (pachca.Properties, error)
+	AddUser(user *pachca.UserRequest) (*pachca.User, error)
+	EditUser(userID uint, user *pachca.UserRequest) (*pachca.User, error)
+	SuspendUser(userID uint) error
+	UnsuspendUser(userID uint) error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Reconciler syncs Pachca users with desired state
+type Reconciler struct {
+	// SuspendMissing enables suspending users which are not present in source
+	SuspendMissing bool
+
+	// ManageGuests enables suspending guests which are not present in source
+	ManageGuests bool
+
+	// MaxSuspendRatio is maximum share of managed users which can be suspended
+	// at once (0 means no limit)
+	MaxSuspendRatio float64
+
+	// MaxSuspend is maximum number of users which can be suspended at once
+	// (0 means no limit)
+	MaxSuspend int
+
+	// Protected is a list of emails of users which must never be modified
+	Protected []string
+
+	// SkipEmailNotify disables email notifications for new users
+	SkipEmailNotify bool
+
+	// DryRun enables mode when plan is created but not applied
+	DryRun bool
+
+	client Client
+}
+
+// ActionType is type of action
+type ActionType uint8
+
+// Action is single change of user
+type Action struct {
+	Type    ActionType
+	Email   string
+	User    *pachca.User        // Existing user (nil for new users)
+	Request *pachca.UserRequest // Request for AddUser or EditUser
+	Changes Changes
+}
+
+// Actions is a slice of actions
+type Actions []*Action
+
+// Change contains info about field change
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Changes is a slice of changes
+type Changes []Change
+
+// Plan contains actions required for reconciliation
+type Plan struct {
+	Actions Actions
+	Managed int // Number of active users managed by reconciler
+}
+
+// Report contains reconciliation results
+type Report struct {
+	Plan        *Plan
+	Added       int // Number of created users
+	Edited      int // Number of modified users
+	Suspended   int // Number of suspended users
+	Unsuspended int // Number of unsuspended users
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient         = errors.New("client is nil")
+	ErrNilReconciler     = errors.New("reconciler is nil")
+	ErrNilPlan           = errors.New("plan is nil")
+	ErrThresholdExceeded = errors.New("too many users must be suspended")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new reconciler
+func New(client Client) (*Reconciler, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Reconciler{
+		MaxSuspendRatio: DEFAULT_MAX_SUSPEND_RATIO,
+		client:          client,
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Sync creates plan for given entries and applies it
+func (r *Reconciler) Sync(entries Entries) (*Report, error) {
+	plan, err := r.Plan(entries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Apply(plan)
+}
+
+// Plan creates reconciliation plan for given entries. Empty values of fields
+// and tags are ignored, empty values of properties clear them.
+func (r *Reconciler) Plan(entries Entries) (*Plan, error) {
+	if r == nil {
+		return nil, ErrNilReconciler
+	}
+
+	props, err := r.client.GetProperties()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get properties: %w", err)
+	}
+
+	err = validateEntries(entries, props)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := r.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	plan := &Plan{}
+	existing := map[string]*pachca.User{}
+	listed := map[string]bool{}
+
+	for _, user := range users {
+		if user == nil || user.IsBot || r.isProtected(user.Email) {
+			continue
+		}
+
+		existing[strings.ToLower(user.Email)] = user
+
+		if !user.IsSuspended && (r.ManageGuests || !user.IsGuest()) {
+			plan.Managed++
+		}
+	}
+
+	for _, entry := range entries {
+		email := strings.ToLower(entry.Email)
+		listed[email] = true
+
+		if r.isProtected(entry.Email) {
+			continue
+		}
+
+		user := existing[email]
+
+		if user == nil {
+			plan.Actions = append(plan.Actions, r.addAction(entry, props))
+			continue
+		}
+
+		edit := editAction(user, entry, props)
+
+		if edit != nil {
+			plan.Actions = append(plan.Actions, edit)
+		}
+
+		if user.IsSuspended {
+			plan.Actions = append(plan.Actions, &Action{
+				Type: ACTION_UNSUSPEND, Email: user.Email, User: user,
+			})
+		}
+	}
+
+	if r.SuspendMissing {
+		for _, user := range users {
+			if user == nil || user.IsBot || user.IsSuspended ||
+				(user.IsGuest() && !r.ManageGuests) ||
+				r.isProtected(user.Email) || listed[strings.ToLower(user.Email)] {
+				continue
+			}
+
+			plan.Actions = append(plan.Actions, &Action{
+				Type: ACTION_SUSPEND, Email: user.Email, User: user,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// Check checks plan against safety thresholds
+func (r *Reconciler) Check(plan *Plan) error {
+	switch {
+	case r == nil:
+		return ErrNilReconciler
+	case plan == nil:
+		return ErrNilPlan
+	}
+
+	suspend := plan.Count(ACTION_SUSPEND)
+
+	if suspend == 0 {
+		return nil
+	}
+
+	if r.MaxSuspend > 0 && suspend > r.MaxSuspend {
+		return fmt.Errorf(
+			"%w: %d users (limit is %d)",
+			ErrThresholdExceeded, suspend, r.MaxSuspend,
+		)
+	}
+
+	if r.MaxSuspendRatio > 0 && float64(suspend) > float64(plan.Managed)*r.MaxSuspendRatio {
+		return fmt.Errorf(
+			"%w: %d of %d users (limit is %g%%)",
+			ErrThresholdExceeded, suspend, plan.Managed, r.MaxSuspendRatio*100,
+		)
+	}
+
+	return nil
+}
+
+// Apply applies reconciliation plan. Failed actions don't stop applying other
+// actions.
+func (r *Reconciler) Apply(plan *Plan) (*Report, error) {
+	err := r.Check(plan)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Plan: plan}
+
+	if r.DryRun {
+		return report, nil
+	}
+
+	errs := errors.NewBundle()
+
+	for _, action := range plan.Actions {
+		err := r.applyAction(action)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't %s user %s: %w", action.Type, action.Email, err))
+			continue
+		}
+
+		switch action.Type {
+		case ACTION_ADD:
+			report.Added++
+		case ACTION_EDIT:
+			report.Edited++
+		case ACTION_SUSPEND:
+			report.Suspended++
+		case ACTION_UNSUSPEND:
+			report.Unsuspended++
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return report, errs.Join()
+	}
+
+	return report, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Count returns number of actions with given type
+func (p *Plan) Count(typ ActionType) int {
+	if p == nil {
+		return 0
+	}
+
+	var result int
+
+	for _, a := range p.Actions {
+		if a.Type == typ {
+			result++
+		}
+	}
+
+	return result
+}
+
+// IsEmpty returns true if plan has no actions
+func (p *Plan) IsEmpty() bool {
+	return p == nil || len(p.Actions) == 0
+}
+
+// String returns human-readable report about plan actions
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "No changes"
+	}
+
+	var buf strings.Builder
+
+	for _, a := range p.Actions {
+		buf.WriteString(a.String() + "\n")
+	}
+
+	fmt.Fprintf(
+		&buf, "Add: %d | Edit: %d | Suspend: %d | Unsuspend: %d",
+		p.Count(ACTION_ADD), p.Count(ACTION_EDIT),
+		p.Count(ACTION_SUSPEND), p.Count(ACTION_UNSUSPEND),
+	)
+
+	return buf.String()
+}
+
+// String returns action description
+func (a *Action) String() string {
+	if a == nil {
+		return ""
+	}
+
+	result := a.Type.String() + " " + a.Email
+
+	if len(a.Changes) != 0 {
+		result += ": " + a.Changes.String()
+	}
+
+	return result
+}
+
+// String returns changes description
+func (c Changes) String() string {
+	var result []string
+
+	for _, cc := range c {
+		result = append(result, fmt.Sprintf("%s %q → %q", cc.Field, cc.Old, cc.New))
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// String returns name of action type
+func (t ActionType) String() string {
+	switch t {
+	case ACTION_ADD:
+		return "add"
+	case ACTION_EDIT:
+		return "edit"
+	case ACTION_SUSPEND:
+		return "suspend"
+	case ACTION_UNSUSPEND:
+		return "unsuspend"
+	}
+
+	return "unknown"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// applyAction sends API request for given action
+func (r *Reconciler) applyAction(a *Action) error {
+	var err error
+
+	switch a.Type {
+	case ACTION_ADD:
+		_, err = r.client.AddUser(a.Request)
+	case ACTION_EDIT:
+		_, err = r.client.EditUser(a.User.ID, a.Request)
+	case ACTION_SUSPEND:
+		err = r.client.SuspendUser(a.User.ID)
+	case ACTION_UNSUSPEND:
+		err = r.client.UnsuspendUser(a.User.ID)
+	}
+
+	return err
+}
+
+// isProtected returns true if user with given email is protected
+func (r *Reconciler) isProtected(email string) bool {
+	return slices.ContainsFunc(r.Protected, func(e string) bool {
+		return strings.EqualFold(e, email)
+	})
+}
+
+// addAction creates action for new user
+func (r *Reconciler) addAction(e *Entry, props pachca.Properties) *Action {
+	request := &pachca.UserRequest{
+		Email:           e.Email,
+		FirstName:       e.FirstName,
+		LastName:        e.LastName,
+		Nickname:        e.Nickname,
+		Title:           e.Title,
+		Department:      e.Department,
+		Role:            e.Role,
+		Tags:            e.Tags,
+		SkipEmailNotify: r.SkipEmailNotify,
+	}
+
+	for _, name := range sortedKeys(e.Properties) {
+		if e.Properties[name] != "" {
+			prop := props.Find(name)
+			request.Properties = append(request.Properties, propertyRequest(prop, e.Properties[name]))
+		}
+	}
+
+	return &Action{Type: ACTION_ADD, Email: e.Email, Request: request}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// editAction creates action with changes of existing user or nil if user
+// is up to date
+func editAction(u *pachca.User, e *Entry, props pachca.Properties) *Action {
+	request := &pachca.UserRequest{}
+	action := &Action{Type: ACTION_EDIT, Email: u.Email, User: u, Request: request}
+
+	diffField(action, "first_name", u.FirstName, e.FirstName, &request.FirstName)
+	diffField(action, "last_name", u.LastName, e.LastName, &request.LastName)
+	diffField(action, "nickname", u.Nickname, e.Nickname, &request.Nickname)
+	diffField(action, "title", u.Title, e.Title, &request.Title)
+	diffField(action, "department", u.Department, e.Department, &request.Department)
+
+	if e.Role != "" && e.Role != u.Role {
+		request.Role = e.Role
+		action.Changes = append(action.Changes, Change{"role", string(u.Role), string(e.Role)})
+	}
+
+	if len(e.Tags) != 0 && !sameTags(u.Tags, e.Tags) {
+		request.Tags = e.Tags
+		action.Changes = append(action.Changes, Change{
+			"tags", strings.Join(u.Tags, ", "), strings.Join(e.Tags, ", "),
+		})
+	}
+
+	for _, name := range sortedKeys(e.Properties) {
+		prop := props.Find(name)
+		current := u.Properties.Get(prop.ID)
+
+		if samePropertyValue(prop, current.String(), e.Properties[name]) {
+			continue
+		}
+
+		request.Properties = append(request.Properties, propertyRequest(prop, e.Properties[name]))
+		action.Changes = append(action.Changes, Change{prop.Name, current.String(), e.Properties[name]})
+	}
+
+	if len(action.Changes) == 0 {
+		return nil
+	}
+
+	return action
+}
+
+// diffField compares field values and adds change to action
+func diffField(a *Action, name, current, desired string, target *string) {
+	if desired == "" || desired == current {
+		return
+	}
+
+	*target = desired
+	a.Changes = append(a.Changes, Change{name, current, desired})
+}
+
+// validateEntries checks entries for errors
+func validateEntries(entries Entries, props pachca.Properties) error {
+	errs := errors.NewBundle()
+	emails := map[string]bool{}
+
+	for i, e := range entries {
+		if e == nil {
+			errs.Add(fmt.Errorf("entry %d is nil", i))
+			continue
+		}
+
+		email := strings.ToLower(e.Email)
+
+		switch {
+		case e.Email == "":
+			errs.Add(fmt.Errorf("entry %d has empty email", i))
+		case !strings.Contains(e.Email, "@"):
+			errs.Add(fmt.Errorf("entry %d has invalid email %q", i, e.Email))
+		case emails[email]:
+			errs.Add(fmt.Errorf("entry %d has duplicate email %q", i, e.Email))
+		}
+
+		emails[email] = true
+
+		switch e.Role {
+		case "", pachca.ROLE_ADMIN, pachca.ROLE_REGULAR,
+			pachca.ROLE_MULTI_GUEST, pachca.ROLE_GUEST:
+			// ok
+		default:
+			errs.Add(fmt.Errorf("entry %d (%s) has unsupported role %q", i, e.Email, e.Role))
+		}
+
+		for _, name := range sortedKeys(e.Properties) {
+			prop := props.Find(name)
+
+			if prop == nil {
+				errs.Add(fmt.Errorf("entry %d (%s) has unknown property %q", i, e.Email, name))
+				continue
+			}
+
+			if prop.IsDate() && e.Properties[name] != "" {
+				_, err := parseDateValue(e.Properties[name])
+
+				if err != nil {
+					errs.Add(fmt.Errorf(
+						"entry %d (%s) has invalid date %q for property %q",
+						i, e.Email, e.Properties[name], name,
+					))
+				}
+			}
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// propertyRequest creates property request with value formatted according to
+// property type
+func propertyRequest(prop *pachca.Property, value string) *pachca.PropertyRequest {
+	if prop.IsDate() && value != "" {
+		d, _ := parseDateValue(value)
+		return pachca.NewPropertyRequest(prop.ID, d)
+	}
+
+	return pachca.NewPropertyRequest(prop.ID, value)
+}
+
+// samePropertyValue returns true if property values are the same
+func samePropertyValue(prop *pachca.Property, current, desired string) bool {
+	if !prop.IsDate() || current == "" || desired == "" {
+		return current == desired
+	}
+
+	d, _ := parseDateValue(desired)
+	c := (&pachca.Property{Type: pachca.PROP_TYPE_DATE, Value: current}).Date()
+
+	return d.Equal(c)
+}
+
+// parseDateValue parses date in YYYY-MM-DD or RFC3339 format
+func parseDateValue(value string) (time.Time, error) {
+	d, err := time.Parse(time.DateOnly, value)
+
+	if err == nil {
+		return d, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// sameTags returns true if both slices contain the same tags
+func sameTags(current, desired []string) bool {
+	c, d := slices.Clone(current), slices.Clone(desired)
+
+	slices.Sort(c)
+	slices.Sort(d)
+
+	return slices.Equal(slices.Compact(c), slices.Compact(d))
+}
+
+// sortedKeys returns sorted map keys
+func sortedKeys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+
+	for k := range m {
+		result = append(result, k)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,572 @@
+package directory
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const TOKEN = "YQlf-6Vce7jM1RMZZUs_iWKYPt24PeR4c7k_RwzqjI5"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type DirectorySuite struct{}
+
+type fakeClient struct {
+	users pachca.Users
+	props pachca.Properties
+
+	added       []*pachca.UserRequest
+	edited      map[uint]*pachca.UserRequest
+	suspended   []uint
+	unsuspended []uint
+
+	failUsers bool
+	failProps bool
+	failEdit  bool
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&DirectorySuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *DirectorySuite) TestReadCSV(c *C) {
+	data := "\ufeffemail,first_name,last_name,nickname,title,department,role,tags,Birthday,Office\n" +
+		"john@domain.com, John ,Doe,john,Engineer,R&D,User,\"dev, backend\",1990-05-01,Berlin\n" +
+		"jane@domain.com,Jane,Doe,jane,,,,,,\n"
+
+	entries, err := ReadCSV(strings.NewReader(data))
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+
+	c.Assert(entries[0], DeepEquals, &Entry{
+		Email: "john@domain.com", FirstName: "John", LastName: "Doe",
+		Nickname: "john", Title: "Engineer", Department: "R&D",
+		Role: pachca.ROLE_REGULAR, Tags: []string{"dev", "backend"},
+		Properties: map[string]string{"Birthday": "1990-05-01", "Office": "Berlin"},
+	})
+
+	c.Assert(entries[1].Tags, IsNil)
+	c.Assert(entries[1].Properties, DeepEquals, map[string]string{"Birthday": "", "Office": ""})
+	c.Assert(entries.Find("JANE@domain.com"), Equals, entries[1])
+	c.Assert(entries.Find("unknown@domain.com"), IsNil)
+
+	file := filepath.Join(c.MkDir(), "users.csv")
+	os.WriteFile(file, []byte(data), 0644)
+
+	entries, err = ReadCSVFile(file)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+
+	_, err = ReadCSV(strings.NewReader(""))
+	c.Assert(err, ErrorMatches, `can't read CSV header: EOF`)
+	_, err = ReadCSV(strings.NewReader("email,title\n\"john"))
+	c.Assert(err, ErrorMatches, `can't read CSV data: .*`)
+	_, err = ReadCSVFile("/_unknown_")
+	c.Assert(err, NotNil)
+}
+
+func (s *DirectorySuite) TestReadJSON(c *C) {
+	data := `[{"email":"john@domain.com","first_name":"John","role":"admin","tags":["dev"],"properties":{"Office":"Berlin"}}]`
+
+	entries, err := ReadJSON(strings.NewReader(data))
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Role, Equals, pachca.ROLE_ADMIN)
+	c.Assert(entries[0].Properties["Office"], Equals, "Berlin")
+
+	file := filepath.Join(c.MkDir(), "users.json")
+	os.WriteFile(file, []byte(data), 0644)
+
+	entries, err = ReadJSONFile(file)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 1)
+
+	_, err = ReadJSON(strings.NewReader("{"))
+	c.Assert(err, ErrorMatches, `can't decode JSON data: .*`)
+	_, err = ReadJSONFile("/_unknown_")
+	c.Assert(err, NotNil)
+}
+
+func (s *DirectorySuite) TestExport(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(usersHandler))
+	defer srv.Close()
+
+	client := newAPIClient(srv)
+
+	_, err := NewExporter(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	e, err := NewExporter(client)
+	c.Assert(err, IsNil)
+
+	var buf bytes.Buffer
+
+	count, err := e.Export(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+	c.Assert(buf.String(), Equals,
+		"id,email,first_name,last_name,nickname,title,department,role,tags,status,last_activity_at\n"+
+			"1,john@domain.com,John,Doe,john,Engineer,R&D,user,\"dev, backend\",active,2026-03-01T09:30:00Z\n"+
+			"4,bob@domain.com,Bob,,bob,,,admin,,active,\n",
+	)
+
+	entries, err := ReadCSV(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].Properties, IsNil)
+	c.Assert(entries[0].Tags, DeepEquals, []string{"dev", "backend"})
+
+	buf.Reset()
+
+	e.Columns = []string{COLUMN_EMAIL, COLUMN_STATUS, COLUMN_CREATED, "birthday", "Office"}
+	e.WithSuspended, e.WithInvited, e.WithBots = true, true, true
+	e.Comma, e.BOM, e.DateLayout = ';', true, "2006-01-02"
+	e.BatchSize = 100
+
+	count, err = e.Export(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 5)
+	c.Assert(buf.String(), Equals,
+		"\ufeffemail;status;created_at;birthday;Office\n"+
+			"john@domain.com;active;2024-01-15;1990-05-17;Berlin\n"+
+			"jane@domain.com;invited;;;\n"+
+			"jack@domain.com;suspended;;;\n"+
+			"bob@domain.com;active;;;\n"+
+			";active;;;\n",
+	)
+
+	buf.Reset()
+
+	e.Format = FORMAT_JSONL
+	e.Columns = []string{COLUMN_ID, COLUMN_TAGS, COLUMN_CREATED, "Birthday"}
+	e.DateLayout = ""
+	e.WithSuspended, e.WithInvited, e.WithBots = false, false, false
+
+	count, err = e.Export(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+	c.Assert(buf.String(), Equals,
+		`{"Birthday":"1990-05-17T00:00:00Z","created_at":"2024-01-15T00:00:00Z","id":1,"tags":["dev","backend"]}`+"\n"+
+			`{"Birthday":"","created_at":"","id":4,"tags":[]}`+"\n",
+	)
+
+	file := filepath.Join(c.MkDir(), "users.jsonl")
+	count, err = e.ExportFile(file)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+
+	data, _ := os.ReadFile(file)
+	c.Assert(string(data), Equals, buf.String())
+
+	_, err = e.ExportFile("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+	_, err = e.ExportFile("/_unknown_/users.jsonl")
+	c.Assert(err, ErrorMatches, `can't create export file: .*`)
+	_, err = e.Export(nil)
+	c.Assert(err, Equals, ErrNilWriter)
+
+	e.Format = 10
+	_, err = e.Export(&buf)
+	c.Assert(err, Equals, ErrUnknownFormat)
+
+	e.Format = FORMAT_CSV
+	e.Columns = []string{COLUMN_EMAIL}
+	e.BatchSize = 1
+	count, err = e.Export(&buf)
+	c.Assert(err, ErrorMatches, `can't fetch users: .*`)
+	c.Assert(count, Equals, 1)
+
+	var ne *Exporter
+	_, err = ne.Export(&buf)
+	c.Assert(err, Equals, ErrNilExporter)
+	_, err = ne.ExportFile(file)
+	c.Assert(err, Equals, ErrNilExporter)
+}
+
+func (s *DirectorySuite) TestExportFormulas(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(usersHandler))
+	defer srv.Close()
+
+	e, err := NewExporter(newAPIClient(srv))
+	c.Assert(err, IsNil)
+
+	e.Columns = []string{COLUMN_FIRST_NAME, COLUMN_LAST_NAME, COLUMN_NICKNAME, COLUMN_TITLE, COLUMN_DEPARTMENT}
+
+	var buf bytes.Buffer
+
+	enc := e.newEncoder(bufio.NewWriter(&buf))
+
+	c.Assert(enc.user(&pachca.User{
+		FirstName: `=HYPERLINK("http://evil.com","x")`, LastName: "+1", Nickname: "-1",
+		Title: "@SUM(A1)", Department: "\tR&D",
+	}), IsNil)
+	c.Assert(enc.flush(), IsNil)
+	c.Assert(buf.String(), Equals, `"'=HYPERLINK(""http://evil.com"",""x"")",'+1,'-1,'@SUM(A1),'`+"\tR&D\n")
+
+	c.Assert(escapeCell(""), Equals, "")
+	c.Assert(escapeCell("John"), Equals, "John")
+	c.Assert(escapeCell("\r1"), Equals, "'\r1")
+}
+
+func (s *DirectorySuite) TestPlan(c *C) {
+	client := newFakeClient()
+	r, err := New(client)
+	c.Assert(err, IsNil)
+
+	r.SuspendMissing = true
+	r.MaxSuspendRatio = 0
+	r.Protected = []string{"ADMIN@domain.com"}
+
+	entries := Entries{
+		{
+			Email: "John@domain.com", FirstName: "John", Title: "Lead Engineer",
+			Tags: []string{"backend", "dev"}, Role: pachca.ROLE_ADMIN,
+			Properties: map[string]string{"Birthday": "1990-05-01", "office": ""},
+		},
+		{
+			Email: "jane@domain.com", FirstName: "Jane", Title: "Designer",
+			Tags: []string{"design"}, Properties: map[string]string{"Birthday": "1991-02-03"},
+		},
+		{Email: "new@domain.com", FirstName: "New", Tags: []string{"dev"}, Properties: map[string]string{"Birthday": "2000-01-01", "Office": ""}},
+		{Email: "admin@domain.com", FirstName: "Changed"},
+	}
+
+	plan, err := r.Plan(entries)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Managed, Equals, 2)
+	c.Assert(plan.Actions, HasLen, 5)
+	c.Assert(plan.Count(ACTION_ADD), Equals, 1)
+	c.Assert(plan.Count(ACTION_EDIT), Equals, 2)
+	c.Assert(plan.Count(ACTION_SUSPEND), Equals, 1)
+	c.Assert(plan.Count(ACTION_UNSUSPEND), Equals, 1)
+
+	john := plan.Actions[0]
+	c.Assert(john.Type, Equals, ACTION_EDIT)
+	c.Assert(john.User.ID, Equals, uint(1))
+	c.Assert(john.Request, DeepEquals, &pachca.UserRequest{
+		Title: "Lead Engineer",
+		Role:  pachca.ROLE_ADMIN,
+		Properties: pachca.PropertyRequests{
+			{ID: 2, Value: ""},
+		},
+	})
+	c.Assert(john.String(), Equals, `edit john@domain.com: title "Engineer" → "Lead Engineer", role "user" → "admin", Office "Berlin" → ""`)
+
+	c.Assert(plan.Actions[1].Type, Equals, ACTION_EDIT)
+	c.Assert(plan.Actions[1].Request.Tags, DeepEquals, []string{"design"})
+	c.Assert(plan.Actions[1].Request.Properties, DeepEquals, pachca.PropertyRequests{
+		{ID: 1, Value: "1991-02-03T00:00:00Z"},
+	})
+	c.Assert(plan.Actions[2].Type, Equals, ACTION_UNSUSPEND)
+	c.Assert(plan.Actions[2].Email, Equals, "jane@domain.com")
+
+	c.Assert(plan.Actions[3].Type, Equals, ACTION_ADD)
+	c.Assert(plan.Actions[3].Request, DeepEquals, &pachca.UserRequest{
+		Email: "new@domain.com", FirstName: "New", Tags: []string{"dev"},
+		Properties: pachca.PropertyRequests{{ID: 1, Value: "2000-01-01T00:00:00Z"}},
+	})
+
+	c.Assert(plan.Actions[4].Type, Equals, ACTION_SUSPEND)
+	c.Assert(plan.Actions[4].Email, Equals, "bob@domain.com")
+
+	c.Assert(plan.String(), Equals, strings.Join([]string{
+		john.String(),
+		`edit jane@domain.com: title "" → "Designer", tags "" → "design", Birthday "" → "1991-02-03"`,
+		"unsuspend jane@domain.com",
+		"add new@domain.com",
+		"suspend bob@domain.com",
+		"Add: 1 | Edit: 2 | Suspend: 1 | Unsuspend: 1",
+	}, "\n"))
+
+	r.ManageGuests = true
+	plan, err = r.Plan(entries)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Managed, Equals, 3)
+	c.Assert(plan.Count(ACTION_SUSPEND), Equals, 2)
+
+	r.SuspendMissing = false
+	plan, err = r.Plan(entries[:1])
+	c.Assert(err, IsNil)
+	c.Assert(plan.Actions, HasLen, 1)
+
+	plan, err = r.Plan(Entries{{Email: "john@domain.com", Title: "Engineer", Tags: []string{"dev", "backend"}}})
+	c.Assert(err, IsNil)
+	c.Assert(plan.IsEmpty(), Equals, true)
+	c.Assert(plan.String(), Equals, "No changes")
+}
+
+func (s *DirectorySuite) TestValidation(c *C) {
+	r, _ := New(newFakeClient())
+
+	_, err := r.Plan(Entries{
+		nil,
+		{Email: ""},
+		{Email: "john"},
+		{Email: "jane@domain.com", Role: "boss"},
+		{Email: "JANE@domain.com", Properties: map[string]string{"Unknown": "1", "Birthday": "01.05.1990"}},
+	})
+
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, strings.Join([]string{
+		"entry 0 is nil",
+		"entry 1 has empty email",
+		`entry 2 has invalid email "john"`,
+		`entry 3 (jane@domain.com) has unsupported role "boss"`,
+		`entry 4 has duplicate email "JANE@domain.com"`,
+		`entry 4 (JANE@domain.com) has invalid date "01.05.1990" for property "Birthday"`,
+		`entry 4 (JANE@domain.com) has unknown property "Unknown"`,
+	}, "\n"))
+}
+
+func (s *DirectorySuite) TestThresholds(c *C) {
+	r, _ := New(newFakeClient())
+	r.SuspendMissing = true
+
+	entries := Entries{{Email: "john@domain.com"}}
+
+	plan, err := r.Plan(entries)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Count(ACTION_SUSPEND), Equals, 2)
+
+	err = r.Check(plan)
+	c.Assert(err, ErrorMatches, `too many users must be suspended: 2 of 3 users \(limit is 10%\)`)
+
+	_, err = r.Sync(entries)
+	c.Assert(err, ErrorMatches, `too many users must be suspended: .*`)
+
+	r.MaxSuspendRatio = 0.7
+	c.Assert(r.Check(plan), IsNil)
+
+	r.MaxSuspendRatio = 0
+	r.MaxSuspend = 2
+	c.Assert(r.Check(plan), IsNil)
+
+	plan.Actions = append(plan.Actions, plan.Actions[len(plan.Actions)-1])
+	c.Assert(r.Check(plan), ErrorMatches, `too many users must be suspended: 3 users \(limit is 2\)`)
+}
+
+func (s *DirectorySuite) TestApply(c *C) {
+	client := newFakeClient()
+	r, _ := New(client)
+	r.SuspendMissing = true
+	r.MaxSuspendRatio = 0.5
+	r.SkipEmailNotify = true
+
+	entries := Entries{
+		{Email: "john@domain.com", Title: "Lead Engineer"},
+		{Email: "jane@domain.com"},
+		{Email: "new@domain.com", FirstName: "New"},
+		{Email: "admin@domain.com"},
+	}
+
+	r.DryRun = true
+	report, err := r.Sync(entries)
+	c.Assert(err, IsNil)
+	c.Assert(report.Plan.Actions, HasLen, 4)
+	c.Assert(report.Added+report.Edited+report.Suspended+report.Unsuspended, Equals, 0)
+	c.Assert(client.added, HasLen, 0)
+
+	r.DryRun = false
+	report, err = r.Sync(entries)
+	c.Assert(err, IsNil)
+	c.Assert(report.Added, Equals, 1)
+	c.Assert(report.Edited, Equals, 1)
+	c.Assert(report.Suspended, Equals, 1)
+	c.Assert(report.Unsuspended, Equals, 1)
+	c.Assert(client.added[0].SkipEmailNotify, Equals, true)
+	c.Assert(client.edited[1].Title, Equals, "Lead Engineer")
+	c.Assert(client.suspended, DeepEquals, []uint{4})
+	c.Assert(client.unsuspended, DeepEquals, []uint{3})
+
+	client.failEdit = true
+	r.MaxSuspendRatio = 0
+	report, err = r.Sync(entries[:1])
+	c.Assert(err, ErrorMatches, `can't edit user john@domain.com: edit error`)
+	c.Assert(report.Edited, Equals, 0)
+	c.Assert(report.Suspended, Equals, 2)
+}
+
+func (s *DirectorySuite) TestErrors(c *C) {
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	var r *Reconciler
+
+	_, err = r.Plan(nil)
+	c.Assert(err, Equals, ErrNilReconciler)
+	_, err = r.Sync(nil)
+	c.Assert(err, Equals, ErrNilReconciler)
+	_, err = r.Apply(&Plan{})
+	c.Assert(err, Equals, ErrNilReconciler)
+	c.Assert(r.Check(&Plan{}), Equals, ErrNilReconciler)
+
+	client := newFakeClient()
+	r, _ = New(client)
+
+	_, err = r.Apply(nil)
+	c.Assert(err, Equals, ErrNilPlan)
+
+	client.failUsers = true
+	_, err = r.Plan(nil)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+
+	client.failProps = true
+	_, err = r.Plan(nil)
+	c.Assert(err, ErrorMatches, `can't get properties: properties error`)
+
+	var p *Plan
+	c.Assert(p.Count(ACTION_ADD), Equals, 0)
+	c.Assert(p.IsEmpty(), Equals, true)
+
+	var a *Action
+	c.Assert(a.String(), Equals, "")
+	c.Assert(ActionType(0).String(), Equals, "unknown")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		props: pachca.Properties{
+			{ID: 1, Name: "Birthday", Type: pachca.PROP_TYPE_DATE},
+			{ID: 2, Name: "Office", Type: pachca.PROP_TYPE_TEXT},
+		},
+		users: pachca.Users{
+			{
+				ID: 1, Email: "john@domain.com", FirstName: "John", Title: "Engineer",
+				Role: pachca.ROLE_REGULAR, Tags: []string{"dev", "backend"},
+				InviteStatus: pachca.INVITE_CONFIRMED,
+				Properties: pachca.Properties{
+					{ID: 1, Name: "Birthday", Type: pachca.PROP_TYPE_DATE, Value: "1990-05-01T00:00:00.000Z"},
+					{ID: 2, Name: "Office", Type: pachca.PROP_TYPE_TEXT, Value: "Berlin"},
+				},
+			},
+			{ID: 2, Email: "admin@domain.com", Role: pachca.ROLE_ADMIN, InviteStatus: pachca.INVITE_CONFIRMED},
+			{ID: 3, Email: "jane@domain.com", FirstName: "Jane", Role: pachca.ROLE_REGULAR, IsSuspended: true},
+			{ID: 4, Email: "bob@domain.com", Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_SENT},
+			{ID: 5, Email: "guest@domain.com", Role: pachca.ROLE_GUEST, InviteStatus: pachca.INVITE_CONFIRMED},
+			{ID: 6, Email: "bot@domain.com", Role: pachca.ROLE_REGULAR, IsBot: true},
+			nil,
+		},
+		edited: map[uint]*pachca.UserRequest{},
+	}
+}
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.failUsers {
+		return nil, fmt.Errorf("users error")
+	}
+
+	return c.users, nil
+}
+
+func (c *fakeClient) GetProperties() (pachca.Properties, error) {
+	if c.failProps {
+		return nil, fmt.Errorf("properties error")
+	}
+
+	return c.props, nil
+}
+
+func (c *fakeClient) AddUser(user *pachca.UserRequest) (*pachca.User, error) {
+	c.added = append(c.added, user)
+	return &pachca.User{ID: 100, Email: user.Email}, nil
+}
+
+func (c *fakeClient) EditUser(userID uint, user *pachca.UserRequest) (*pachca.User, error) {
+	if c.failEdit {
+		return nil, fmt.Errorf("edit error")
+	}
+
+	c.edited[userID] = user
+
+	return &pachca.User{ID: userID}, nil
+}
+
+func (c *fakeClient) SuspendUser(userID uint) error {
+	c.suspended = append(c.suspended, userID)
+	return nil
+}
+
+func (c *fakeClient) UnsuspendUser(userID uint) error {
+	c.unsuspended = append(c.unsuspended, userID)
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newAPIClient(srv *httptest.Server) *pachca.Client {
+	client, _ := pachca.NewClient(TOKEN)
+	target, _ := url.Parse(srv.URL)
+
+	client.Engine().Client = &http.Client{Transport: &rewriteTransport{target}}
+
+	return client
+}
+
+func usersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("limit") == "1" {
+		if r.URL.Query().Get("cursor") != "" {
+			w.WriteHeader(500)
+			return
+		}
+
+		fmt.Fprint(w, `{"data":[{"id":1,"email":"john@domain.com","invite_status":"confirmed"}],"meta":{"paginate":{"has_next":true,"next_page":"p2"}}}`)
+		return
+	}
+
+	switch r.URL.Query().Get("cursor") {
+	case "":
+		fmt.Fprint(w, `{"data":[
+			{"id":1,"email":"john@domain.com","first_name":"John","last_name":"Doe","nickname":"john",
+			 "title":"Engineer","department":"R&D","role":"user","list_tags":["dev","backend"],
+			 "invite_status":"confirmed","created_at":"2024-01-15T00:00:00.000Z",
+			 "last_activity_at":"2026-03-01T09:30:00.000Z",
+			 "custom_properties":[
+			   {"id":1,"name":"Birthday","data_type":"date","value":"1990-05-17T00:00:00.000Z"},
+			   {"id":2,"name":"Office","data_type":"text","value":"Berlin"}
+			 ]},
+			{"id":2,"email":"jane@domain.com","role":"user","invite_status":"sent"},
+			{"id":3,"email":"jack@domain.com","role":"user","invite_status":"confirmed","suspended":true}
+		],"meta":{"paginate":{"has_next":true,"next_page":"p2"}}}`)
+	default:
+		fmt.Fprint(w, `{"data":[
+			{"id":4,"email":"bob@domain.com","first_name":"Bob","nickname":"bob","role":"admin","invite_status":"confirmed"},
+			{"id":5,"role":"user","invite_status":"confirmed","bot":true}
+		]}`)
+	}
+}
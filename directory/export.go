@@ -0,0 +1,418 @@
+package directory
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	FORMAT_CSV   Format = iota // Comma-separated values
+	FORMAT_JSONL               // JSON Lines
+)
+
+const (
+	STATUS_ACTIVE    = "active"
+	STATUS_INVITED   = "invited"
+	STATUS_SUSPENDED = "suspended"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ExportClient is the subset of Pachca API client methods used by exporter
+type ExportClient interface {
+	PaginateUsers(limit int) *pachca.UserPaginator
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Format is export format
+type Format uint8
+
+// Exporter exports workspace users. CSV cells which start with =, +, -, @, tab
+// or carriage return are prefixed with ' so spreadsheets don't treat them as
+// formulas.
+type Exporter struct {
+	// Columns is a list of exported columns. Columns which are not known fields
+	// are treated as custom properties names. DefaultColumns are used if empty.
+	Columns []string
+
+	// Format is output format
+	Format Format
+
+	// WithSuspended enables export of suspended users
+	WithSuspended bool
+
+	// WithInvited enables export of users who haven't accepted invite yet
+	WithInvited bool
+
+	// WithBots enables export of bots
+	WithBots bool
+
+	// Comma is CSV fields delimiter (comma by default)
+	Comma rune
+
+	// BOM enables writing UTF-8 byte order mark at the beginning of CSV data,
+	// which is required by Excel to detect encoding
+	BOM bool
+
+	// DateLayout is layout for dates (RFC 3339 in UTC by default)
+	DateLayout string
+
+	// BatchSize is number of users per page
+	BatchSize int
+
+	client ExportClient
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DefaultColumns is default list of exported columns
+var DefaultColumns = []string{
+	COLUMN_ID, COLUMN_EMAIL, COLUMN_FIRST_NAME, COLUMN_LAST_NAME, COLUMN_NICKNAME,
+	COLUMN_TITLE, COLUMN_DEPARTMENT, COLUMN_ROLE, COLUMN_TAGS, COLUMN_STATUS,
+	COLUMN_LAST_ACTIVITY,
+}
+
+var (
+	ErrNilExporter   = errors.New("exporter is nil")
+	ErrNilWriter     = errors.New("writer is nil")
+	ErrEmptyFilePath = errors.New("export file path is empty")
+	ErrUnknownFormat = errors.New("unknown export format")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewExporter creates new users exporter
+func NewExporter(client ExportClient) (*Exporter, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Exporter{client: client, BatchSize: pachca.MAX_PER_PAGE}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Export streams users into given writer and returns number of exported users
+func (e *Exporter) Export(w io.Writer) (int, error) {
+	switch {
+	case e == nil:
+		return 0, ErrNilExporter
+	case w == nil:
+		return 0, ErrNilWriter
+	case e.Format > FORMAT_JSONL:
+		return 0, ErrUnknownFormat
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := e.newEncoder(bw)
+
+	err := enc.header()
+
+	if err != nil {
+		return 0, fmt.Errorf("can't write header: %w", err)
+	}
+
+	count := 0
+	paginator := e.client.PaginateUsers(e.getBatchSize())
+
+	for users := range paginator.Pages {
+		for _, user := range users {
+			if !e.isExported(user) {
+				continue
+			}
+
+			err = enc.user(user)
+
+			if err != nil {
+				return count, fmt.Errorf("can't write user %d: %w", user.ID, err)
+			}
+
+			count++
+		}
+
+		err = enc.flush()
+
+		if err != nil {
+			return count, fmt.Errorf("can't write data: %w", err)
+		}
+	}
+
+	err = paginator.Error()
+
+	if err != nil {
+		return count, fmt.Errorf("can't fetch users: %w", err)
+	}
+
+	err = enc.flush()
+
+	if err != nil {
+		return count, fmt.Errorf("can't write data: %w", err)
+	}
+
+	return count, nil
+}
+
+// ExportFile exports users into given file
+func (e *Exporter) ExportFile(file string) (int, error) {
+	switch {
+	case e == nil:
+		return 0, ErrNilExporter
+	case file == "":
+		return 0, ErrEmptyFilePath
+	}
+
+	fd, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+
+	if err != nil {
+		return 0, fmt.Errorf("can't create export file: %w", err)
+	}
+
+	count, err := e.Export(fd)
+
+	if err != nil {
+		fd.Close()
+		return count, err
+	}
+
+	return count, fd.Close()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// encoder is users encoder
+type encoder struct {
+	e   *Exporter
+	w   *bufio.Writer
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+// newEncoder creates encoder for exporter format
+func (e *Exporter) newEncoder(w *bufio.Writer) *encoder {
+	enc := &encoder{e: e, w: w}
+
+	if e.Format == FORMAT_CSV {
+		enc.csv = csv.NewWriter(w)
+
+		if e.Comma != 0 {
+			enc.csv.Comma = e.Comma
+		}
+	} else {
+		enc.enc = json.NewEncoder(w)
+		enc.enc.SetEscapeHTML(false)
+	}
+
+	return enc
+}
+
+// header writes CSV header
+func (enc *encoder) header() error {
+	if enc.csv == nil {
+		return nil
+	}
+
+	if enc.e.BOM {
+		_, err := enc.w.WriteString("\ufeff")
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.csv.Write(enc.e.getColumns())
+}
+
+// user writes user data
+func (enc *encoder) user(user *pachca.User) error {
+	columns := enc.e.getColumns()
+
+	if enc.csv != nil {
+		record := make([]string, len(columns))
+
+		for i, column := range columns {
+			record[i] = escapeCell(enc.e.format(enc.e.value(user, column)))
+		}
+
+		return enc.csv.Write(record)
+	}
+
+	record := make(map[string]any, len(columns))
+
+	for _, column := range columns {
+		record[column] = enc.e.value(user, column)
+
+		if t, ok := record[column].(time.Time); ok {
+			record[column] = enc.e.format(t)
+		}
+	}
+
+	return enc.enc.Encode(record)
+}
+
+// flush flushes buffered data
+func (enc *encoder) flush() error {
+	if enc.csv != nil {
+		enc.csv.Flush()
+
+		err := enc.csv.Error()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.w.Flush()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isExported returns true if user must be exported
+func (e *Exporter) isExported(user *pachca.User) bool {
+	switch {
+	case user == nil:
+		return false
+	case user.IsBot:
+		return e.WithBots
+	case user.IsSuspended:
+		return e.WithSuspended
+	case user.IsInvited():
+		return e.WithInvited
+	}
+
+	return true
+}
+
+// value returns value of column for given user
+func (e *Exporter) value(user *pachca.User, column string) any {
+	switch strings.ToLower(column) {
+	case COLUMN_ID:
+		return user.ID
+	case COLUMN_EMAIL:
+		return user.Email
+	case COLUMN_FIRST_NAME:
+		return user.FirstName
+	case COLUMN_LAST_NAME:
+		return user.LastName
+	case COLUMN_NICKNAME:
+		return user.Nickname
+	case COLUMN_TITLE:
+		return user.Title
+	case COLUMN_DEPARTMENT:
+		return user.Department
+	case COLUMN_ROLE:
+		return string(user.Role)
+	case COLUMN_TAGS:
+		if user.Tags == nil {
+			return []string{}
+		}
+
+		return user.Tags
+	case COLUMN_PHONE:
+		return user.PhoneNumber
+	case COLUMN_TIME_ZONE:
+		return user.TimeZone
+	case COLUMN_STATUS:
+		return getStatus(user)
+	case COLUMN_CREATED:
+		return user.CreatedAt.Time
+	case COLUMN_LAST_ACTIVITY:
+		return user.LastActivityAt.Time
+	}
+
+	prop := user.Properties.Find(column)
+
+	if prop.IsDate() && prop.IsSet() {
+		d, err := prop.ToDate()
+
+		if err == nil {
+			return d
+		}
+	}
+
+	return prop.String()
+}
+
+// format formats value for CSV
+func (e *Exporter) format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case []string:
+		return strings.Join(v, ", ")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+
+		if e.DateLayout == "" {
+			return v.UTC().Format(time.RFC3339)
+		}
+
+		return v.UTC().Format(e.DateLayout)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// getColumns returns list of exported columns
+func (e *Exporter) getColumns() []string {
+	if len(e.Columns) == 0 {
+		return DefaultColumns
+	}
+
+	return e.Columns
+}
+
+// getBatchSize returns number of users per page
+func (e *Exporter) getBatchSize() int {
+	if e.BatchSize <= 0 || e.BatchSize > pachca.MAX_PER_PAGE {
+		return pachca.MAX_PER_PAGE
+	}
+
+	return e.BatchSize
+}
+
+// getStatus returns user account status
+func getStatus(user *pachca.User) string {
+	switch {
+	case user.IsSuspended:
+		return STATUS_SUSPENDED
+	case user.IsInvited():
+		return STATUS_INVITED
+	}
+
+	return STATUS_ACTIVE
+}
+
+// escapeCell escapes CSV cell which can be interpreted as formula by
+// spreadsheet applications
+func escapeCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+
+	return "'" + value
+}
@@ -0,0 +1,210 @@
+package directory
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	COLUMN_EMAIL      = "email"
+	COLUMN_FIRST_NAME = "first_name"
+	COLUMN_LAST_NAME  = "last_name"
+	COLUMN_NICKNAME   = "nickname"
+	COLUMN_TITLE      = "title"
+	COLUMN_DEPARTMENT = "department"
+	COLUMN_ROLE       = "role"
+	COLUMN_TAGS       = "tags"
+
+	// Read-only columns (used only for export)
+	COLUMN_ID            = "id"
+	COLUMN_PHONE         = "phone_number"
+	COLUMN_TIME_ZONE     = "time_zone"
+	COLUMN_STATUS        = "status"
+	COLUMN_CREATED       = "created_at"
+	COLUMN_LAST_ACTIVITY = "last_activity_at"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Entry contains desired state of user
+type Entry struct {
+	Email      string            `json:"email"`
+	FirstName  string            `json:"first_name,omitempty"`
+	LastName   string            `json:"last_name,omitempty"`
+	Nickname   string            `json:"nickname,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	Department string            `json:"department,omitempty"`
+	Role       pachca.UserRole   `json:"role,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"` // Property name → value
+}
+
+// Entries is a slice of entries
+type Entries []*Entry
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadCSV reads entries from CSV data. The first row must contain column names.
+// Columns which are not known fields (email, first_name, last_name, nickname,
+// title, department, role and tags) are treated as custom properties names.
+// Read-only columns produced by exporter are ignored. Tags are separated by
+// commas.
+func ReadCSV(r io.Reader) (Entries, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read CSV header: %w", err)
+	}
+
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	var result Entries
+
+	for {
+		record, err := cr.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can't read CSV data: %w", err)
+		}
+
+		result = append(result, parseRecord(header, record))
+	}
+
+	return result, nil
+}
+
+// ReadCSVFile reads entries from CSV file
+func ReadCSVFile(file string) (Entries, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return ReadCSV(fd)
+}
+
+// ReadJSON reads entries from JSON array
+func ReadJSON(r io.Reader) (Entries, error) {
+	var result Entries
+
+	err := json.NewDecoder(r).Decode(&result)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't decode JSON data: %w", err)
+	}
+
+	return result, nil
+}
+
+// ReadJSONFile reads entries from JSON file
+func ReadJSONFile(file string) (Entries, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return ReadJSON(fd)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Find returns entry with given email
+func (e Entries) Find(email string) *Entry {
+	for _, entry := range e {
+		if entry != nil && strings.EqualFold(entry.Email, email) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseRecord creates entry from CSV record
+func parseRecord(header, record []string) *Entry {
+	entry := &Entry{}
+
+	for i, value := range record {
+		if i >= len(header) || header[i] == "" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(header[i]) {
+		case COLUMN_EMAIL:
+			entry.Email = value
+		case COLUMN_FIRST_NAME:
+			entry.FirstName = value
+		case COLUMN_LAST_NAME:
+			entry.LastName = value
+		case COLUMN_NICKNAME:
+			entry.Nickname = value
+		case COLUMN_TITLE:
+			entry.Title = value
+		case COLUMN_DEPARTMENT:
+			entry.Department = value
+		case COLUMN_ROLE:
+			entry.Role = pachca.UserRole(strings.ToLower(value))
+		case COLUMN_TAGS:
+			entry.Tags = splitTags(value)
+		case COLUMN_ID, COLUMN_PHONE, COLUMN_TIME_ZONE, COLUMN_STATUS,
+			COLUMN_CREATED, COLUMN_LAST_ACTIVITY:
+			continue
+		default:
+			if entry.Properties == nil {
+				entry.Properties = map[string]string{}
+			}
+
+			entry.Properties[header[i]] = value
+		}
+	}
+
+	return entry
+}
+
+// splitTags splits comma-separated list of tags
+func splitTags(value string) []string {
+	var result []string
+
+	for tag := range strings.SplitSeq(value, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,624 @@
+package export
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+	"github.com/essentialkaos/ek/v14/req"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	FILE_CHAT       = "chat.json"
+	FILE_USERS      = "users.json"
+	FILE_MESSAGES   = "messages.jsonl"
+	FILE_CHECKPOINT = "checkpoint.json"
+	FILE_MARKDOWN   = "chat.md"
+	FILE_HTML       = "chat.html"
+	DIR_FILES       = "files"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by exporter
+type Client interface {
+	GetChat(chatID uint) (*pachca.Chat, error)
+	GetChatUsers(chatID uint, memberRole pachca.ChatRole) (pachca.Users, error)
+	GetUser(userID uint) (*pachca.User, error)
+	GetThread(threadID uint) (*pachca.Thread, error)
+	GetReactions(messageID uint) (pachca.Reactions, error)
+	GetMessageReads(messageID uint) ([]uint, error)
+	PaginateMessages(chatID uint, limit int, order pachca.SortOrder) *pachca.MessagePaginator
+	Engine() *req.Engine
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Exporter exports chats into directory
+type Exporter struct {
+	Dir string // Dir is target directory (every chat is exported into sub-directory)
+
+	WithThreads   bool // Export messages from threads
+	WithReactions bool // Export message reactions
+	WithReads     bool // Export number of users who read the message
+	WithFiles     bool // Download attachments
+	Markdown      bool // Render Markdown version of the chat
+	HTML          bool // Render HTML version of the chat
+
+	BatchSize int // Number of messages per page
+
+	client Client
+}
+
+// Record is exported message
+type Record struct {
+	*pachca.Message
+
+	ThreadID   uint             `json:"export_thread_id,omitempty"`
+	Reactions  pachca.Reactions `json:"export_reactions,omitempty"`
+	ReadCount  int              `json:"export_read_count"`
+	LocalFiles []string         `json:"export_local_files,omitempty"`
+}
+
+// Records is a slice of records
+type Records []*Record
+
+// Checkpoint contains info about export progress
+type Checkpoint struct {
+	Threads       map[uint]*ThreadCheckpoint `json:"threads,omitempty"`
+	LastMessageID uint                       `json:"last_message_id"`
+	Offset        int64                      `json:"offset"`
+	IsComplete    bool                       `json:"complete"`
+}
+
+// ThreadCheckpoint contains info about export progress of thread
+type ThreadCheckpoint struct {
+	ChatID        uint `json:"chat_id"`
+	LastMessageID uint `json:"last_message_id"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient     = errors.New("client is nil")
+	ErrNilExporter   = errors.New("exporter is nil")
+	ErrEmptyDir      = errors.New("export directory path is empty")
+	ErrInvalidChatID = errors.New("chat ID must be greater than 0")
+)
+
+// unsafeNameRegex is regex for characters which are not allowed in file names
+var unsafeNameRegex = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new exporter
+func New(client Client, dir string) (*Exporter, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case dir == "":
+		return nil, ErrEmptyDir
+	}
+
+	return &Exporter{
+		Dir:           dir,
+		WithThreads:   true,
+		WithReactions: true,
+		WithReads:     true,
+		BatchSize:     pachca.MAX_PER_PAGE,
+		client:        client,
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ChatDir returns path to directory with exported data for chat with given ID
+func (e *Exporter) ChatDir(chatID uint) string {
+	if e == nil {
+		return ""
+	}
+
+	return filepath.Join(e.Dir, strconv.FormatUint(uint64(chatID), 10))
+}
+
+// Export exports chat with given ID. If chat was partially exported before,
+// export continues from the latest checkpoint. If chat was fully exported
+// before, only new messages and new replies in known threads are exported.
+func (e *Exporter) Export(chatID uint) error {
+	switch {
+	case e == nil:
+		return ErrNilExporter
+	case chatID == 0:
+		return ErrInvalidChatID
+	}
+
+	dir := e.ChatDir(chatID)
+	err := os.MkdirAll(filepath.Join(dir, DIR_FILES), 0750)
+
+	if err != nil {
+		return fmt.Errorf("can't create export directory: %w", err)
+	}
+
+	chat, err := e.client.GetChat(chatID)
+
+	if err != nil {
+		return err
+	}
+
+	err = jsonutil.Write(filepath.Join(dir, FILE_CHAT), chat, 0640)
+
+	if err != nil {
+		return fmt.Errorf("can't save chat info: %w", err)
+	}
+
+	err = e.exportMessages(chatID, dir)
+
+	if err != nil {
+		return err
+	}
+
+	archive, err := Load(dir)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.exportUsers(chatID, dir, archive)
+
+	if err != nil {
+		return err
+	}
+
+	if e.Markdown {
+		err = writeFile(filepath.Join(dir, FILE_MARKDOWN), archive.WriteMarkdown)
+
+		if err != nil {
+			return fmt.Errorf("can't render markdown: %w", err)
+		}
+	}
+
+	if e.HTML {
+		err = writeFile(filepath.Join(dir, FILE_HTML), archive.WriteHTML)
+
+		if err != nil {
+			return fmt.Errorf("can't render HTML: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadCheckpoint reads checkpoint from given directory
+func ReadCheckpoint(dir string) (*Checkpoint, error) {
+	cp := &Checkpoint{}
+	err := jsonutil.Read(filepath.Join(dir, FILE_CHECKPOINT), cp)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("can't read checkpoint: %w", err)
+	}
+
+	return cp, nil
+}
+
+// ReadRecords reads all records from given JSONL file
+func ReadRecords(file string) (Records, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't open messages file: %w", err)
+	}
+
+	defer fd.Close()
+
+	var result Records
+
+	dec := json.NewDecoder(bufio.NewReader(fd))
+
+	for {
+		r := &Record{}
+		err = dec.Decode(r)
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can't decode record #%d: %w", len(result)+1, err)
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Main returns records from the chat itself (without threads)
+func (r Records) Main() Records {
+	var result Records
+
+	for _, rr := range r {
+		if rr.ThreadID == 0 {
+			result = append(result, rr)
+		}
+	}
+
+	return result
+}
+
+// Thread returns records from thread with given ID
+func (r Records) Thread(threadID uint) Records {
+	var result Records
+
+	for _, rr := range r {
+		if rr.ThreadID == threadID && threadID != 0 {
+			result = append(result, rr)
+		}
+	}
+
+	return result
+}
+
+// UserIDs returns IDs of all users mentioned in records as authors or
+// reactions owners
+func (r Records) UserIDs() []uint {
+	var result []uint
+
+	for _, rr := range r {
+		if rr.Message != nil && rr.UserID != 0 {
+			result = append(result, rr.UserID)
+		}
+
+		for _, rc := range rr.Reactions {
+			result = append(result, rc.UserID)
+		}
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// exportMessages exports messages of chat
+func (e *Exporter) exportMessages(chatID uint, dir string) error {
+	cp, err := ReadCheckpoint(dir)
+
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(filepath.Join(dir, FILE_MESSAGES), os.O_CREATE|os.O_RDWR, 0640)
+
+	if err != nil {
+		return fmt.Errorf("can't open messages file: %w", err)
+	}
+
+	defer fd.Close()
+
+	// Drop data written after the latest checkpoint
+	err = fd.Truncate(cp.Offset)
+
+	if err == nil {
+		_, err = fd.Seek(cp.Offset, io.SeekStart)
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't restore messages file state: %w", err)
+	}
+
+	if cp.Threads == nil {
+		cp.Threads = map[uint]*ThreadCheckpoint{}
+	}
+
+	cp.IsComplete = false
+	knownThreads := slices.Sorted(maps.Keys(cp.Threads))
+	paginator := e.client.PaginateMessages(chatID, e.getBatchSize(), pachca.SORT_ORDER_ASC)
+
+	for messages := range paginator.Pages {
+		for _, msg := range messages {
+			if msg.ID <= cp.LastMessageID {
+				continue
+			}
+
+			err = e.exportMessage(fd, dir, cp, msg, 0)
+
+			if err != nil {
+				return err
+			}
+
+			cp.LastMessageID = msg.ID
+
+			err = saveCheckpoint(fd, dir, cp)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if paginator.Error() != nil {
+		return fmt.Errorf("can't fetch messages: %w", paginator.Error())
+	}
+
+	// Threads of messages exported before can have new replies
+	for _, threadID := range knownThreads {
+		if !e.WithThreads {
+			break
+		}
+
+		err = e.exportThread(fd, dir, cp, threadID)
+
+		if err != nil {
+			return err
+		}
+
+		err = saveCheckpoint(fd, dir, cp)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	cp.IsComplete = true
+
+	err = jsonutil.Write(filepath.Join(dir, FILE_CHECKPOINT), cp, 0640)
+
+	if err != nil {
+		return fmt.Errorf("can't save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// exportMessage exports single message and its thread
+func (e *Exporter) exportMessage(w io.Writer, dir string, cp *Checkpoint, msg *pachca.Message, threadID uint) error {
+	record := &Record{Message: msg, ThreadID: threadID}
+
+	if e.WithReactions {
+		reactions, err := e.client.GetReactions(msg.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Reactions = reactions
+	}
+
+	if e.WithReads {
+		reads, err := e.client.GetMessageReads(msg.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.ReadCount = len(reads)
+	}
+
+	if e.WithFiles {
+		for _, f := range msg.Files {
+			file, err := e.downloadFile(dir, msg.ID, f)
+
+			if err != nil {
+				return err
+			}
+
+			record.LocalFiles = append(record.LocalFiles, file)
+		}
+	}
+
+	data, err := json.Marshal(record)
+
+	if err != nil {
+		return fmt.Errorf("can't encode message %d: %w", msg.ID, err)
+	}
+
+	_, err = w.Write(append(data, '\n'))
+
+	if err != nil {
+		return fmt.Errorf("can't write message %d: %w", msg.ID, err)
+	}
+
+	if !e.WithThreads || msg.Thread == nil || threadID != 0 {
+		return nil
+	}
+
+	thread := msg.Thread
+
+	if thread.ChatID == 0 {
+		thread, err = e.client.GetThread(thread.ID)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if cp.Threads[thread.ID] == nil {
+		cp.Threads[thread.ID] = &ThreadCheckpoint{ChatID: thread.ChatID}
+	}
+
+	return e.exportThread(w, dir, cp, thread.ID)
+}
+
+// exportThread exports replies from thread which were not exported before
+func (e *Exporter) exportThread(w io.Writer, dir string, cp *Checkpoint, threadID uint) error {
+	tcp := cp.Threads[threadID]
+	paginator := e.client.PaginateMessages(tcp.ChatID, e.getBatchSize(), pachca.SORT_ORDER_ASC)
+
+	for messages := range paginator.Pages {
+		for _, m := range messages {
+			if m.ID <= tcp.LastMessageID {
+				continue
+			}
+
+			err := e.exportMessage(w, dir, cp, m, threadID)
+
+			if err != nil {
+				return err
+			}
+
+			tcp.LastMessageID = m.ID
+		}
+	}
+
+	if paginator.Error() != nil {
+		return fmt.Errorf("can't fetch messages of thread %d: %w", threadID, paginator.Error())
+	}
+
+	return nil
+}
+
+// exportUsers saves info about chat members and authors of messages
+func (e *Exporter) exportUsers(chatID uint, dir string, archive *Archive) error {
+	users, err := e.client.GetChatUsers(chatID, pachca.CHAT_ROLE_ANY)
+
+	if err != nil {
+		return err
+	}
+
+	for _, id := range archive.Records.UserIDs() {
+		if users.Get(id) != nil {
+			continue
+		}
+
+		user := archive.Users.Get(id)
+
+		if user == nil {
+			user, err = e.client.GetUser(id)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	archive.Users = users
+
+	err = jsonutil.Write(filepath.Join(dir, FILE_USERS), users, 0640)
+
+	if err != nil {
+		return fmt.Errorf("can't save users info: %w", err)
+	}
+
+	return nil
+}
+
+// downloadFile downloads message attachment
+func (e *Exporter) downloadFile(dir string, messageID uint, f *pachca.File) (string, error) {
+	name := fmt.Sprintf(
+		"%d_%d_%s", messageID, f.ID,
+		unsafeNameRegex.ReplaceAllString(f.Name, "_"),
+	)
+
+	file := filepath.Join(dir, DIR_FILES, name)
+	local := DIR_FILES + "/" + name
+
+	if f.URL == "" {
+		return "", nil
+	}
+
+	_, err := os.Stat(file)
+
+	if err == nil {
+		return local, nil
+	}
+
+	resp, err := e.client.Engine().Get(req.Request{URL: f.URL, AutoDiscard: true})
+
+	if err != nil {
+		return "", fmt.Errorf("can't download file %q: %w", f.Name, err)
+	}
+
+	if resp.StatusCode != req.STATUS_OK {
+		return "", fmt.Errorf("can't download file %q: server returned status code %d", f.Name, resp.StatusCode)
+	}
+
+	err = resp.Save(file+".tmp", 0640)
+
+	if err == nil {
+		err = os.Rename(file+".tmp", file)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("can't save file %q: %w", f.Name, err)
+	}
+
+	return local, nil
+}
+
+// getBatchSize returns size of messages batch
+func (e *Exporter) getBatchSize() int {
+	return min(max(e.BatchSize, 1), pachca.MAX_PER_PAGE)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// saveCheckpoint saves checkpoint with current offset of messages file
+func saveCheckpoint(fd *os.File, dir string, cp *Checkpoint) error {
+	var err error
+
+	cp.Offset, err = fd.Seek(0, io.SeekCurrent)
+
+	if err == nil {
+		err = fd.Sync()
+	}
+
+	if err == nil {
+		err = jsonutil.Write(filepath.Join(dir, FILE_CHECKPOINT), cp, 0640)
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile writes file using given writer function
+func writeFile(file string, fn func(w io.Writer) error) error {
+	fd, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(fd)
+	err = fn(bw)
+
+	if err == nil {
+		err = bw.Flush()
+	}
+
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	return fd.Close()
+}
@@ -0,0 +1,280 @@
+package export
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const TOKEN = "YQlf-6Vce7jM1RMZZUs_iWKYPt24PeR4c7k_RwzqjI5"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ExportSuite struct {
+	srv       *httptest.Server
+	failPage  bool
+	withReply bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ExportSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ExportSuite) SetUpSuite(c *C) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/shared/v1/chats/10", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, &pachca.Chat{ID: 10, Name: "Compliance <Ops>"}, "")
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/chats/10/members", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, pachca.Users{{ID: 1, FirstName: "John", LastName: "Doe"}}, "")
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/users/2", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, &pachca.User{ID: 2, Nickname: "bob"}, "")
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/threads/500", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, &pachca.Thread{ID: 500, ChatID: 100}, "")
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("chat_id") {
+		case "10":
+			if r.URL.Query().Get("cursor") == "" {
+				writeData(w, pachca.Messages{
+					{ID: 1, UserID: 1, Content: "Hello"},
+					{ID: 2, UserID: 2, Content: "Report", Thread: &pachca.Thread{ID: 500}},
+				}, "page2")
+				return
+			}
+
+			if s.failPage {
+				w.WriteHeader(500)
+				return
+			}
+
+			writeData(w, pachca.Messages{
+				{ID: 3, UserID: 1, Content: "File", Files: pachca.Files{
+					{ID: 7, Name: "report 1.txt", URL: s.srv.URL + "/files/report.txt"},
+				}},
+			}, "")
+
+		case "100":
+			replies := pachca.Messages{{ID: 50, UserID: 1, Content: "Thread reply"}}
+
+			if s.withReply {
+				replies = append(replies, &pachca.Message{ID: 51, UserID: 2, Content: "Late reply"})
+			}
+
+			writeData(w, replies, "")
+		}
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/messages/{id}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "1" {
+			writeData(w, pachca.Reactions{{UserID: 2, Emoji: "👍"}, {UserID: 1, Emoji: "👍"}}, "")
+			return
+		}
+
+		writeData(w, pachca.Reactions{}, "")
+	})
+
+	mux.HandleFunc("GET /api/shared/v1/messages/{id}/read_member_ids", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, []uint{1, 2}, "")
+	})
+
+	mux.HandleFunc("GET /files/report.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("REPORT"))
+	})
+
+	s.srv = httptest.NewServer(mux)
+}
+
+func (s *ExportSuite) TearDownSuite(c *C) {
+	s.srv.Close()
+}
+
+func (s *ExportSuite) TestExport(c *C) {
+	_, err := New(nil, "/tmp")
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(newClient(s.srv), "")
+	c.Assert(err, Equals, ErrEmptyDir)
+
+	e, err := New(newClient(s.srv), c.MkDir())
+	c.Assert(err, IsNil)
+
+	e.WithFiles, e.Markdown, e.HTML = true, true, true
+
+	c.Assert(e.Export(0), Equals, ErrInvalidChatID)
+
+	s.failPage = true
+	c.Assert(e.Export(10), NotNil)
+
+	cp, err := ReadCheckpoint(e.ChatDir(10))
+	c.Assert(err, IsNil)
+	c.Assert(cp.LastMessageID, Equals, uint(2))
+	c.Assert(cp.IsComplete, Equals, false)
+
+	s.failPage = false
+	c.Assert(e.Export(10), IsNil)
+
+	cp, err = ReadCheckpoint(e.ChatDir(10))
+	c.Assert(err, IsNil)
+	c.Assert(cp.LastMessageID, Equals, uint(3))
+	c.Assert(cp.IsComplete, Equals, true)
+
+	// Nothing new, so export must not duplicate messages
+	c.Assert(e.Export(10), IsNil)
+
+	a, err := Load(e.ChatDir(10))
+	c.Assert(err, IsNil)
+	c.Assert(a.Chat.Name, Equals, "Compliance <Ops>")
+	c.Assert(a.Users, HasLen, 2)
+	c.Assert(a.Records, HasLen, 4)
+	c.Assert(a.Records.Main(), HasLen, 3)
+	c.Assert(a.Records.Thread(500), HasLen, 1)
+	c.Assert(a.Records.Thread(0), HasLen, 0)
+	c.Assert(a.Records.UserIDs(), DeepEquals, []uint{1, 2})
+	c.Assert(a.Records[0].Reactions, HasLen, 2)
+	c.Assert(a.Records[0].ReadCount, Equals, 2)
+	c.Assert(a.Records[3].LocalFiles, DeepEquals, []string{"files/3_7_report_1.txt"})
+
+	data, err := os.ReadFile(e.ChatDir(10) + "/files/3_7_report_1.txt")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "REPORT")
+
+	md, err := os.ReadFile(e.ChatDir(10) + "/" + FILE_MARKDOWN)
+	c.Assert(err, IsNil)
+	c.Assert(string(md), Matches, `(?s)# Compliance <Ops>.*\*\*John Doe\*\*.*Hello.*👍 2.*\*\*bob\*\*.*Report.*> \*\*John Doe\*\*.*> Thread reply.*📎 \[3_7_report_1.txt\]\(files/3_7_report_1.txt\).*`)
+
+	page, err := os.ReadFile(e.ChatDir(10) + "/" + FILE_HTML)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(page), "<h1>Compliance &lt;Ops&gt;</h1>"), Equals, true)
+	c.Assert(strings.Contains(string(page), `<div class="thread">`), Equals, true)
+
+	// New replies in threads of exported messages must be exported too
+	s.withReply = true
+	defer func() { s.withReply = false }()
+
+	c.Assert(e.Export(10), IsNil)
+	c.Assert(e.Export(10), IsNil)
+
+	cp, err = ReadCheckpoint(e.ChatDir(10))
+	c.Assert(err, IsNil)
+	c.Assert(cp.Threads[500], DeepEquals, &ThreadCheckpoint{ChatID: 100, LastMessageID: 51})
+
+	a, err = Load(e.ChatDir(10))
+	c.Assert(err, IsNil)
+	c.Assert(a.Records, HasLen, 5)
+	c.Assert(a.Records.Thread(500), HasLen, 2)
+	c.Assert(a.Records[4].Content, Equals, "Late reply")
+
+	md, err = os.ReadFile(e.ChatDir(10) + "/" + FILE_MARKDOWN)
+	c.Assert(err, IsNil)
+	c.Assert(string(md), Matches, `(?s).*> Thread reply.*> Late reply.*📎.*`)
+
+	var ne *Exporter
+	c.Assert(ne.Export(1), Equals, ErrNilExporter)
+	c.Assert(ne.ChatDir(1), Equals, "")
+
+	var na *Archive
+	c.Assert(na.WriteMarkdown(nil), NotNil)
+	c.Assert(na.WriteHTML(nil), NotNil)
+}
+
+func (s *ExportSuite) TestRenderDeleted(c *C) {
+	a := &Archive{
+		Chat: &pachca.Chat{Name: "Test"},
+		Records: Records{
+			{Message: &pachca.Message{ID: 1, UserID: 9, Content: "secret", DeletedAt: pachca.Date{Time: pachca.Date{}.Time.AddDate(2000, 0, 0)}}},
+			{Message: &pachca.Message{ID: 2, UserID: 8, Content: "edited", ChangedAt: pachca.Date{Time: pachca.Date{}.Time.AddDate(2000, 0, 0)}}},
+		},
+		Users: pachca.Users{{ID: 8, Email: "a@domain.com"}},
+	}
+
+	var sb strings.Builder
+
+	c.Assert(a.WriteMarkdown(&sb), IsNil)
+	c.Assert(sb.String(), Matches, `(?s).*User #9.*_Message deleted_.*a@domain.com.*edited.*`)
+	c.Assert(strings.Contains(sb.String(), "secret"), Equals, false)
+
+	sb.Reset()
+
+	c.Assert(a.WriteHTML(&sb), IsNil)
+	c.Assert(strings.Contains(sb.String(), "Message deleted"), Equals, true)
+}
+
+func (s *ExportSuite) TestErrors(c *C) {
+	_, err := Load(c.MkDir())
+	c.Assert(err, NotNil)
+
+	dir := c.MkDir()
+	os.WriteFile(dir+"/"+FILE_CHAT, []byte(`{}`), 0644)
+	os.WriteFile(dir+"/"+FILE_MESSAGES, []byte(`{broken`), 0644)
+
+	_, err = Load(dir)
+	c.Assert(err, NotNil)
+
+	os.WriteFile(dir+"/"+FILE_CHECKPOINT, []byte(`{broken`), 0644)
+	_, err = ReadCheckpoint(dir)
+	c.Assert(err, NotNil)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newClient(srv *httptest.Server) *pachca.Client {
+	client, _ := pachca.NewClient(TOKEN)
+	target, _ := url.Parse(srv.URL)
+
+	client.Engine().Client = &http.Client{Transport: &rewriteTransport{target}}
+
+	return client
+}
+
+func writeData(w http.ResponseWriter, data any, nextPage string) {
+	resp := map[string]any{"data": data}
+
+	if nextPage != "" {
+		resp["meta"] = map[string]any{
+			"paginate": map[string]any{"next_page": nextPage, "has_next": true},
+		}
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,243 @@
+package export
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DATE_LAYOUT is layout used for dates in rendered documents
+const DATE_LAYOUT = "2006-01-02 15:04 MST"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Archive contains exported chat data
+type Archive struct {
+	Chat    *pachca.Chat
+	Users   pachca.Users
+	Records Records
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// htmlTemplate is template for HTML version of the chat
+var htmlTemplate = template.Must(template.New("chat").Funcs(template.FuncMap{
+	"thread": func(a *Archive, id uint) Records { return a.Records.Thread(id) },
+	"name":   func(a *Archive, id uint) string { return a.userName(id) },
+	"date":   func(d pachca.Date) string { return d.UTC().Format(DATE_LAYOUT) },
+	"react":  func(r pachca.Reactions) string { return formatReactions(r) },
+	"base":   filepath.Base,
+	"dict":   func(a *Archive, r *Record) map[string]any { return map[string]any{"A": a, "R": r} },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Chat.Name }}</title>
+<style>
+body { font-family: sans-serif; max-width: 860px; margin: 2em auto; color: #222; }
+.msg { border-bottom: 1px solid #eee; padding: .6em 0; }
+.meta { color: #888; font-size: .85em; }
+.content { white-space: pre-wrap; margin: .3em 0; }
+.thread { margin-left: 2em; padding-left: 1em; border-left: 3px solid #ddd; }
+.deleted { color: #aaa; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{ .Chat.Name }}</h1>
+<p class="meta">Created {{ date .Chat.CreatedAt }} · {{ len .Users }} users · {{ len .Records }} messages</p>
+{{- $a := . }}
+{{- range .Records.Main }}
+{{ template "message" (dict $a .) }}
+{{- end }}
+</body>
+</html>
+{{ define "message" -}}
+<div class="msg" id="m{{ .R.ID }}">
+<div class="meta"><b>{{ name .A .R.UserID }}</b> · {{ date .R.CreatedAt }}{{ if not .R.ChangedAt.IsZero }} · edited{{ end }}{{ if .R.ReadCount }} · read by {{ .R.ReadCount }}{{ end }}</div>
+{{- if not .R.DeletedAt.IsZero }}
+<div class="content deleted">Message deleted</div>
+{{- else }}
+<div class="content">{{ .R.Content }}</div>
+{{- end }}
+{{- range .R.LocalFiles }}
+<div>📎 <a href="{{ . }}">{{ base . }}</a></div>
+{{- end }}
+{{- if .R.Reactions }}
+<div class="meta">{{ react .R.Reactions }}</div>
+{{- end }}
+{{- if .R.Thread }}
+<div class="thread">
+{{- $a := .A }}
+{{- range thread .A .R.Thread.ID }}
+{{ template "message" (dict $a .) }}
+{{- end }}
+</div>
+{{- end }}
+</div>
+{{- end }}`))
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Load loads exported chat data from given directory
+func Load(dir string) (*Archive, error) {
+	a := &Archive{Chat: &pachca.Chat{}}
+	err := jsonutil.Read(filepath.Join(dir, FILE_CHAT), a.Chat)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read chat info: %w", err)
+	}
+
+	err = jsonutil.Read(filepath.Join(dir, FILE_USERS), &a.Users)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("can't read users info: %w", err)
+	}
+
+	a.Records, err = ReadRecords(filepath.Join(dir, FILE_MESSAGES))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// WriteMarkdown writes Markdown version of the chat
+func (a *Archive) WriteMarkdown(w io.Writer) error {
+	if a == nil || a.Chat == nil {
+		return fmt.Errorf("archive is empty")
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", a.Chat.Name)
+	fmt.Fprintf(
+		&sb, "_Created %s · %d users · %d messages_\n\n",
+		a.Chat.CreatedAt.UTC().Format(DATE_LAYOUT), len(a.Users), len(a.Records),
+	)
+
+	for _, r := range a.Records.Main() {
+		sb.WriteString("---\n\n")
+		a.writeMarkdownRecord(&sb, r, "")
+
+		if r.Thread == nil {
+			continue
+		}
+
+		for _, tr := range a.Records.Thread(r.Thread.ID) {
+			sb.WriteString("> \n")
+			a.writeMarkdownRecord(&sb, tr, "> ")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// WriteHTML writes static HTML version of the chat
+func (a *Archive) WriteHTML(w io.Writer) error {
+	if a == nil || a.Chat == nil {
+		return fmt.Errorf("archive is empty")
+	}
+
+	return htmlTemplate.Execute(w, a)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// writeMarkdownRecord writes message in markdown format
+func (a *Archive) writeMarkdownRecord(sb *strings.Builder, r *Record, prefix string) {
+	meta := r.CreatedAt.UTC().Format(DATE_LAYOUT)
+
+	if !r.ChangedAt.IsZero() {
+		meta += " · edited"
+	}
+
+	if r.ReadCount > 0 {
+		meta += fmt.Sprintf(" · read by %d", r.ReadCount)
+	}
+
+	fmt.Fprintf(sb, "%s**%s** · %s\n%s\n", prefix, a.userName(r.UserID), meta, prefix)
+
+	content := r.Content
+
+	if !r.DeletedAt.IsZero() {
+		content = "_Message deleted_"
+	}
+
+	for line := range strings.SplitSeq(content, "\n") {
+		fmt.Fprintf(sb, "%s%s\n", prefix, line)
+	}
+
+	for _, f := range r.LocalFiles {
+		fmt.Fprintf(sb, "%s\n%s📎 [%s](%s)\n", prefix, prefix, filepath.Base(f), f)
+	}
+
+	if len(r.Reactions) != 0 {
+		fmt.Fprintf(sb, "%s\n%s%s\n", prefix, prefix, formatReactions(r.Reactions))
+	}
+
+	sb.WriteString("\n")
+}
+
+// userName returns name of user with given ID
+func (a *Archive) userName(id uint) string {
+	user := a.Users.Get(id)
+
+	switch {
+	case user == nil:
+		return fmt.Sprintf("User #%d", id)
+	case user.FullName() != "":
+		return user.FullName()
+	case user.Nickname != "":
+		return user.Nickname
+	}
+
+	return user.Email
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// formatReactions formats reactions summary
+func formatReactions(reactions pachca.Reactions) string {
+	var order []string
+
+	counts := map[string]int{}
+
+	for _, r := range reactions {
+		if counts[r.Emoji] == 0 {
+			order = append(order, r.Emoji)
+		}
+
+		counts[r.Emoji]++
+	}
+
+	result := make([]string, 0, len(order))
+
+	for _, e := range order {
+		result = append(result, fmt.Sprintf("%s %d", e, counts[e]))
+	}
+
+	return strings.Join(result, " · ")
+}
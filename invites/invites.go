@@ -0,0 +1,479 @@
+package invites
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_REMINDER_TEXT is default text of reminder for inviters
+const DEFAULT_REMINDER_TEXT = "These users haven't accepted your invitation yet:\n{invites}"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used for tracking invites
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+	GetUser(userID uint) (*pachca.User, error)
+	SendMessageToUser(userID uint, text string) (*pachca.Message, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Invite contains info about pending invite
+type Invite struct {
+	User    *pachca.User  // Invited user
+	Inviter *pachca.User  // User who sent invite (nil if unknown)
+	Age     time.Duration // Time since invite was sent
+}
+
+// Invites is a slice of invites
+type Invites []*Invite
+
+// Event contains info about confirmed invite
+type Event struct {
+	User    *pachca.User  // User who confirmed invite
+	Inviter *pachca.User  // User who sent invite (nil if unknown)
+	Time    time.Time     // Time when confirmation was detected
+	Age     time.Duration // Time between invite and confirmation
+}
+
+// Watcher tracks pending invites, detects confirmations and reminds inviters
+// about invites which are still pending
+type Watcher struct {
+	// RemindAfter is age of pending invite after which inviter receives
+	// reminder. Reminder is repeated with the same interval while invite is
+	// pending. Zero value disables reminders.
+	RemindAfter time.Duration
+
+	// ReminderText is reminder text. Text can contain {invites} (list of
+	// pending invites) and {count} (number of pending invites) placeholders.
+	ReminderText string
+
+	// OnConfirm is callback for confirmed invites
+	OnConfirm func(e *Event)
+
+	// OnError is callback for errors which occurred during background checks
+	OnError func(err error)
+
+	client Client
+
+	mu       sync.Mutex
+	checkMu  sync.Mutex
+	pending  map[uint]*pachca.User
+	reminded map[uint]time.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient       = errors.New("client is nil")
+	ErrNilWatcher      = errors.New("watcher is nil")
+	ErrNilWebhook      = errors.New("webhook is nil")
+	ErrInvalidInterval = errors.New("check interval must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pending returns pending invites from given users sorted by age (the oldest
+// invites first). Inviters are looked up in the same slice of users.
+func Pending(users pachca.Users, now time.Time) Invites {
+	var result Invites
+
+	for _, user := range users.Invited() {
+		result = append(result, &Invite{
+			User:    user,
+			Inviter: findInviter(users, user),
+			Age:     age(user, now),
+		})
+	}
+
+	slices.SortStableFunc(result, func(i1, i2 *Invite) int {
+		return i1.User.CreatedAt.Compare(i2.User.CreatedAt.Time)
+	})
+
+	return result
+}
+
+// Remind sends every inviter a direct message with the list of their pending
+// invites. Invites without known inviter are ignored.
+func Remind(client Client, invites Invites, text string) error {
+	if client == nil {
+		return ErrNilClient
+	}
+
+	if text == "" {
+		text = DEFAULT_REMINDER_TEXT
+	}
+
+	errs := errors.NewBundle()
+
+	groups := invites.byInviter()
+
+	for _, inviterID := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[inviterID]
+		lines := make([]string, 0, len(group))
+
+		for _, invite := range group {
+			lines = append(lines, "• "+invite.String())
+		}
+
+		_, err := client.SendMessageToUser(inviterID, strings.NewReplacer(
+			"{invites}", strings.Join(lines, "\n"),
+			"{count}", strconv.Itoa(len(group)),
+		).Replace(text))
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't send reminder to user %d: %w", inviterID, err))
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewWatcher creates new invites watcher
+func NewWatcher(client Client) (*Watcher, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Watcher{
+		client:   client,
+		pending:  map[uint]*pachca.User{},
+		reminded: map[uint]time.Time{},
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Days returns invite age in full days
+func (i *Invite) Days() int {
+	if i == nil {
+		return 0
+	}
+
+	return int(i.Age / (24 * time.Hour))
+}
+
+// String returns invite info as a string
+func (i *Invite) String() string {
+	if i == nil || i.User == nil {
+		return ""
+	}
+
+	name := i.User.FullName()
+
+	if name == "" {
+		name = i.User.Email
+	} else if i.User.Email != "" {
+		name += " (" + i.User.Email + ")"
+	}
+
+	switch days := i.Days(); days {
+	case 0:
+		return name + " — invited today"
+	case 1:
+		return name + " — invited 1 day ago"
+	default:
+		return name + " — invited " + strconv.Itoa(days) + " days ago"
+	}
+}
+
+// OlderThan returns invites older than given duration
+func (i Invites) OlderThan(d time.Duration) Invites {
+	var result Invites
+
+	for _, invite := range i {
+		if invite != nil && invite.Age >= d {
+			result = append(result, invite)
+		}
+	}
+
+	return result
+}
+
+// WithInviter returns invites sent by user with given ID
+func (i Invites) WithInviter(inviterID uint) Invites {
+	var result Invites
+
+	for _, invite := range i {
+		if invite != nil && invite.Inviter != nil && invite.Inviter.ID == inviterID {
+			result = append(result, invite)
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pending returns current pending invites
+func (w *Watcher) Pending(now time.Time) (Invites, error) {
+	if w == nil {
+		return nil, ErrNilWatcher
+	}
+
+	users, err := w.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	return Pending(users, now), nil
+}
+
+// Check fetches users, detects confirmed invites and sends reminders about
+// pending ones. The first check only collects pending invites, so invites
+// confirmed before it are not reported.
+func (w *Watcher) Check(now time.Time) ([]*Event, error) {
+	if w == nil {
+		return nil, ErrNilWatcher
+	}
+
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	users, err := w.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	var events []*Event
+
+	w.mu.Lock()
+
+	for id := range w.pending {
+		user := users.Get(id)
+
+		if user != nil && user.InviteStatus == pachca.INVITE_CONFIRMED {
+			events = append(events, newEvent(users, user, now))
+		}
+	}
+
+	invites := Pending(users, now)
+	w.pending = map[uint]*pachca.User{}
+
+	for _, invite := range invites {
+		w.pending[invite.User.ID] = invite.User
+	}
+
+	for id := range w.reminded {
+		if w.pending[id] == nil {
+			delete(w.reminded, id)
+		}
+	}
+
+	w.mu.Unlock()
+
+	slices.SortStableFunc(events, func(e1, e2 *Event) int {
+		return cmp.Compare(e1.User.ID, e2.User.ID)
+	})
+
+	w.notify(events)
+
+	return events, w.remind(invites, now)
+}
+
+// HandleWebhook handles organization members webhook and returns events for
+// users who have confirmed invites. Only invites which are pending since the
+// last check are reported, so confirmations already reported by check are not
+// reported twice.
+func (w *Watcher) HandleWebhook(m *webhook.OrgMember) ([]*Event, error) {
+	switch {
+	case w == nil:
+		return nil, ErrNilWatcher
+	case m == nil:
+		return nil, ErrNilWebhook
+	case m.Event != webhook.EVENT_CONFIRM:
+		return nil, nil
+	}
+
+	now := m.CreatedAt.Time
+
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var events []*Event
+
+	errs := errors.NewBundle()
+
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	for _, id := range m.UserIDs {
+		w.mu.Lock()
+		isPending := w.pending[id] != nil
+		w.mu.Unlock()
+
+		if !isPending {
+			continue
+		}
+
+		user, err := w.client.GetUser(id)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't get user %d: %w", id, err))
+			continue
+		}
+
+		var inviter *pachca.User
+
+		if user.InviterID != 0 {
+			inviter, err = w.client.GetUser(user.InviterID)
+
+			if err != nil {
+				errs.Add(fmt.Errorf("can't get inviter %d: %w", user.InviterID, err))
+			}
+		}
+
+		events = append(events, &Event{User: user, Inviter: inviter, Time: now, Age: age(user, now)})
+
+		w.mu.Lock()
+		delete(w.pending, id)
+		delete(w.reminded, id)
+		w.mu.Unlock()
+	}
+
+	w.notify(events)
+
+	if !errs.IsEmpty() {
+		return events, errs.Join()
+	}
+
+	return events, nil
+}
+
+// Run periodically checks invites until stop channel is closed
+func (w *Watcher) Run(interval time.Duration, stop <-chan struct{}) error {
+	switch {
+	case w == nil:
+		return ErrNilWatcher
+	case interval <= 0:
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			_, err := w.Check(now)
+
+			if err != nil && w.OnError != nil {
+				w.OnError(err)
+			}
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// remind sends reminders about invites which are due
+func (w *Watcher) remind(invites Invites, now time.Time) error {
+	if w.RemindAfter <= 0 {
+		return nil
+	}
+
+	var due Invites
+
+	w.mu.Lock()
+
+	for _, invite := range invites.OlderThan(w.RemindAfter) {
+		last, ok := w.reminded[invite.User.ID]
+
+		if invite.Inviter != nil && (!ok || now.Sub(last) >= w.RemindAfter) {
+			due = append(due, invite)
+			w.reminded[invite.User.ID] = now
+		}
+	}
+
+	w.mu.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	return Remind(w.client, due, w.ReminderText)
+}
+
+// notify passes events to callback
+func (w *Watcher) notify(events []*Event) {
+	if w.OnConfirm == nil {
+		return
+	}
+
+	for _, e := range events {
+		w.OnConfirm(e)
+	}
+}
+
+// byInviter groups invites by inviter ID
+func (i Invites) byInviter() map[uint]Invites {
+	result := map[uint]Invites{}
+
+	for _, invite := range i {
+		if invite != nil && invite.Inviter != nil && invite.Inviter.ID != 0 {
+			result[invite.Inviter.ID] = append(result[invite.Inviter.ID], invite)
+		}
+	}
+
+	return result
+}
+
+// newEvent creates confirmation event for user
+func newEvent(users pachca.Users, user *pachca.User, now time.Time) *Event {
+	return &Event{
+		User:    user,
+		Inviter: findInviter(users, user),
+		Time:    now,
+		Age:     age(user, now),
+	}
+}
+
+// findInviter returns user who invited given user
+func findInviter(users pachca.Users, user *pachca.User) *pachca.User {
+	if user.InviterID == 0 {
+		return nil
+	}
+
+	return users.Get(user.InviterID)
+}
+
+// age returns time since user creation
+func age(user *pachca.User, now time.Time) time.Duration {
+	if user.CreatedAt.IsZero() {
+		return 0
+	}
+
+	return max(now.Sub(user.CreatedAt.Time), 0)
+}
@@ -0,0 +1,326 @@
+package invites
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type InvitesSuite struct{}
+
+type fakeClient struct {
+	users    pachca.Users
+	messages map[uint][]string
+	failSend uint
+	failGet  uint
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&InvitesSuite{})
+
+var _ Client = (*pachca.Client)(nil)
+
+var now = time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *InvitesSuite) TestPending(c *C) {
+	invites := Pending(testUsers(), now)
+
+	c.Assert(invites, HasLen, 3)
+	c.Assert(invites[0].User.ID, Equals, uint(4))
+	c.Assert(invites[0].Inviter.ID, Equals, uint(1))
+	c.Assert(invites[0].Days(), Equals, 10)
+	c.Assert(invites[0].String(), Equals, "John Doe (john@domain.com) — invited 10 days ago")
+	c.Assert(invites[1].User.ID, Equals, uint(3))
+	c.Assert(invites[1].String(), Equals, "bob@domain.com — invited 1 day ago")
+	c.Assert(invites[2].User.ID, Equals, uint(5))
+	c.Assert(invites[2].Inviter, IsNil)
+	c.Assert(invites[2].String(), Equals, "Alice (alice@domain.com) — invited today")
+
+	c.Assert(invites.OlderThan(24*time.Hour), HasLen, 2)
+	c.Assert(invites.WithInviter(1), HasLen, 1)
+	c.Assert(invites.WithInviter(2), HasLen, 1)
+	c.Assert(invites.WithInviter(3), HasLen, 0)
+
+	var i *Invite
+
+	c.Assert(i.Days(), Equals, 0)
+	c.Assert(i.String(), Equals, "")
+	c.Assert(age(&pachca.User{}, now), Equals, time.Duration(0))
+}
+
+func (s *InvitesSuite) TestRemind(c *C) {
+	client := newFakeClient()
+	invites := Pending(testUsers(), now)
+
+	c.Assert(Remind(nil, invites, ""), Equals, ErrNilClient)
+	c.Assert(Remind(client, invites, ""), IsNil)
+	c.Assert(client.messages, HasLen, 2)
+	c.Assert(client.messages[1], DeepEquals, []string{
+		"These users haven't accepted your invitation yet:\n• John Doe (john@domain.com) — invited 10 days ago",
+	})
+
+	client.failSend = 2
+
+	err := Remind(client, invites, "{count} pending: {invites}")
+	c.Assert(err, ErrorMatches, `can't send reminder to user 2: send error`)
+	c.Assert(client.messages[1][1], Equals, "1 pending: • John Doe (john@domain.com) — invited 10 days ago")
+}
+
+func (s *InvitesSuite) TestWatcher(c *C) {
+	client := newFakeClient()
+	client.users = testUsers()
+
+	_, err := NewWatcher(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	w, err := NewWatcher(client)
+	c.Assert(err, IsNil)
+
+	var confirmed []uint
+
+	w.RemindAfter = 7 * 24 * time.Hour
+	w.OnConfirm = func(e *Event) { confirmed = append(confirmed, e.User.ID) }
+
+	invites, err := w.Pending(now)
+	c.Assert(err, IsNil)
+	c.Assert(invites, HasLen, 3)
+
+	events, err := w.Check(now)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 0)
+	c.Assert(client.messages[1], HasLen, 1)
+	c.Assert(client.messages[2], HasLen, 0)
+
+	// Reminder isn't repeated until interval has passed
+	client.users[3].InviteStatus = pachca.INVITE_CONFIRMED
+
+	events, err = w.Check(now.Add(time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].User.ID, Equals, uint(4))
+	c.Assert(events[0].Inviter.ID, Equals, uint(1))
+	c.Assert(events[0].Age, Equals, 10*24*time.Hour+time.Hour)
+	c.Assert(confirmed, DeepEquals, []uint{4})
+	c.Assert(client.messages[1], HasLen, 1)
+
+	events, err = w.Check(now.AddDate(0, 0, 7))
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 0)
+	c.Assert(client.messages[2], HasLen, 1)
+	c.Assert(client.messages[1], HasLen, 1)
+
+	client.failSend = 2
+
+	_, err = w.Check(now.AddDate(0, 0, 14))
+	c.Assert(err, ErrorMatches, `can't send reminder to user 2: send error`)
+
+	client.users = nil
+
+	_, err = w.Pending(now)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+	_, err = w.Check(now)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+
+	c.Assert(w.Run(0, nil), Equals, ErrInvalidInterval)
+
+	var errs []error
+	stop := make(chan struct{})
+
+	w.OnError = func(err error) { errs = append(errs, err) }
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+	}()
+
+	c.Assert(w.Run(10*time.Millisecond, stop), IsNil)
+	c.Assert(errs, Not(HasLen), 0)
+}
+
+func (s *InvitesSuite) TestHandleWebhook(c *C) {
+	client := newFakeClient()
+	client.users = testUsers()
+
+	w, err := NewWatcher(client)
+	c.Assert(err, IsNil)
+
+	var confirmed []uint
+
+	w.OnConfirm = func(e *Event) { confirmed = append(confirmed, e.User.ID) }
+
+	_, err = w.Check(now)
+	c.Assert(err, IsNil)
+	c.Assert(w.pending, HasLen, 3)
+
+	events, err := w.HandleWebhook(&webhook.OrgMember{Event: webhook.EVENT_INVITE, UserIDs: []uint{4}})
+	c.Assert(err, IsNil)
+	c.Assert(events, IsNil)
+
+	events, err = w.HandleWebhook(&webhook.OrgMember{
+		Event: webhook.EVENT_CONFIRM, UserIDs: []uint{4, 5},
+		CreatedAt: pachca.Date{Time: now.Add(time.Hour)},
+	})
+	c.Assert(err, ErrorMatches, `can't get inviter 99: user not found`)
+	c.Assert(events, HasLen, 2)
+	c.Assert(events[0].Inviter.ID, Equals, uint(1))
+	c.Assert(events[0].Age, Equals, 10*24*time.Hour+time.Hour)
+	c.Assert(events[1].Inviter, IsNil)
+	c.Assert(confirmed, DeepEquals, []uint{4, 5})
+	c.Assert(w.pending, HasLen, 1)
+
+	client.failGet = 2
+
+	events, err = w.HandleWebhook(&webhook.OrgMember{Event: webhook.EVENT_CONFIRM, UserIDs: []uint{3, 10}})
+	c.Assert(err, ErrorMatches, `can't get inviter 2: get error`)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].Inviter, IsNil)
+	c.Assert(events[0].Time.IsZero(), Equals, false)
+
+	c.Assert(confirmed, DeepEquals, []uint{4, 5, 3})
+
+	_, err = w.HandleWebhook(nil)
+	c.Assert(err, Equals, ErrNilWebhook)
+
+	w = nil
+
+	_, err = w.HandleWebhook(&webhook.OrgMember{})
+	c.Assert(err, Equals, ErrNilWatcher)
+	_, err = w.Check(now)
+	c.Assert(err, Equals, ErrNilWatcher)
+	_, err = w.Pending(now)
+	c.Assert(err, Equals, ErrNilWatcher)
+	c.Assert(w.Run(time.Second, nil), Equals, ErrNilWatcher)
+}
+
+func (s *InvitesSuite) TestWebhookAfterCheck(c *C) {
+	client := newFakeClient()
+	client.users = testUsers()
+
+	w, _ := NewWatcher(client)
+
+	var confirmed []uint
+
+	w.OnConfirm = func(e *Event) { confirmed = append(confirmed, e.User.ID) }
+
+	events, err := w.HandleWebhook(&webhook.OrgMember{Event: webhook.EVENT_CONFIRM, UserIDs: []uint{4}})
+	c.Assert(err, IsNil)
+	c.Assert(events, IsNil)
+
+	_, err = w.Check(now)
+	c.Assert(err, IsNil)
+
+	client.users.Get(4).InviteStatus = pachca.INVITE_CONFIRMED
+	client.failGet = 5
+
+	events, err = w.Check(now.Add(time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+
+	events, err = w.HandleWebhook(&webhook.OrgMember{Event: webhook.EVENT_CONFIRM, UserIDs: []uint{4, 5}})
+	c.Assert(err, ErrorMatches, `can't get user 5: get error`)
+	c.Assert(events, IsNil)
+	c.Assert(w.pending, HasLen, 2)
+
+	client.failGet = 0
+
+	events, err = w.HandleWebhook(&webhook.OrgMember{Event: webhook.EVENT_CONFIRM, UserIDs: []uint{4, 5}})
+	c.Assert(err, ErrorMatches, `can't get inviter 99: user not found`)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].User.ID, Equals, uint(5))
+	c.Assert(confirmed, DeepEquals, []uint{4, 5})
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{messages: map[uint][]string{}}
+}
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.users == nil {
+		return nil, errors.New("users error")
+	}
+
+	return c.users, nil
+}
+
+func (c *fakeClient) GetUser(userID uint) (*pachca.User, error) {
+	if userID == c.failGet {
+		return nil, errors.New("get error")
+	}
+
+	user := c.users.Get(userID)
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func (c *fakeClient) SendMessageToUser(userID uint, text string) (*pachca.Message, error) {
+	if userID == c.failSend {
+		return nil, errors.New("send error")
+	}
+
+	c.messages[userID] = append(c.messages[userID], text)
+
+	return &pachca.Message{ID: 1}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func testUsers() pachca.Users {
+	return pachca.Users{
+		{
+			ID: 1, FirstName: "Jane", Email: "jane@domain.com",
+			InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt:    pachca.Date{Time: now.AddDate(-1, 0, 0)},
+		},
+		{
+			ID: 2, FirstName: "Mark", Email: "mark@domain.com",
+			InviteStatus: pachca.INVITE_CONFIRMED,
+			CreatedAt:    pachca.Date{Time: now.AddDate(-1, 0, 0)},
+		},
+		{
+			ID: 3, Email: "bob@domain.com", InviterID: 2,
+			InviteStatus: pachca.INVITE_SENT,
+			CreatedAt:    pachca.Date{Time: now.Add(-36 * time.Hour)},
+		},
+		{
+			ID: 4, FirstName: "John", LastName: "Doe", Email: "john@domain.com", InviterID: 1,
+			InviteStatus: pachca.INVITE_SENT,
+			CreatedAt:    pachca.Date{Time: now.AddDate(0, 0, -10)},
+		},
+		{
+			ID: 5, FirstName: "Alice", Email: "alice@domain.com", InviterID: 99,
+			InviteStatus: pachca.INVITE_SENT,
+			CreatedAt:    pachca.Date{Time: now.Add(-time.Hour)},
+		},
+		{
+			ID: 6, Email: "eve@domain.com", InviterID: 1,
+			InviteStatus: pachca.INVITE_SENT, IsSuspended: true,
+			CreatedAt: pachca.Date{Time: now.AddDate(0, 0, -30)},
+		},
+	}
+}
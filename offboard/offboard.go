@@ -0,0 +1,423 @@
+package offboard
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	ACTION_ADD_SUCCESSOR Action = iota + 1 // Add successor to chat
+	ACTION_GRANT_ADMIN                     // Grant admin role in chat to successor
+	ACTION_EXCLUDE                         // Exclude user from chat
+	ACTION_REMOVE_TAGS                     // Remove all group tags of user
+	ACTION_CLEAR_STATUS                    // Remove user status
+	ACTION_SUSPEND                         // Suspend user
+	ACTION_AUDIT_NOTE                      // Send audit note to chat
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used for offboarding
+type Client interface {
+	GetUser(userID uint) (*pachca.User, error)
+	GetChats(filter ...pachca.ChatFilter) (pachca.Chats, error)
+	GetChatUsers(chatID uint, memberRole pachca.ChatRole) (pachca.Users, error)
+	AddChatUsers(chatID uint, membersIDs []uint, silent bool) error
+	SetChatUserRole(chatID, userID uint, role pachca.ChatRole) error
+	ExcludeChatUser(chatID, userID uint) error
+	SetUserTags(userID uint, tags []string) error
+	DeleteStatus(userID uint) error
+	SuspendUser(userID uint) error
+	SendMessageToChat(chatID uint, text string) (*pachca.Message, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Action is offboarding action
+type Action uint8
+
+// Step is single offboarding step
+type Step struct {
+	Action Action
+	Chat   *pachca.Chat // Target chat (only for chat actions)
+	Tags   []string     // Removed group tags (only for ACTION_REMOVE_TAGS)
+	IsDone bool         // Step was successfully executed
+	Err    error        // Step execution error
+}
+
+// Plan contains offboarding steps for user
+type Plan struct {
+	User      *pachca.User
+	Successor *pachca.User
+	Steps     []*Step
+	IsDryRun  bool
+}
+
+// Offboarder plans and executes offboarding of departing users: transfers
+// admin roles in their chats to successor, excludes them from chats, removes
+// group tags and status, suspends them and leaves an audit note.
+//
+// Pachca API doesn't allow transferring chat ownership, so in chats owned by
+// departing user the successor receives admin role.
+type Offboarder struct {
+	// SuccessorID is ID of user who receives admin roles in chats where
+	// departing user is owner or admin. Chats are left without new admin
+	// if it's not set.
+	SuccessorID uint
+
+	// AuditChatID is ID of chat for audit note (no note if it's not set)
+	AuditChatID uint
+
+	// DryRun disables execution of plan, so Offboard only returns plan
+	DryRun bool
+
+	client Client
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient         = errors.New("client is nil")
+	ErrNilOffboarder     = errors.New("offboarder is nil")
+	ErrNilPlan           = errors.New("plan is nil")
+	ErrInvalidUserID     = errors.New("user ID must be greater than 0")
+	ErrSameSuccessor     = errors.New("successor can't be the departing user")
+	ErrInactiveSuccessor = errors.New("successor must be an active user")
+	ErrTransferFailed    = errors.New("skipped because role transfer failed")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new offboarder
+func New(client Client) (*Offboarder, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Offboarder{client: client}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Offboard plans offboarding of user with given ID and executes the plan
+// unless dry-run mode is enabled
+func (o *Offboarder) Offboard(userID uint) (*Plan, error) {
+	plan, err := o.Plan(userID)
+
+	if err != nil || plan.IsDryRun {
+		return plan, err
+	}
+
+	return plan, o.Execute(plan)
+}
+
+// Plan returns offboarding plan for user with given ID
+func (o *Offboarder) Plan(userID uint) (*Plan, error) {
+	switch {
+	case o == nil:
+		return nil, ErrNilOffboarder
+	case userID == 0:
+		return nil, ErrInvalidUserID
+	case userID == o.SuccessorID:
+		return nil, ErrSameSuccessor
+	}
+
+	user, err := o.client.GetUser(userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get user %d: %w", userID, err)
+	}
+
+	plan := &Plan{User: user, IsDryRun: o.DryRun}
+
+	if o.SuccessorID != 0 {
+		plan.Successor, err = o.client.GetUser(o.SuccessorID)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't get successor %d: %w", o.SuccessorID, err)
+		}
+
+		if !plan.Successor.IsActive() {
+			return nil, ErrInactiveSuccessor
+		}
+	}
+
+	err = o.planChats(plan)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(user.Tags) != 0 {
+		plan.Steps = append(plan.Steps, &Step{Action: ACTION_REMOVE_TAGS, Tags: slices.Clone(user.Tags)})
+	}
+
+	if user.Status != nil {
+		plan.Steps = append(plan.Steps, &Step{Action: ACTION_CLEAR_STATUS})
+	}
+
+	if !user.IsSuspended {
+		plan.Steps = append(plan.Steps, &Step{Action: ACTION_SUSPEND})
+	}
+
+	if o.AuditChatID != 0 {
+		plan.Steps = append(plan.Steps, &Step{
+			Action: ACTION_AUDIT_NOTE, Chat: &pachca.Chat{ID: o.AuditChatID},
+		})
+	}
+
+	return plan, nil
+}
+
+// Execute executes all steps of plan. Execution isn't stopped on errors, but
+// remaining steps for chat are skipped if any of its steps has failed, so user
+// isn't excluded from chats where role transfer to successor has failed.
+func (o *Offboarder) Execute(plan *Plan) error {
+	switch {
+	case o == nil:
+		return ErrNilOffboarder
+	case plan == nil || plan.User == nil:
+		return ErrNilPlan
+	}
+
+	errs := errors.NewBundle()
+	failed := map[uint]bool{}
+
+	for _, step := range plan.Steps {
+		if step.IsDone {
+			continue
+		}
+
+		if step.Action != ACTION_AUDIT_NOTE && step.Chat != nil && failed[step.Chat.ID] {
+			step.Err = ErrTransferFailed
+			continue
+		}
+
+		step.Err = o.execute(plan, step)
+
+		if step.Err != nil {
+			errs.Add(fmt.Errorf("can't %s: %w", step.description(), step.Err))
+
+			if step.Chat != nil {
+				failed[step.Chat.ID] = true
+			}
+
+			continue
+		}
+
+		step.IsDone = true
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// String returns step description
+func (s *Step) String() string {
+	if s == nil {
+		return ""
+	}
+
+	desc := s.description()
+
+	switch {
+	case s.IsDone:
+		return "✅ " + desc
+	case s.Err != nil:
+		return "❌ " + desc + ": " + s.Err.Error()
+	}
+
+	return "• " + desc
+}
+
+// Summary returns plan summary suitable for audit note
+func (p *Plan) Summary() string {
+	if p == nil || p.User == nil {
+		return ""
+	}
+
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "Offboarding of %s", userName(p.User))
+
+	if p.Successor != nil {
+		fmt.Fprintf(&buf, " (successor: %s)", userName(p.Successor))
+	}
+
+	if p.IsDryRun {
+		buf.WriteString(" [dry run]")
+	}
+
+	buf.WriteString(":")
+
+	for _, step := range p.Steps {
+		if step.Action != ACTION_AUDIT_NOTE {
+			buf.WriteString("\n" + step.String())
+		}
+	}
+
+	if len(p.Steps) == 0 {
+		buf.WriteString("\nNothing to do")
+	}
+
+	return buf.String()
+}
+
+// Errors returns errors of failed steps
+func (p *Plan) Errors() []error {
+	if p == nil {
+		return nil
+	}
+
+	var result []error
+
+	for _, step := range p.Steps {
+		if step.Err != nil {
+			result = append(result, step.Err)
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// planChats adds chat steps to plan
+func (o *Offboarder) planChats(plan *Plan) error {
+	chats, err := o.client.GetChats()
+
+	if err != nil {
+		return fmt.Errorf("can't get chats: %w", err)
+	}
+
+	userID := plan.User.ID
+
+	for _, chat := range chats {
+		isMember := slices.Contains(chat.Members, userID)
+
+		if chat.IsPersonal || (!isMember && chat.OwnerID != userID) {
+			continue
+		}
+
+		if plan.Successor != nil {
+			isAdmin := chat.OwnerID == userID
+
+			if !isAdmin {
+				admins, err := o.client.GetChatUsers(chat.ID, pachca.CHAT_ROLE_ADMIN)
+
+				if err != nil {
+					return fmt.Errorf("can't get admins of chat %d: %w", chat.ID, err)
+				}
+
+				isAdmin = admins.Get(userID) != nil
+			}
+
+			if isAdmin {
+				if !slices.Contains(chat.Members, plan.Successor.ID) {
+					plan.Steps = append(plan.Steps, &Step{Action: ACTION_ADD_SUCCESSOR, Chat: chat})
+				}
+
+				plan.Steps = append(plan.Steps, &Step{Action: ACTION_GRANT_ADMIN, Chat: chat})
+			}
+		}
+
+		if isMember {
+			plan.Steps = append(plan.Steps, &Step{Action: ACTION_EXCLUDE, Chat: chat})
+		}
+	}
+
+	return nil
+}
+
+// execute executes single step
+func (o *Offboarder) execute(plan *Plan, step *Step) error {
+	userID := plan.User.ID
+
+	switch step.Action {
+	case ACTION_ADD_SUCCESSOR:
+		return o.client.AddChatUsers(step.Chat.ID, []uint{plan.Successor.ID}, true)
+	case ACTION_GRANT_ADMIN:
+		return o.client.SetChatUserRole(step.Chat.ID, plan.Successor.ID, pachca.CHAT_ROLE_ADMIN)
+	case ACTION_EXCLUDE:
+		return o.client.ExcludeChatUser(step.Chat.ID, userID)
+	case ACTION_REMOVE_TAGS:
+		return o.client.SetUserTags(userID, nil)
+	case ACTION_CLEAR_STATUS:
+		return o.client.DeleteStatus(userID)
+	case ACTION_SUSPEND:
+		return o.client.SuspendUser(userID)
+	case ACTION_AUDIT_NOTE:
+		_, err := o.client.SendMessageToChat(step.Chat.ID, plan.Summary())
+		return err
+	}
+
+	return fmt.Errorf("unknown action %d", step.Action)
+}
+
+// description returns step description without status
+func (s *Step) description() string {
+	switch s.Action {
+	case ACTION_ADD_SUCCESSOR:
+		return "add successor to chat " + chatName(s.Chat)
+	case ACTION_GRANT_ADMIN:
+		return "grant admin role in chat " + chatName(s.Chat) + " to successor"
+	case ACTION_EXCLUDE:
+		return "exclude user from chat " + chatName(s.Chat)
+	case ACTION_REMOVE_TAGS:
+		return "remove group tags (" + strings.Join(s.Tags, ", ") + ")"
+	case ACTION_CLEAR_STATUS:
+		return "clear status"
+	case ACTION_SUSPEND:
+		return "suspend user"
+	case ACTION_AUDIT_NOTE:
+		return "send audit note to chat " + chatName(s.Chat)
+	}
+
+	return fmt.Sprintf("execute unknown action %d", s.Action)
+}
+
+// chatName returns chat name for descriptions
+func chatName(chat *pachca.Chat) string {
+	if chat == nil {
+		return ""
+	}
+
+	if chat.Name == "" {
+		return fmt.Sprintf("%d", chat.ID)
+	}
+
+	return fmt.Sprintf("%q (%d)", chat.Name, chat.ID)
+}
+
+// userName returns user name for descriptions
+func userName(user *pachca.User) string {
+	name := user.FullName()
+
+	switch {
+	case name == "":
+		return fmt.Sprintf("%s (%d)", user.Email, user.ID)
+	case user.Email == "":
+		return fmt.Sprintf("%s (%d)", name, user.ID)
+	}
+
+	return fmt.Sprintf("%s <%s> (%d)", name, user.Email, user.ID)
+}
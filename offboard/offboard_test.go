@@ -0,0 +1,336 @@
+package offboard
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type OffboardSuite struct{}
+
+type fakeClient struct {
+	users    pachca.Users
+	chats    pachca.Chats
+	admins   map[uint][]uint
+	calls    []string
+	messages map[uint]string
+	fail     map[string]bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&OffboardSuite{})
+
+var _ Client = (*pachca.Client)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *OffboardSuite) TestPlan(c *C) {
+	client := newFakeClient()
+
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	o, err := New(client)
+	c.Assert(err, IsNil)
+
+	o.SuccessorID = 2
+	o.AuditChatID = 100
+	o.DryRun = true
+
+	plan, err := o.Offboard(1)
+	c.Assert(err, IsNil)
+	c.Assert(plan.User.ID, Equals, uint(1))
+	c.Assert(plan.Successor.ID, Equals, uint(2))
+	c.Assert(plan.IsDryRun, Equals, true)
+	c.Assert(client.calls, HasLen, 0)
+
+	c.Assert(plan.Summary(), Equals, `Offboarding of John Doe <john@domain.com> (1) (successor: Jane <jane@domain.com> (2)) [dry run]:
+• grant admin role in chat "General" (10) to successor
+• exclude user from chat "General" (10)
+• add successor to chat "Backend" (11)
+• grant admin role in chat "Backend" (11) to successor
+• exclude user from chat "Backend" (11)
+• exclude user from chat "Random" (12)
+• remove group tags (dev, ops)
+• clear status
+• suspend user`)
+
+	o.SuccessorID = 0
+	o.AuditChatID = 0
+
+	plan, err = o.Plan(1)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Successor, IsNil)
+	c.Assert(actions(plan), DeepEquals, []Action{
+		ACTION_EXCLUDE, ACTION_EXCLUDE, ACTION_EXCLUDE,
+		ACTION_REMOVE_TAGS, ACTION_CLEAR_STATUS, ACTION_SUSPEND,
+	})
+
+	plan, err = o.Plan(4)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Steps, HasLen, 0)
+	c.Assert(plan.Summary(), Equals, "Offboarding of bob@domain.com (4) [dry run]:\nNothing to do")
+
+	_, err = o.Plan(0)
+	c.Assert(err, Equals, ErrInvalidUserID)
+	_, err = o.Plan(99)
+	c.Assert(err, ErrorMatches, `can't get user 99: user not found`)
+
+	o.SuccessorID = 1
+	_, err = o.Plan(1)
+	c.Assert(err, Equals, ErrSameSuccessor)
+
+	o.SuccessorID = 4
+	_, err = o.Plan(1)
+	c.Assert(err, Equals, ErrInactiveSuccessor)
+
+	o.SuccessorID = 99
+	_, err = o.Plan(1)
+	c.Assert(err, ErrorMatches, `can't get successor 99: user not found`)
+
+	o.SuccessorID = 2
+	client.fail["GetChatUsers"] = true
+	_, err = o.Plan(1)
+	c.Assert(err, ErrorMatches, `can't get admins of chat 11: GetChatUsers error`)
+
+	client.fail["GetChats"] = true
+	_, err = o.Plan(1)
+	c.Assert(err, ErrorMatches, `can't get chats: GetChats error`)
+}
+
+func (s *OffboardSuite) TestExecute(c *C) {
+	client := newFakeClient()
+	o, err := New(client)
+	c.Assert(err, IsNil)
+
+	o.SuccessorID = 2
+	o.AuditChatID = 100
+
+	plan, err := o.Offboard(1)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Errors(), HasLen, 0)
+	c.Assert(client.calls, DeepEquals, []string{
+		"SetChatUserRole 10 2 admin",
+		"ExcludeChatUser 10 1",
+		"AddChatUsers 11 [2]",
+		"SetChatUserRole 11 2 admin",
+		"ExcludeChatUser 11 1",
+		"ExcludeChatUser 12 1",
+		"SetUserTags 1 []",
+		"DeleteStatus 1",
+		"SuspendUser 1",
+		"SendMessageToChat 100",
+	})
+	c.Assert(client.messages[100], Equals, `Offboarding of John Doe <john@domain.com> (1) (successor: Jane <jane@domain.com> (2)):
+✅ grant admin role in chat "General" (10) to successor
+✅ exclude user from chat "General" (10)
+✅ add successor to chat "Backend" (11)
+✅ grant admin role in chat "Backend" (11) to successor
+✅ exclude user from chat "Backend" (11)
+✅ exclude user from chat "Random" (12)
+✅ remove group tags (dev, ops)
+✅ clear status
+✅ suspend user`)
+
+	// Re-execution skips done steps
+	client.calls = nil
+	c.Assert(o.Execute(plan), IsNil)
+	c.Assert(client.calls, HasLen, 0)
+}
+
+func (s *OffboardSuite) TestExecuteErrors(c *C) {
+	client := newFakeClient()
+	o, err := New(client)
+	c.Assert(err, IsNil)
+
+	o.SuccessorID = 2
+	o.AuditChatID = 100
+	client.fail["AddChatUsers"] = true
+	client.fail["SuspendUser"] = true
+
+	plan, err := o.Offboard(1)
+	c.Assert(err, ErrorMatches, `(?s)can't add successor to chat "Backend" \(11\): AddChatUsers error.*can't suspend user: SuspendUser error`)
+	c.Assert(plan.Errors(), HasLen, 4)
+	c.Assert(slices.Contains(client.calls, "ExcludeChatUser 11 1"), Equals, false)
+	c.Assert(slices.Contains(client.calls, "ExcludeChatUser 12 1"), Equals, true)
+	c.Assert(plan.Steps[3].Err, Equals, ErrTransferFailed)
+	c.Assert(plan.Steps[4].Err, Equals, ErrTransferFailed)
+	c.Assert(client.messages[100], Matches, `(?s).*❌ exclude user from chat "Backend" \(11\): skipped because role transfer failed.*❌ suspend user: SuspendUser error`)
+
+	_, err = o.Offboard(99)
+	c.Assert(err, NotNil)
+
+	c.Assert(o.Execute(nil), Equals, ErrNilPlan)
+	c.Assert(o.Execute(&Plan{}), Equals, ErrNilPlan)
+
+	err = o.Execute(&Plan{User: &pachca.User{ID: 1}, Steps: []*Step{{Action: 99}}})
+	c.Assert(err, ErrorMatches, `can't execute unknown action 99: unknown action 99`)
+
+	o = nil
+
+	_, err = o.Offboard(1)
+	c.Assert(err, Equals, ErrNilOffboarder)
+	c.Assert(o.Execute(&Plan{}), Equals, ErrNilOffboarder)
+}
+
+func (s *OffboardSuite) TestHelpers(c *C) {
+	var step *Step
+	var plan *Plan
+
+	c.Assert(step.String(), Equals, "")
+	c.Assert(plan.Summary(), Equals, "")
+	c.Assert(plan.Errors(), IsNil)
+
+	c.Assert(chatName(nil), Equals, "")
+	c.Assert(chatName(&pachca.Chat{ID: 1}), Equals, "1")
+	c.Assert(userName(&pachca.User{ID: 1, FirstName: "John"}), Equals, "John (1)")
+
+	step = &Step{Action: ACTION_AUDIT_NOTE, Chat: &pachca.Chat{ID: 1}}
+	c.Assert(step.String(), Equals, "• send audit note to chat 1")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		users: pachca.Users{
+			{
+				ID: 1, FirstName: "John", LastName: "Doe", Email: "john@domain.com",
+				InviteStatus: pachca.INVITE_CONFIRMED, Tags: []string{"dev", "ops"},
+				Status: &pachca.Status{Emoji: "🌴", Title: "Vacation"},
+			},
+			{
+				ID: 2, FirstName: "Jane", Email: "jane@domain.com",
+				InviteStatus: pachca.INVITE_CONFIRMED,
+			},
+			{
+				ID: 4, Email: "bob@domain.com",
+				InviteStatus: pachca.INVITE_CONFIRMED, IsSuspended: true,
+			},
+		},
+		chats: pachca.Chats{
+			{ID: 10, Name: "General", OwnerID: 1, Members: []uint{1, 2, 3}},
+			{ID: 11, Name: "Backend", OwnerID: 3, Members: []uint{1, 3}},
+			{ID: 12, Name: "Random", OwnerID: 3, Members: []uint{1, 3}},
+			{ID: 13, Name: "Ops", OwnerID: 3, Members: []uint{2, 3}},
+			{ID: 14, OwnerID: 3, Members: []uint{1, 3}, IsPersonal: true},
+		},
+		admins:   map[uint][]uint{11: {1, 3}, 12: {3}},
+		messages: map[uint]string{},
+		fail:     map[string]bool{},
+	}
+}
+
+func (c *fakeClient) GetUser(userID uint) (*pachca.User, error) {
+	user := c.users.Get(userID)
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func (c *fakeClient) GetChats(filter ...pachca.ChatFilter) (pachca.Chats, error) {
+	if c.fail["GetChats"] {
+		return nil, errors.New("GetChats error")
+	}
+
+	return c.chats, nil
+}
+
+func (c *fakeClient) GetChatUsers(chatID uint, memberRole pachca.ChatRole) (pachca.Users, error) {
+	if c.fail["GetChatUsers"] {
+		return nil, errors.New("GetChatUsers error")
+	}
+
+	var result pachca.Users
+
+	for _, id := range c.admins[chatID] {
+		result = append(result, &pachca.User{ID: id})
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) AddChatUsers(chatID uint, membersIDs []uint, silent bool) error {
+	return c.call("AddChatUsers", chatID, membersIDs)
+}
+
+func (c *fakeClient) SetChatUserRole(chatID, userID uint, role pachca.ChatRole) error {
+	return c.call("SetChatUserRole", chatID, userID, role)
+}
+
+func (c *fakeClient) ExcludeChatUser(chatID, userID uint) error {
+	return c.call("ExcludeChatUser", chatID, userID)
+}
+
+func (c *fakeClient) SetUserTags(userID uint, tags []string) error {
+	return c.call("SetUserTags", userID, tags)
+}
+
+func (c *fakeClient) DeleteStatus(userID uint) error {
+	return c.call("DeleteStatus", userID)
+}
+
+func (c *fakeClient) SuspendUser(userID uint) error {
+	return c.call("SuspendUser", userID)
+}
+
+func (c *fakeClient) SendMessageToChat(chatID uint, text string) (*pachca.Message, error) {
+	err := c.call("SendMessageToChat", chatID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.messages[chatID] = text
+
+	return &pachca.Message{ID: 1}, nil
+}
+
+func (c *fakeClient) call(method string, args ...any) error {
+	if c.fail[method] {
+		return errors.New(method + " error")
+	}
+
+	call := method
+
+	for _, arg := range args {
+		call += " " + fmt.Sprint(arg)
+	}
+
+	c.calls = append(c.calls, call)
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func actions(plan *Plan) []Action {
+	var result []Action
+
+	for _, step := range plan.Steps {
+		result = append(result, step.Action)
+	}
+
+	return result
+}
@@ -0,0 +1,340 @@
+package orgchart
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_MANAGER_PROPERTY is default name of custom property with manager
+const DEFAULT_MANAGER_PROPERTY = "Manager"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by org chart builder
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Builder builds org chart from users data
+type Builder struct {
+	// ManagerProperty is name of custom property with reference to manager. The
+	// reference can be user ID, email, nickname or link to user profile.
+	ManagerProperty string
+
+	// WithInactive enables suspended and invited users
+	WithInactive bool
+
+	// WithBots enables bots
+	WithBots bool
+
+	client Client
+}
+
+// Chart is org chart
+type Chart struct {
+	// Roots is a list of top-level nodes (users without managers)
+	Roots []*Node
+
+	// Orphans is a list of nodes with manager reference which can't be
+	// resolved. Orphans are also added to roots.
+	Orphans []*Node
+
+	// Cycles is a list of management cycles. Every cycle is broken at the node
+	// with the lowest user ID, which is added to roots.
+	Cycles [][]*Node
+
+	nodes map[uint]*Node
+}
+
+// Node is org chart node
+type Node struct {
+	User    *pachca.User
+	Manager *Node
+	Reports []*Node
+
+	// ManagerRef is raw manager reference from custom property
+	ManagerRef string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient  = errors.New("client is nil")
+	ErrNilBuilder = errors.New("builder is nil")
+	ErrNilChart   = errors.New("chart is nil")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new org chart builder
+func New(client Client) (*Builder, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Builder{client: client, ManagerProperty: DEFAULT_MANAGER_PROPERTY}, nil
+}
+
+// Build fetches workspace users and builds org chart
+func (b *Builder) Build() (*Chart, error) {
+	if b == nil {
+		return nil, ErrNilBuilder
+	}
+
+	users, err := b.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	return b.BuildFrom(users), nil
+}
+
+// BuildFrom builds org chart from given users
+func (b *Builder) BuildFrom(users pachca.Users) *Chart {
+	chart := &Chart{nodes: map[uint]*Node{}}
+
+	if b == nil {
+		return chart
+	}
+
+	var nodes []*Node
+
+	index := map[string]*Node{}
+
+	for _, user := range users {
+		if !b.isIncluded(user) || chart.nodes[user.ID] != nil {
+			continue
+		}
+
+		node := &Node{User: user}
+		nodes = append(nodes, node)
+		chart.nodes[user.ID] = node
+
+		if user.Email != "" {
+			index[strings.ToLower(user.Email)] = node
+		}
+
+		if user.Nickname != "" {
+			index[strings.ToLower(user.Nickname)] = node
+		}
+	}
+
+	for _, node := range nodes {
+		node.ManagerRef = strings.TrimSpace(
+			node.User.Properties.Find(b.getManagerProperty()).String(),
+		)
+
+		if node.ManagerRef == "" {
+			continue
+		}
+
+		node.Manager = chart.resolve(index, node.ManagerRef)
+
+		if node.Manager == nil {
+			chart.Orphans = append(chart.Orphans, node)
+		}
+	}
+
+	chart.breakCycles(nodes)
+
+	for _, node := range nodes {
+		if node.Manager == nil {
+			chart.Roots = append(chart.Roots, node)
+		} else {
+			node.Manager.Reports = append(node.Manager.Reports, node)
+		}
+	}
+
+	sortNodes(chart.Roots)
+	sortNodes(chart.Orphans)
+
+	for _, node := range nodes {
+		sortNodes(node.Reports)
+	}
+
+	return chart
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns node of user with given ID
+func (c *Chart) Get(userID uint) *Node {
+	if c == nil {
+		return nil
+	}
+
+	return c.nodes[userID]
+}
+
+// Size returns number of nodes in chart
+func (c *Chart) Size() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(c.nodes)
+}
+
+// HasProblems returns true if chart has orphans or cycles
+func (c *Chart) HasProblems() bool {
+	return c != nil && (len(c.Orphans) > 0 || len(c.Cycles) > 0)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ID returns user ID
+func (n *Node) ID() uint {
+	if n == nil || n.User == nil {
+		return 0
+	}
+
+	return n.User.ID
+}
+
+// Name returns user full name or nickname
+func (n *Node) Name() string {
+	switch {
+	case n == nil || n.User == nil:
+		return ""
+	case n.User.FullName() != "":
+		return n.User.FullName()
+	}
+
+	return n.User.Nickname
+}
+
+// Chain returns chain of managers starting from direct manager
+func (n *Node) Chain() []*Node {
+	var result []*Node
+
+	if n == nil {
+		return nil
+	}
+
+	for m := n.Manager; m != nil && m != n; m = m.Manager {
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isIncluded returns true if user must be added to chart
+func (b *Builder) isIncluded(user *pachca.User) bool {
+	switch {
+	case user == nil:
+		return false
+	case user.IsBot:
+		return b.WithBots
+	case !user.IsActive():
+		return b.WithInactive
+	}
+
+	return true
+}
+
+// getManagerProperty returns name of manager property
+func (b *Builder) getManagerProperty() string {
+	if b.ManagerProperty == "" {
+		return DEFAULT_MANAGER_PROPERTY
+	}
+
+	return b.ManagerProperty
+}
+
+// resolve resolves manager reference
+func (c *Chart) resolve(index map[string]*Node, ref string) *Node {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		u, err := url.Parse(ref)
+
+		if err != nil {
+			return nil
+		}
+
+		ref = path.Base(u.Path)
+	}
+
+	id, err := strconv.ParseUint(ref, 10, 64)
+
+	if err == nil {
+		return c.nodes[uint(id)]
+	}
+
+	return index[strings.ToLower(strings.TrimPrefix(ref, "@"))]
+}
+
+// breakCycles finds management cycles and breaks them
+func (c *Chart) breakCycles(nodes []*Node) {
+	const (
+		unvisited uint8 = iota
+		inProgress
+		done
+	)
+
+	state := make(map[*Node]uint8, len(nodes))
+
+	for _, node := range nodes {
+		var chain []*Node
+
+		n := node
+
+		for n != nil && state[n] == unvisited {
+			state[n] = inProgress
+			chain = append(chain, n)
+			n = n.Manager
+		}
+
+		if n != nil && state[n] == inProgress {
+			cycle := chain[slices.Index(chain, n):]
+			start := 0
+
+			for i, cn := range cycle {
+				if cn.ID() < cycle[start].ID() {
+					start = i
+				}
+			}
+
+			cycle = append(slices.Clone(cycle[start:]), cycle[:start]...)
+			cycle[0].Manager = nil
+
+			c.Cycles = append(c.Cycles, cycle)
+		}
+
+		for _, cn := range chain {
+			state[cn] = done
+		}
+	}
+}
+
+// sortNodes sorts nodes by department, name and ID
+func sortNodes(nodes []*Node) {
+	slices.SortStableFunc(nodes, func(n1, n2 *Node) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(n1.User.Department), strings.ToLower(n2.User.Department)),
+			cmp.Compare(strings.ToLower(n1.Name()), strings.ToLower(n2.Name())),
+			cmp.Compare(n1.ID(), n2.ID()),
+		)
+	})
+}
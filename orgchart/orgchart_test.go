@@ -0,0 +1,306 @@
+package orgchart
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type fakeClient struct {
+	users pachca.Users
+	fail  bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type OrgChartSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&OrgChartSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *OrgChartSuite) TestBuild(c *C) {
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	client := &fakeClient{users: testUsers()}
+	b, err := New(client)
+	c.Assert(err, IsNil)
+
+	chart, err := b.Build()
+	c.Assert(err, IsNil)
+	c.Assert(chart.Size(), Equals, 6)
+	c.Assert(chart.HasProblems(), Equals, false)
+
+	c.Assert(names(chart.Roots), DeepEquals, []string{"Alice Smith"})
+
+	alice := chart.Get(1)
+	c.Assert(names(alice.Reports), DeepEquals, []string{"Bob Brown", "Carol White"})
+	c.Assert(names(chart.Get(2).Reports), DeepEquals, []string{"dave", "Eve Black"})
+	c.Assert(names(chart.Get(3).Reports), DeepEquals, []string{"Frank Green"})
+	c.Assert(names(chart.Get(6).Chain()), DeepEquals, []string{"Carol White", "Alice Smith"})
+	c.Assert(chart.Get(4).ManagerRef, Equals, "@bob")
+	c.Assert(chart.Get(7), IsNil)
+
+	b.WithInactive, b.WithBots = true, true
+	chart, err = b.Build()
+	c.Assert(err, IsNil)
+	c.Assert(chart.Size(), Equals, 8)
+
+	client.fail = true
+	_, err = b.Build()
+	c.Assert(err, ErrorMatches, `can't get users: API error`)
+
+	var nb *Builder
+	_, err = nb.Build()
+	c.Assert(err, Equals, ErrNilBuilder)
+	c.Assert(nb.BuildFrom(testUsers()).Size(), Equals, 0)
+
+	var nc *Chart
+	c.Assert(nc.Get(1), IsNil)
+	c.Assert(nc.Size(), Equals, 0)
+	c.Assert(nc.HasProblems(), Equals, false)
+
+	var nn *Node
+	c.Assert(nn.ID(), Equals, uint(0))
+	c.Assert(nn.Name(), Equals, "")
+	c.Assert(nn.Chain(), IsNil)
+}
+
+func (s *OrgChartSuite) TestProblems(c *C) {
+	b := &Builder{ManagerProperty: "Boss"}
+
+	users := pachca.Users{
+		user(1, "a", "", "", "3"),
+		user(2, "b", "", "", "1"),
+		user(3, "c", "", "", "2"),
+		user(4, "d", "", "", "4"),
+		user(5, "e", "", "", "unknown@domain.com"),
+		user(6, "f", "", "", "5"),
+		user(7, "g", "", "", "https://app.pachca.com/users/100"),
+		user(7, "g", "", "", ""),
+	}
+
+	for _, u := range users {
+		u.Properties[0].Name = "Boss"
+	}
+
+	chart := b.BuildFrom(users)
+
+	c.Assert(chart.HasProblems(), Equals, true)
+	c.Assert(chart.Size(), Equals, 7)
+	c.Assert(ids(chart.Orphans), DeepEquals, []uint{5, 7})
+	c.Assert(chart.Cycles, HasLen, 2)
+	c.Assert(ids(chart.Cycles[0]), DeepEquals, []uint{1, 3, 2})
+	c.Assert(ids(chart.Cycles[1]), DeepEquals, []uint{4})
+	c.Assert(ids(chart.Roots), DeepEquals, []uint{1, 4, 5, 7})
+	c.Assert(ids(chart.Get(1).Reports), DeepEquals, []uint{2})
+	c.Assert(ids(chart.Get(5).Reports), DeepEquals, []uint{6})
+
+	var buf bytes.Buffer
+
+	c.Assert(chart.WriteJSON(&buf), IsNil)
+	c.Assert(buf.String(), Matches, `(?s).*"orphans": \[\s*5,\s*7\s*\],\s*"cycles": \[\s*\[\s*1,\s*3,\s*2\s*\],\s*\[\s*4\s*\]\s*\].*`)
+}
+
+func (s *OrgChartSuite) TestRender(c *C) {
+	b := &Builder{}
+	chart := b.BuildFrom(testUsers())
+
+	var buf bytes.Buffer
+
+	c.Assert(chart.WriteJSON(&buf), IsNil)
+	c.Assert(buf.String(), Equals, `{
+  "roots": [
+    {
+      "id": 1,
+      "name": "Alice Smith",
+      "email": "alice@domain.com",
+      "title": "CEO",
+      "reports": [
+        {
+          "id": 2,
+          "name": "Bob Brown",
+          "email": "bob@domain.com",
+          "title": "CTO",
+          "department": "Engineering",
+          "reports": [
+            {
+              "id": 4,
+              "name": "dave",
+              "email": "dave@domain.com",
+              "department": "Engineering"
+            },
+            {
+              "id": 5,
+              "name": "Eve Black",
+              "email": "eve@domain.com",
+              "title": "Developer",
+              "department": "engineering"
+            }
+          ]
+        },
+        {
+          "id": 3,
+          "name": "Carol White",
+          "email": "carol@domain.com",
+          "title": "Head of \"Sales\"",
+          "department": "Sales",
+          "reports": [
+            {
+              "id": 6,
+              "name": "Frank Green",
+              "email": "frank@domain.com",
+              "title": "Manager",
+              "department": "Sales"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`)
+
+	buf.Reset()
+
+	c.Assert(chart.WriteDOT(&buf), IsNil)
+	c.Assert(buf.String(), Equals, `digraph orgchart {
+  rankdir=TB;
+  node [shape=box];
+  "u1" [label="Alice Smith\nCEO"];
+  subgraph cluster_1 {
+    label="Engineering";
+    "u2" [label="Bob Brown\nCTO"];
+    "u4" [label="dave"];
+    "u5" [label="Eve Black\nDeveloper"];
+  }
+  subgraph cluster_2 {
+    label="Sales";
+    "u3" [label="Carol White\nHead of \"Sales\""];
+    "u6" [label="Frank Green\nManager"];
+  }
+  "u1" -> "u2";
+  "u1" -> "u3";
+  "u2" -> "u4";
+  "u2" -> "u5";
+  "u3" -> "u6";
+}
+`)
+
+	buf.Reset()
+
+	c.Assert(chart.WriteMermaid(&buf), IsNil)
+	c.Assert(buf.String(), Equals, `flowchart TD
+  u1["Alice Smith<br/>CEO"]
+  subgraph d1["Engineering"]
+    u2["Bob Brown<br/>CTO"]
+    u4["dave"]
+    u5["Eve Black<br/>Developer"]
+  end
+  subgraph d2["Sales"]
+    u3["Carol White<br/>Head of #quot;Sales#quot;"]
+    u6["Frank Green<br/>Manager"]
+  end
+  u1 --> u2
+  u1 --> u3
+  u2 --> u4
+  u2 --> u5
+  u3 --> u6
+`)
+
+	var nc *Chart
+
+	c.Assert(nc.WriteJSON(&buf), Equals, ErrNilChart)
+	c.Assert(nc.WriteDOT(&buf), Equals, ErrNilChart)
+	c.Assert(nc.WriteMermaid(&buf), Equals, ErrNilChart)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.fail {
+		return nil, errors.New("API error")
+	}
+
+	return c.users, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func testUsers() pachca.Users {
+	users := pachca.Users{
+		user(1, "alice", "Alice Smith", "CEO", ""),
+		user(2, "bob", "Bob Brown", "CTO", "alice@domain.com"),
+		user(3, "carol", "Carol White", `Head of "Sales"`, "1"),
+		user(4, "dave", "", "", "@bob"),
+		user(5, "eve", "Eve Black", "Developer", "https://app.pachca.com/users/2"),
+		user(6, "frank", "Frank Green", "Manager", "carol"),
+		user(7, "bot", "Bot", "", ""),
+		user(8, "gina", "Gina Grey", "", "1"),
+	}
+
+	users[1].Department = "Engineering"
+	users[2].Department = "Sales"
+	users[3].Department = "Engineering"
+	users[4].Department = "engineering"
+	users[5].Department = "Sales"
+	users[6].IsBot = true
+	users[7].IsSuspended = true
+
+	return users
+}
+
+func user(id uint, nick, name, title, manager string) *pachca.User {
+	first, last, _ := bytes.Cut([]byte(name), []byte(" "))
+
+	return &pachca.User{
+		ID:           id,
+		Nickname:     nick,
+		Email:        nick + "@domain.com",
+		FirstName:    string(first),
+		LastName:     string(last),
+		Title:        title,
+		InviteStatus: pachca.INVITE_CONFIRMED,
+		Properties: pachca.Properties{
+			{ID: 1, Name: "Manager", Type: pachca.PROP_TYPE_TEXT, Value: manager},
+		},
+	}
+}
+
+func names(nodes []*Node) []string {
+	var result []string
+
+	for _, n := range nodes {
+		result = append(result, n.Name())
+	}
+
+	return result
+}
+
+func ids(nodes []*Node) []uint {
+	var result []uint
+
+	for _, n := range nodes {
+		result = append(result, n.ID())
+	}
+
+	return result
+}
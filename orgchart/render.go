@@ -0,0 +1,238 @@
+package orgchart
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// jsonChart is JSON representation of org chart
+type jsonChart struct {
+	Roots   []*jsonNode `json:"roots"`
+	Orphans []uint      `json:"orphans,omitempty"`
+	Cycles  [][]uint    `json:"cycles,omitempty"`
+}
+
+// jsonNode is JSON representation of org chart node
+type jsonNode struct {
+	ID         uint        `json:"id"`
+	Name       string      `json:"name"`
+	Email      string      `json:"email,omitempty"`
+	Title      string      `json:"title,omitempty"`
+	Department string      `json:"department,omitempty"`
+	Reports    []*jsonNode `json:"reports,omitempty"`
+}
+
+// department is a group of nodes from the same department
+type department struct {
+	Name  string
+	Nodes []*Node
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// WriteJSON writes org chart as JSON tree
+func (c *Chart) WriteJSON(w io.Writer) error {
+	if c == nil {
+		return ErrNilChart
+	}
+
+	data := &jsonChart{Roots: make([]*jsonNode, 0, len(c.Roots))}
+
+	for _, node := range c.Roots {
+		data.Roots = append(data.Roots, toJSONNode(node))
+	}
+
+	for _, node := range c.Orphans {
+		data.Orphans = append(data.Orphans, node.ID())
+	}
+
+	for _, cycle := range c.Cycles {
+		var ids []uint
+
+		for _, node := range cycle {
+			ids = append(ids, node.ID())
+		}
+
+		data.Cycles = append(data.Cycles, ids)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(data)
+}
+
+// WriteDOT writes org chart in Graphviz DOT format. Users from the same
+// department are grouped into clusters.
+func (c *Chart) WriteDOT(w io.Writer) error {
+	if c == nil {
+		return ErrNilChart
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("digraph orgchart {\n")
+	sb.WriteString("  rankdir=TB;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for i, dep := range c.departments() {
+		indent := "  "
+
+		if dep.Name != "" {
+			fmt.Fprintf(&sb, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&sb, "    label=%s;\n", quoteDOT(dep.Name))
+			indent = "    "
+		}
+
+		for _, node := range dep.Nodes {
+			fmt.Fprintf(&sb, "%s\"u%d\" [label=%s];\n", indent, node.ID(), quoteDOT(nodeLabel(node, "\n")))
+		}
+
+		if dep.Name != "" {
+			sb.WriteString("  }\n")
+		}
+	}
+
+	for _, node := range c.ordered() {
+		for _, report := range node.Reports {
+			fmt.Fprintf(&sb, "  \"u%d\" -> \"u%d\";\n", node.ID(), report.ID())
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// WriteMermaid writes org chart as Mermaid flowchart. Users from the same
+// department are grouped into subgraphs.
+func (c *Chart) WriteMermaid(w io.Writer) error {
+	if c == nil {
+		return ErrNilChart
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("flowchart TD\n")
+
+	for i, dep := range c.departments() {
+		indent := "  "
+
+		if dep.Name != "" {
+			fmt.Fprintf(&sb, "  subgraph d%d[\"%s\"]\n", i, escapeMermaid(dep.Name))
+			indent = "    "
+		}
+
+		for _, node := range dep.Nodes {
+			fmt.Fprintf(&sb, "%su%d[\"%s\"]\n", indent, node.ID(), escapeMermaid(nodeLabel(node, "<br/>")))
+		}
+
+		if dep.Name != "" {
+			sb.WriteString("  end\n")
+		}
+	}
+
+	for _, node := range c.ordered() {
+		for _, report := range node.Reports {
+			fmt.Fprintf(&sb, "  u%d --> u%d\n", node.ID(), report.ID())
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ordered returns all nodes in depth-first order
+func (c *Chart) ordered() []*Node {
+	var result []*Node
+	var walk func(nodes []*Node)
+
+	walk = func(nodes []*Node) {
+		for _, node := range nodes {
+			result = append(result, node)
+			walk(node.Reports)
+		}
+	}
+
+	walk(c.Roots)
+
+	return result
+}
+
+// departments groups nodes by department keeping depth-first order
+func (c *Chart) departments() []*department {
+	var result []*department
+
+	index := map[string]*department{}
+
+	for _, node := range c.ordered() {
+		name := strings.TrimSpace(node.User.Department)
+		key := strings.ToLower(name)
+		dep := index[key]
+
+		if dep == nil {
+			dep = &department{Name: name}
+			index[key] = dep
+			result = append(result, dep)
+		}
+
+		dep.Nodes = append(dep.Nodes, node)
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// toJSONNode converts node to JSON representation
+func toJSONNode(node *Node) *jsonNode {
+	result := &jsonNode{
+		ID:         node.ID(),
+		Name:       node.Name(),
+		Email:      node.User.Email,
+		Title:      node.User.Title,
+		Department: node.User.Department,
+	}
+
+	for _, report := range node.Reports {
+		result.Reports = append(result.Reports, toJSONNode(report))
+	}
+
+	return result
+}
+
+// nodeLabel returns node label with name and title
+func nodeLabel(node *Node, sep string) string {
+	if node.User.Title == "" {
+		return node.Name()
+	}
+
+	return node.Name() + sep + node.User.Title
+}
+
+// quoteDOT quotes string for DOT
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// escapeMermaid escapes string for Mermaid label
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;").Replace(s)
+}
@@ -263,6 +263,17 @@ type Reaction struct {
 // Reactions is a slice of reactions
 type Reactions []*Reaction
 
+// ReactionGroup contains info about all reactions with the same emoji
+type ReactionGroup struct {
+	Emoji string
+	Name  string
+	Count int
+	Users []uint
+}
+
+// ReactionGroups is a slice of reaction groups
+type ReactionGroups []*ReactionGroup
+
 // Thread contains info about thread
 type Thread struct {
 	ID            uint `json:"id"`
@@ -473,6 +484,10 @@ type UserRequest struct {
 	Tags            []string         `json:"list_tags,omitempty"`
 	IsSuspended     bool             `json:"suspended,omitempty"`
 	SkipEmailNotify bool             `json:"skip_email_notify,omitempty"`
+
+	// ClearFields is list of JSON names of string fields (e.g. "nickname" or
+	// "title") which must be sent with empty value to clear them
+	ClearFields []string `json:"-"`
 }
 
 // PropertyRequest is a struct with property info
@@ -608,6 +623,32 @@ func (d Date) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Time.UTC().Format("2006-01-02T15:04:05.999Z"))
 }
 
+// MarshalJSON converts user request into JSON format
+func (r *UserRequest) MarshalJSON() ([]byte, error) {
+	type userRequest UserRequest
+
+	data, err := json.Marshal((*userRequest)(r))
+
+	if err != nil || len(r.ClearFields) == 0 {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &fields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range r.ClearFields {
+		if _, ok := fields[field]; !ok {
+			fields[field] = json.RawMessage(`""`)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // tokenValidationRegex is regex pattern for token validation
@@ -630,6 +671,7 @@ var (
 	ErrNilStatus           = errors.New("status is nil")
 	ErrNilBotConfiguration = errors.New("bot webhook configuration is nil")
 	ErrNilYieldFunc        = errors.New("nil yield function provided")
+	ErrNilReader           = errors.New("reader is nil")
 
 	// Empty value guards
 	ErrEmptyToken     = errors.New("token is empty")
@@ -640,6 +682,7 @@ var (
 	ErrEmptyUsersIDS  = errors.New("users IDs list is empty")
 	ErrEmptyTagsIDS   = errors.New("tags IDs list is empty")
 	ErrEmptyFilePath  = errors.New("file path is empty")
+	ErrEmptyFileName  = errors.New("file name is empty")
 	ErrEmptyPreviews  = errors.New("link previews map is empty")
 	ErrEmptyTriggerID = errors.New("view trigger ID is empty")
 
@@ -662,7 +705,8 @@ var (
 	ErrEmptyWebhookURL = errors.New("webhook URL is empty")
 	ErrEmptyResponse   = errors.New("empty response from API endpoint")
 
-	// Rate-limit
+	// API errors
+	ErrNotFound    = errors.New("resource not found")
 	ErrRateLimited = errors.New("rate limit exceeded")
 )
 
@@ -711,10 +755,10 @@ func NewPropertyRequest(id uint, value any) *PropertyRequest {
 		v = fmt.Sprintf("%d", value)
 
 	case float32:
-		v = fmt.Sprintf("%d", int64(t))
+		v = strconv.FormatFloat(float64(t), 'f', -1, 32)
 
 	case float64:
-		v = fmt.Sprintf("%d", int64(t))
+		v = strconv.FormatFloat(t, 'f', -1, 64)
 
 	default:
 		v = fmt.Sprintf("%v", value)
@@ -1153,6 +1197,79 @@ func (c *Client) DeleteUser(userID uint) error {
 	return nil
 }
 
+// SuspendUser suspends user with given ID
+//
+// https://dev.pachca.com/users/update
+func (c *Client) SuspendUser(userID uint) error {
+	switch {
+	case c == nil || c.engine == nil:
+		return ErrNilClient
+	case userID == 0:
+		return ErrInvalidUserID
+	}
+
+	err := c.setUserSuspended(userID, true)
+
+	if err != nil {
+		return fmt.Errorf("can't suspend user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// UnsuspendUser unsuspends user with given ID
+//
+// https://dev.pachca.com/users/update
+func (c *Client) UnsuspendUser(userID uint) error {
+	switch {
+	case c == nil || c.engine == nil:
+		return ErrNilClient
+	case userID == 0:
+		return ErrInvalidUserID
+	}
+
+	err := c.setUserSuspended(userID, false)
+
+	if err != nil {
+		return fmt.Errorf("can't unsuspend user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// SetUserTags replaces all group tags of user with given ones. Unlike EditUser,
+// it can be used to remove all tags from user.
+//
+// https://dev.pachca.com/users/update
+func (c *Client) SetUserTags(userID uint, tags []string) error {
+	switch {
+	case c == nil || c.engine == nil:
+		return ErrNilClient
+	case userID == 0:
+		return ErrInvalidUserID
+	}
+
+	payload := &struct {
+		User struct {
+			Tags []string `json:"list_tags"`
+		} `json:"user"`
+	}{}
+
+	payload.User.Tags = tags
+
+	if tags == nil {
+		payload.User.Tags = []string{}
+	}
+
+	err := c.sendRequest(req.PUT, getURL("/users/%d", userID), nil, payload, nil)
+
+	if err != nil {
+		return fmt.Errorf("can't set tags for user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
 // BOTS ///////////////////////////////////////////////////////////////////////////// //
 
 // AddBot creates a new bot
@@ -1391,6 +1508,39 @@ func (c *Client) UpdateAvatar(file string) (string, error) {
 	return "", nil
 }
 
+// UpdateAvatarFromReader updates current user avatar to image from given
+// reader. Name is used as the name of uploaded file.
+//
+// https://dev.pachca.com/api/profile/update-avatar
+func (c *Client) UpdateAvatarFromReader(name string, r io.Reader) (string, error) {
+	switch {
+	case c == nil || c.engine == nil:
+		return "", ErrNilClient
+	case name == "":
+		return "", ErrEmptyFileName
+	case r == nil:
+		return "", ErrNilReader
+	}
+
+	resp := &struct {
+		Data *struct {
+			ImageURL string `json:"image_url"`
+		} `json:"data"`
+	}{}
+
+	err := c.uploadReader(req.PUT, getURL("/profile/avatar"), name, r, resp)
+
+	if err != nil {
+		return "", fmt.Errorf("can't upload user avatar: %w", err)
+	}
+
+	if resp != nil && resp.Data != nil {
+		return resp.Data.ImageURL, nil
+	}
+
+	return "", nil
+}
+
 // DeleteAvatar deletes current user avatar
 //
 // https://dev.pachca.com/api/profile/delete-avatar
@@ -1441,6 +1591,41 @@ func (c *Client) UpdateUserAvatar(userID uint, file string) (string, error) {
 	return "", nil
 }
 
+// UpdateUserAvatarFromReader updates specified user avatar to image from given
+// reader. Name is used as the name of uploaded file.
+//
+// https://dev.pachca.com/api/users/update-avatar
+func (c *Client) UpdateUserAvatarFromReader(userID uint, name string, r io.Reader) (string, error) {
+	switch {
+	case c == nil || c.engine == nil:
+		return "", ErrNilClient
+	case userID == 0:
+		return "", ErrInvalidUserID
+	case name == "":
+		return "", ErrEmptyFileName
+	case r == nil:
+		return "", ErrNilReader
+	}
+
+	resp := &struct {
+		Data *struct {
+			ImageURL string `json:"image_url"`
+		} `json:"data"`
+	}{}
+
+	err := c.uploadReader(req.PUT, getURL("/users/%d/avatar", userID), name, r, resp)
+
+	if err != nil {
+		return "", fmt.Errorf("can't upload avatar for user %d: %w", userID, err)
+	}
+
+	if resp != nil && resp.Data != nil {
+		return resp.Data.ImageURL, nil
+	}
+
+	return "", nil
+}
+
 // DeleteUserAvatar deletes specified user avatar
 //
 // https://dev.pachca.com/api/users/remove-avatar
@@ -3421,6 +3606,17 @@ func (u Users) WithTag(tag string) Users {
 	})
 }
 
+// Filter returns users for which given function returns true
+func (u Users) Filter(fn func(u *User) bool) Users {
+	if fn == nil {
+		return u
+	}
+
+	return sliceutil.Filter(u, func(uu *User, _ int) bool {
+		return fn(uu)
+	})
+}
+
 // Get returns chat with given ID
 func (c Chats) Get(id uint) *Chat {
 	for _, cc := range c {
@@ -3473,6 +3669,17 @@ func (c Chats) Communal() Chats {
 	})
 }
 
+// Filter returns chats for which given function returns true
+func (c Chats) Filter(fn func(c *Chat) bool) Chats {
+	if fn == nil {
+		return c
+	}
+
+	return sliceutil.Filter(c, func(cc *Chat, _ int) bool {
+		return fn(cc)
+	})
+}
+
 // Get returns tag with given ID
 func (t Tags) Get(id uint) *Tag {
 	for _, tt := range t {
@@ -3527,6 +3734,105 @@ func (t Tags) InChat(chat *Chat) Tags {
 	return result
 }
 
+// Filter returns tags for which given function returns true
+func (t Tags) Filter(fn func(t *Tag) bool) Tags {
+	if fn == nil {
+		return t
+	}
+
+	return sliceutil.Filter(t, func(tt *Tag, _ int) bool {
+		return fn(tt)
+	})
+}
+
+// Group groups reactions by emoji. Groups are sorted by number of reactions
+// (the most popular first).
+func (r Reactions) Group() ReactionGroups {
+	var result ReactionGroups
+
+	for _, rr := range r {
+		if rr == nil {
+			continue
+		}
+
+		group := result.Get(rr.Emoji)
+
+		if group == nil {
+			group = &ReactionGroup{Emoji: rr.Emoji, Name: rr.Name}
+			result = append(result, group)
+		}
+
+		group.Count++
+
+		if !slices.Contains(group.Users, rr.UserID) {
+			group.Users = append(group.Users, rr.UserID)
+		}
+	}
+
+	slices.SortStableFunc(result, func(g1, g2 *ReactionGroup) int {
+		return g2.Count - g1.Count
+	})
+
+	return result
+}
+
+// WithEmoji returns reactions with given emoji or emoji name
+func (r Reactions) WithEmoji(emoji string) Reactions {
+	return sliceutil.Filter(r, func(rr *Reaction, _ int) bool {
+		return rr.Is(emoji)
+	})
+}
+
+// UserIDs returns IDs of users who reacted with any of given emoji or with
+// any emoji if no emoji is given
+func (r Reactions) UserIDs(emoji ...string) []uint {
+	var result []uint
+
+	for _, rr := range r {
+		if rr == nil || slices.Contains(result, rr.UserID) {
+			continue
+		}
+
+		if len(emoji) == 0 || slices.ContainsFunc(emoji, rr.Is) {
+			result = append(result, rr.UserID)
+		}
+	}
+
+	return result
+}
+
+// HasUser returns true if user with given ID reacted with given emoji
+func (r Reactions) HasUser(userID uint, emoji string) bool {
+	return slices.ContainsFunc(r, func(rr *Reaction) bool {
+		return rr.Is(emoji) && rr.UserID == userID
+	})
+}
+
+// NotReacted returns users who haven't reacted with given emoji
+func (r Reactions) NotReacted(users Users, emoji string) Users {
+	reacted := r.UserIDs(emoji)
+
+	return sliceutil.Filter(users, func(u *User, _ int) bool {
+		return !slices.Contains(reacted, u.ID)
+	})
+}
+
+// Is returns true if reaction has given emoji or emoji name
+func (r *Reaction) Is(emoji string) bool {
+	return r != nil && emoji != "" && (r.Emoji == emoji || r.Name == emoji)
+}
+
+// Get returns group with given emoji or emoji name
+func (g ReactionGroups) Get(emoji string) *ReactionGroup {
+	for _, gg := range g {
+		if emoji != "" && (gg.Emoji == emoji || gg.Name == emoji) {
+			return gg
+		}
+	}
+
+	return nil
+}
+
 // URL returns chat URL
 func (c *Chat) URL() string {
 	if c == nil {
@@ -3822,6 +4128,19 @@ func (c *Client) sendRequest(method, url string, query req.Query, payload any, r
 	return nil
 }
 
+// setUserSuspended sets user suspension flag
+func (c *Client) setUserSuspended(userID uint, suspended bool) error {
+	payload := &struct {
+		User struct {
+			IsSuspended bool `json:"suspended"`
+		} `json:"user"`
+	}{}
+
+	payload.User.IsSuspended = suspended
+
+	return c.sendRequest(req.PUT, getURL("/users/%d", userID), nil, payload, nil)
+}
+
 // uploadFile uploads given file using multipart upload
 func (c *Client) uploadFile(method, url, file string, response any) error {
 	r := req.Request{
@@ -3853,6 +4172,62 @@ func (c *Client) uploadFile(method, url, file string, response any) error {
 	return nil
 }
 
+// uploadReader uploads data from given reader using multipart upload
+func (c *Client) uploadReader(method, url, name string, r io.Reader, response any) error {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		defer pw.Close()
+		defer mw.Close()
+
+		fw, err := mw.CreateFormFile("image", name)
+
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(fw, r)
+
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+	}()
+
+	resp, err := c.engine.Do(req.Request{
+		Method:      method,
+		URL:         url,
+		ContentType: mw.FormDataContentType(),
+		Body:        pr,
+		Auth:        req.AuthBearer{c.token},
+	})
+
+	if err != nil {
+		pr.CloseWithError(err)
+		return fmt.Errorf("can't send request to API: %w", err)
+	}
+
+	// Unblock writer if API responded before reading the whole body
+	defer pr.Close()
+	defer resp.Discard()
+
+	if resp.StatusCode >= 400 {
+		return unmarshalError(resp)
+	}
+
+	if response != nil {
+		err = resp.JSON(response)
+
+		if err != nil {
+			return fmt.Errorf("can't decode API response: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // unmarshalError decodes error from Pachca API
@@ -3861,7 +4236,10 @@ func unmarshalError(resp *req.Response) error {
 	case 401, 403:
 		return unmarshalBasicError(resp)
 
-	case 400, 402, 404, 409, 410, 422:
+	case 404:
+		return fmt.Errorf("%w: %w", ErrNotFound, unmarshalDetailedError(resp))
+
+	case 400, 402, 409, 410, 422:
 		return unmarshalDetailedError(resp)
 
 	case 429:
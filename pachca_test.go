@@ -8,6 +8,7 @@ package pachca
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -93,6 +94,9 @@ func (s *PachcaSuite) TestNilClient(c *C) {
 	c.Assert(err, Equals, ErrNilClient)
 
 	c.Assert(cc.DeleteUser(1), Equals, ErrNilClient)
+	c.Assert(cc.SuspendUser(1), Equals, ErrNilClient)
+	c.Assert(cc.UnsuspendUser(1), Equals, ErrNilClient)
+	c.Assert(cc.SetUserTags(1, nil), Equals, ErrNilClient)
 
 	// BOTS
 
@@ -127,6 +131,12 @@ func (s *PachcaSuite) TestNilClient(c *C) {
 	_, err = cc.UpdateUserAvatar(1, "test")
 	c.Assert(err, Equals, ErrNilClient)
 
+	_, err = cc.UpdateAvatarFromReader("test.png", nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	_, err = cc.UpdateUserAvatarFromReader(1, "test.png", nil)
+	c.Assert(err, Equals, ErrNilClient)
+
 	err = cc.DeleteUserAvatar(1)
 	c.Assert(err, Equals, ErrNilClient)
 
@@ -266,8 +276,9 @@ func (s *PachcaSuite) TestNilClient(c *C) {
 func (s *PachcaSuite) TestNewPropertyRequest(c *C) {
 	c.Assert(NewPropertyRequest(1, "test").Value, Equals, "test")
 	c.Assert(NewPropertyRequest(1, 100).Value, Equals, "100")
-	c.Assert(NewPropertyRequest(1, float32(100.12)).Value, Equals, "100")
-	c.Assert(NewPropertyRequest(1, float64(100.12)).Value, Equals, "100")
+	c.Assert(NewPropertyRequest(1, float32(100.12)).Value, Equals, "100.12")
+	c.Assert(NewPropertyRequest(1, float64(100.12)).Value, Equals, "100.12")
+	c.Assert(NewPropertyRequest(1, float64(100)).Value, Equals, "100")
 	c.Assert(NewPropertyRequest(1, time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)).Value, Equals, "2020-01-01T12:00:00Z")
 	c.Assert(NewPropertyRequest(1, true).Value, Equals, "true")
 }
@@ -307,6 +318,9 @@ func (s *PachcaSuite) TestErrors(c *C) {
 	c.Assert(err, Equals, ErrNilUserRequest)
 
 	c.Assert(cc.DeleteUser(0), Equals, ErrInvalidUserID)
+	c.Assert(cc.SuspendUser(0), Equals, ErrInvalidUserID)
+	c.Assert(cc.UnsuspendUser(0), Equals, ErrInvalidUserID)
+	c.Assert(cc.SetUserTags(0, nil), Equals, ErrInvalidUserID)
 
 	// BOTS
 
@@ -337,6 +351,21 @@ func (s *PachcaSuite) TestErrors(c *C) {
 	_, err = cc.UpdateUserAvatar(1, "")
 	c.Assert(err, Equals, ErrEmptyFilePath)
 
+	_, err = cc.UpdateAvatarFromReader("", nil)
+	c.Assert(err, Equals, ErrEmptyFileName)
+
+	_, err = cc.UpdateAvatarFromReader("test.png", nil)
+	c.Assert(err, Equals, ErrNilReader)
+
+	_, err = cc.UpdateUserAvatarFromReader(0, "", nil)
+	c.Assert(err, Equals, ErrInvalidUserID)
+
+	_, err = cc.UpdateUserAvatarFromReader(1, "", nil)
+	c.Assert(err, Equals, ErrEmptyFileName)
+
+	_, err = cc.UpdateUserAvatarFromReader(1, "test.png", nil)
+	c.Assert(err, Equals, ErrNilReader)
+
 	err = cc.DeleteUserAvatar(0)
 	c.Assert(err, Equals, ErrInvalidUserID)
 
@@ -651,6 +680,8 @@ func (s *PachcaSuite) TestUsersHelpers(c *C) {
 	c.Assert(uu.WithoutGuests(), HasLen, 5)
 	c.Assert(uu.Paid(), HasLen, 6)
 	c.Assert(uu.WithTag("developers"), HasLen, 2)
+	c.Assert(uu.Filter(nil), HasLen, len(uu))
+	c.Assert(uu.Filter(func(u *User) bool { return u.ID > 5 }), HasLen, 2)
 
 	c.Assert(uu.Find("test"), IsNil)
 	c.Assert(uu.Find("j.doe"), NotNil)
@@ -683,6 +714,9 @@ func (s *PachcaSuite) TestChatsHelpers(c *C) {
 
 	c.Assert(cc.Communal()[0].ID, Equals, uint(1))
 	c.Assert(cc.Personal()[0].ID, Equals, uint(5))
+
+	c.Assert(cc.Filter(nil), HasLen, 5)
+	c.Assert(cc.Filter(func(c *Chat) bool { return c.ID%2 == 0 }), HasLen, 2)
 }
 
 func (s *PachcaSuite) TestTagsHelpers(c *C) {
@@ -705,6 +739,46 @@ func (s *PachcaSuite) TestTagsHelpers(c *C) {
 	c.Assert(tt.InChat(chat), HasLen, 3)
 
 	c.Assert(tt.Names(), DeepEquals, []string{"Test1", "Test2", "Test3"})
+
+	c.Assert(tt.Filter(nil), HasLen, 3)
+	c.Assert(tt.Filter(func(t *Tag) bool { return t.UsersCount >= 5 }), HasLen, 2)
+}
+
+func (s *PachcaSuite) TestReactionsHelpers(c *C) {
+	rr := Reactions{
+		{UserID: 1, Emoji: "👍", Name: ":+1:"},
+		{UserID: 2, Emoji: "✅", Name: ":white_check_mark:"},
+		{UserID: 3, Emoji: "✅", Name: ":white_check_mark:"},
+		{UserID: 1, Emoji: "✅", Name: ":white_check_mark:"},
+		nil,
+	}
+
+	g := rr.Group()
+	c.Assert(g, HasLen, 2)
+	c.Assert(g[0].Emoji, Equals, "✅")
+	c.Assert(g[0].Count, Equals, 3)
+	c.Assert(g[0].Users, DeepEquals, []uint{2, 3, 1})
+	c.Assert(g[1].Emoji, Equals, "👍")
+	c.Assert(g.Get(":+1:"), Equals, g[1])
+	c.Assert(g.Get("🔥"), IsNil)
+	c.Assert(g.Get(""), IsNil)
+
+	c.Assert(rr.WithEmoji("✅"), HasLen, 3)
+	c.Assert(rr.WithEmoji(":+1:"), HasLen, 1)
+	c.Assert(rr.UserIDs(), DeepEquals, []uint{1, 2, 3})
+	c.Assert(rr.UserIDs("👍"), DeepEquals, []uint{1})
+	c.Assert(rr.UserIDs("🔥"), IsNil)
+	c.Assert(rr.HasUser(1, "👍"), Equals, true)
+	c.Assert(rr.HasUser(2, "👍"), Equals, false)
+
+	uu := Users{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	c.Assert(rr.NotReacted(uu, "👍"), HasLen, 3)
+	c.Assert(rr.NotReacted(uu, "✅"), HasLen, 1)
+	c.Assert(rr.NotReacted(uu, "✅")[0].ID, Equals, uint(4))
+
+	var nr *Reaction
+	c.Assert(nr.Is("👍"), Equals, false)
+	c.Assert(Reactions{}.Group(), IsNil)
 }
 
 func (s *PachcaSuite) TestURLHelpers(c *C) {
@@ -895,6 +969,16 @@ func (s *PachcaSuite) TestJSONDateDecoder(c *C) {
 	c.Assert(d.IsZero(), Equals, false)
 }
 
+func (s *PachcaSuite) TestUserRequestEncoder(c *C) {
+	data, err := json.Marshal(&UserRequest{Title: "CEO"})
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"title":"CEO"}`)
+
+	data, err = json.Marshal(&UserRequest{Title: "CEO", ClearFields: []string{"nickname", "title"}})
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"nickname":"","title":"CEO"}`)
+}
+
 func (s *PachcaSuite) TestAPIErrorToString(c *C) {
 	var s3Err *S3Error
 
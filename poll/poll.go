@@ -0,0 +1,712 @@
+package poll
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DATA_PREFIX is prefix of poll buttons data
+const DATA_PREFIX = "poll:"
+
+// MAX_OPTIONS is maximum number of poll options
+const MAX_OPTIONS = 20
+
+// DATE_LAYOUT is layout of poll deadline date
+const DATE_LAYOUT = "2006-01-02 15:04 MST"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by polls manager
+type Client interface {
+	AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error)
+	EditMessage(messageID uint, message *pachca.MessageRequest) (*pachca.Message, error)
+	DeleteMessageButtons(messageID uint) error
+}
+
+// Store is storage for polls
+type Store interface {
+	// Get returns poll with given message ID or nil if there is no such poll
+	Get(messageID uint) (*Poll, error)
+
+	// Set saves poll
+	Set(poll *Poll) error
+
+	// List returns all polls
+	List() ([]*Poll, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Poll contains poll info and votes
+type Poll struct {
+	MessageID   uint           `json:"message_id"`
+	ChatID      uint           `json:"chat_id"`
+	Question    string         `json:"question"`
+	Options     []string       `json:"options"`
+	Votes       map[uint][]int `json:"votes"`
+	Deadline    time.Time      `json:"deadline,omitzero"`
+	IsMultiple  bool           `json:"multiple"`
+	IsAnonymous bool           `json:"anonymous"`
+	AllowChange bool           `json:"allow_change"`
+	IsClosed    bool           `json:"closed"`
+}
+
+// Manager creates polls and handles votes
+type Manager struct {
+	// Location is time zone of deadline date (UTC by default)
+	Location *time.Location
+
+	client Client
+	store  Store
+
+	mu sync.Mutex
+}
+
+// MemoryStore is in-memory polls store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[uint]*Poll
+}
+
+// FileStore is polls store which keeps data in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data map[string]*Poll
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient      = errors.New("client is nil")
+	ErrNilStore       = errors.New("store is nil")
+	ErrNilManager     = errors.New("manager is nil")
+	ErrNilPoll        = errors.New("poll is nil")
+	ErrEmptyQuestion  = errors.New("poll question is empty")
+	ErrEmptyOption    = errors.New("poll option is empty")
+	ErrNotEnoughOpts  = errors.New("poll must have at least 2 options")
+	ErrTooManyOpts    = errors.New("poll has too many options")
+	ErrEmptyFilePath  = errors.New("store file path is empty")
+	ErrInvalidChatID  = errors.New("chat ID must be greater than 0")
+	ErrPollNotFound   = errors.New("poll not found")
+	ErrInvalidOption  = errors.New("invalid poll option")
+	ErrPollIsClosed   = errors.New("poll is closed")
+	ErrChangeDisabled = errors.New("vote change is not allowed")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new polls manager
+func New(client Client, store Store) (*Manager, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	}
+
+	return &Manager{client: client, store: store}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[uint]*Poll{}}
+}
+
+// NewFileStore creates new file store and loads polls from given file if it
+// exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file, data: map[string]*Poll{}}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Create posts poll to the chat and saves it to the store
+func (m *Manager) Create(poll *Poll) (*Poll, error) {
+	if m == nil {
+		return nil, ErrNilManager
+	}
+
+	err := poll.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.Votes == nil {
+		poll.Votes = map[uint][]int{}
+	}
+
+	msg, err := m.client.AddMessage(&pachca.MessageRequest{
+		EntityType: pachca.ENTITY_TYPE_DISCUSSION,
+		EntityID:   poll.ChatID,
+		Content:    m.Render(poll),
+		Buttons:    poll.Buttons(),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't post poll: %w", err)
+	}
+
+	poll.MessageID = msg.ID
+
+	err = m.store.Set(poll)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save poll: %w", err)
+	}
+
+	return poll, nil
+}
+
+// IsPollButton returns true if button event is related to poll
+func IsPollButton(b *webhook.Button) bool {
+	return b != nil && strings.HasPrefix(b.Data, DATA_PREFIX)
+}
+
+// HandleButton handles button webhook event and updates poll message. It
+// returns nil poll if button isn't a poll button.
+func (m *Manager) HandleButton(b *webhook.Button) (*Poll, error) {
+	switch {
+	case m == nil:
+		return nil, ErrNilManager
+	case !IsPollButton(b):
+		return nil, nil
+	}
+
+	option, err := strconv.Atoi(strings.TrimPrefix(b.Data, DATA_PREFIX))
+
+	if err != nil {
+		return nil, ErrInvalidOption
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	poll, err := m.getPoll(b.MessageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !poll.IsClosed && poll.IsExpired(time.Now()) {
+		return m.close(poll)
+	}
+
+	// Vote on a copy, so the stored poll stays intact if it can't be saved
+	updated := poll.clone()
+	err = updated.Vote(b.UserID, option)
+
+	if err != nil {
+		return poll, err
+	}
+
+	err = m.store.Set(updated)
+
+	if err != nil {
+		return poll, fmt.Errorf("can't save poll: %w", err)
+	}
+
+	return updated, m.update(updated)
+}
+
+// Close closes poll, renders final results and removes buttons
+func (m *Manager) Close(messageID uint) (*Poll, error) {
+	if m == nil {
+		return nil, ErrNilManager
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	poll, err := m.getPoll(messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosed {
+		return poll, nil
+	}
+
+	return m.close(poll)
+}
+
+// CloseExpired closes all polls with deadline before given time
+func (m *Manager) CloseExpired(now time.Time) error {
+	if m == nil {
+		return ErrNilManager
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	polls, err := m.store.List()
+
+	if err != nil {
+		return fmt.Errorf("can't list polls: %w", err)
+	}
+
+	errs := errors.NewBundle()
+
+	for _, poll := range polls {
+		if !poll.IsClosed && poll.IsExpired(now) {
+			_, err = m.close(poll)
+			errs.Add(err)
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// Render renders poll message content
+func (m *Manager) Render(poll *Poll) string {
+	if poll == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	counts := poll.Counts()
+	total := poll.VotesNum()
+
+	fmt.Fprintf(&sb, "📊 **%s**\n", poll.Question)
+
+	for i, option := range poll.Options {
+		var percent int
+
+		if total > 0 {
+			percent = int(math.Round(float64(counts[i]) / float64(total) * 100))
+		}
+
+		fmt.Fprintf(&sb, "\n**%s** — %d (%d%%)", option, counts[i], percent)
+
+		if !poll.IsAnonymous {
+			voters := poll.Voters(i)
+
+			if len(voters) != 0 {
+				mentions := make([]string, 0, len(voters))
+
+				for _, id := range voters {
+					mentions = append(mentions, fmt.Sprintf("<@%d>", id))
+				}
+
+				sb.WriteString("\n" + strings.Join(mentions, " "))
+			}
+		}
+	}
+
+	var info []string
+
+	if poll.IsAnonymous {
+		info = append(info, "Anonymous")
+	}
+
+	if poll.IsMultiple {
+		info = append(info, "Multiple choice")
+	}
+
+	info = append(info, fmt.Sprintf("Voted: %d", len(poll.Votes)))
+
+	switch {
+	case poll.IsClosed:
+		info = append(info, "Closed")
+	case !poll.Deadline.IsZero():
+		loc := m.getLocation()
+		info = append(info, "Until "+poll.Deadline.In(loc).Format(DATE_LAYOUT))
+	}
+
+	sb.WriteString("\n\n_" + strings.Join(info, " · ") + "_")
+
+	return sb.String()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates poll
+func (p *Poll) Validate() error {
+	switch {
+	case p == nil:
+		return ErrNilPoll
+	case p.ChatID == 0:
+		return ErrInvalidChatID
+	case strings.TrimSpace(p.Question) == "":
+		return ErrEmptyQuestion
+	case len(p.Options) < 2:
+		return ErrNotEnoughOpts
+	case len(p.Options) > MAX_OPTIONS:
+		return ErrTooManyOpts
+	}
+
+	for _, option := range p.Options {
+		if strings.TrimSpace(option) == "" {
+			return ErrEmptyOption
+		}
+	}
+
+	return nil
+}
+
+// Vote registers vote of user for option with given index. Repeated vote for
+// the same option removes it, and vote for another option in single choice poll
+// replaces previous one. Both are treated as vote change and require AllowChange.
+func (p *Poll) Vote(userID uint, option int) error {
+	switch {
+	case p == nil:
+		return ErrNilPoll
+	case p.IsClosed:
+		return ErrPollIsClosed
+	case option < 0 || option >= len(p.Options):
+		return ErrInvalidOption
+	}
+
+	if p.Votes == nil {
+		p.Votes = map[uint][]int{}
+	}
+
+	votes := p.Votes[userID]
+	hasVote := slices.Contains(votes, option)
+
+	switch {
+	case len(votes) == 0:
+		p.Votes[userID] = []int{option}
+
+	case p.IsMultiple && !hasVote:
+		// Adding one more option in multiple choice poll isn't a vote change
+		votes = append(votes, option)
+		slices.Sort(votes)
+		p.Votes[userID] = votes
+
+	case !p.AllowChange:
+		return ErrChangeDisabled
+
+	case hasVote:
+		votes = slices.DeleteFunc(votes, func(v int) bool { return v == option })
+
+		if len(votes) == 0 {
+			delete(p.Votes, userID)
+		} else {
+			p.Votes[userID] = votes
+		}
+
+	default:
+		p.Votes[userID] = []int{option}
+	}
+
+	return nil
+}
+
+// Counts returns number of votes for every option
+func (p *Poll) Counts() []int {
+	if p == nil {
+		return nil
+	}
+
+	result := make([]int, len(p.Options))
+
+	for _, votes := range p.Votes {
+		for _, v := range votes {
+			if v >= 0 && v < len(result) {
+				result[v]++
+			}
+		}
+	}
+
+	return result
+}
+
+// VotesNum returns total number of votes
+func (p *Poll) VotesNum() int {
+	var result int
+
+	for _, c := range p.Counts() {
+		result += c
+	}
+
+	return result
+}
+
+// Voters returns sorted IDs of users who voted for option with given index
+func (p *Poll) Voters(option int) []uint {
+	if p == nil {
+		return nil
+	}
+
+	var result []uint
+
+	for userID, votes := range p.Votes {
+		if slices.Contains(votes, option) {
+			result = append(result, userID)
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// Buttons returns poll buttons (one button per option)
+func (p *Poll) Buttons() pachca.Buttons {
+	if p == nil {
+		return nil
+	}
+
+	counts := p.Counts()
+	result := make(pachca.Buttons, 0, len(p.Options))
+
+	for i, option := range p.Options {
+		text := option
+
+		if counts[i] > 0 {
+			text = fmt.Sprintf("%s (%d)", option, counts[i])
+		}
+
+		result = append(result, pachca.ButtonLine{
+			{Text: text, Data: DATA_PREFIX + strconv.Itoa(i)},
+		})
+	}
+
+	return result
+}
+
+// IsExpired returns true if poll deadline is before given time
+func (p *Poll) IsExpired(now time.Time) bool {
+	return p != nil && !p.Deadline.IsZero() && !now.Before(p.Deadline)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns poll with given message ID or nil if there is no such poll
+func (s *MemoryStore) Get(messageID uint) (*Poll, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data[messageID], nil
+}
+
+// Set saves poll
+func (s *MemoryStore) Set(poll *Poll) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case poll == nil:
+		return ErrNilPoll
+	}
+
+	s.mu.Lock()
+	s.data[poll.MessageID] = poll
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns all polls
+func (s *MemoryStore) List() ([]*Poll, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Poll, 0, len(s.data))
+
+	for _, poll := range s.data {
+		result = append(result, poll)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns poll with given message ID or nil if there is no such poll
+func (s *FileStore) Get(messageID uint) (*Poll, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data[getStoreKey(messageID)], nil
+}
+
+// Set saves poll
+func (s *FileStore) Set(poll *Poll) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case poll == nil:
+		return ErrNilPoll
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[getStoreKey(poll.MessageID)] = poll
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// List returns all polls
+func (s *FileStore) List() ([]*Poll, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Poll, 0, len(s.data))
+
+	for _, poll := range s.data {
+		result = append(result, poll)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getPoll returns poll from the store
+func (m *Manager) getPoll(messageID uint) (*Poll, error) {
+	poll, err := m.store.Get(messageID)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get poll: %w", err)
+	}
+
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	return poll, nil
+}
+
+// update updates poll message
+func (m *Manager) update(poll *Poll) error {
+	_, err := m.client.EditMessage(poll.MessageID, &pachca.MessageRequest{
+		Content: m.Render(poll),
+		Buttons: poll.Buttons(),
+	})
+
+	if err != nil {
+		return fmt.Errorf("can't update poll message: %w", err)
+	}
+
+	return nil
+}
+
+// close marks copy of poll as closed, saves it and removes buttons from poll
+// message. Given poll is returned as is if closed poll can't be saved.
+func (m *Manager) close(poll *Poll) (*Poll, error) {
+	closed := poll.clone()
+	closed.IsClosed = true
+
+	err := m.store.Set(closed)
+
+	if err != nil {
+		return poll, fmt.Errorf("can't save poll: %w", err)
+	}
+
+	_, err = m.client.EditMessage(closed.MessageID, &pachca.MessageRequest{
+		Content: m.Render(closed),
+	})
+
+	if err != nil {
+		return closed, fmt.Errorf("can't update poll message: %w", err)
+	}
+
+	err = m.client.DeleteMessageButtons(closed.MessageID)
+
+	if err != nil {
+		return closed, fmt.Errorf("can't remove poll buttons: %w", err)
+	}
+
+	return closed, nil
+}
+
+// getLocation returns location for dates
+func (m *Manager) getLocation() *time.Location {
+	if m == nil || m.Location == nil {
+		return time.UTC
+	}
+
+	return m.Location
+}
+
+// clone returns deep copy of poll
+func (p *Poll) clone() *Poll {
+	result := *p
+
+	result.Options = slices.Clone(p.Options)
+
+	if p.Votes != nil {
+		result.Votes = make(map[uint][]int, len(p.Votes))
+
+		for userID, votes := range p.Votes {
+			result.Votes[userID] = slices.Clone(votes)
+		}
+	}
+
+	return &result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getStoreKey returns key for storing poll
+func getStoreKey(messageID uint) string {
+	return strconv.FormatUint(uint64(messageID), 10)
+}
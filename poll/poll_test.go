@@ -0,0 +1,342 @@
+package poll
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type PollSuite struct{}
+
+type fakeClient struct {
+	messages map[uint]*pachca.MessageRequest
+	removed  []uint
+	fail     bool
+}
+
+type failStore struct {
+	*MemoryStore
+	fail bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&PollSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *PollSuite) TestSingleChoice(c *C) {
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+	m, err := New(client, NewMemoryStore())
+	c.Assert(err, IsNil)
+
+	p, err := m.Create(&Poll{ChatID: 10, Question: "Lunch?", Options: []string{"Pizza", "Sushi"}})
+	c.Assert(err, IsNil)
+	c.Assert(p.MessageID, Equals, uint(1))
+	c.Assert(client.messages[1].EntityID, Equals, uint(10))
+	c.Assert(client.messages[1].Content, Equals, "📊 **Lunch?**\n\n**Pizza** — 0 (0%)\n**Sushi** — 0 (0%)\n\n_Voted: 0_")
+	c.Assert(client.messages[1].Buttons, HasLen, 2)
+	c.Assert(client.messages[1].Buttons[1][0].Data, Equals, "poll:1")
+
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:0"})
+	c.Assert(err, IsNil)
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 6, Data: "poll:0"})
+	c.Assert(err, IsNil)
+	p, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 7, Data: "poll:1"})
+	c.Assert(err, IsNil)
+	c.Assert(p.Counts(), DeepEquals, []int{2, 1})
+
+	c.Assert(client.messages[1].Content, Equals, "📊 **Lunch?**\n\n**Pizza** — 2 (67%)\n<@5> <@6>\n**Sushi** — 1 (33%)\n<@7>\n\n_Voted: 3_")
+	c.Assert(client.messages[1].Buttons[0][0].Text, Equals, "Pizza (2)")
+
+	// Vote change is not allowed
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 7, Data: "poll:0"})
+	c.Assert(err, Equals, ErrChangeDisabled)
+
+	p, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 7, Data: "other"})
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 7, Data: "poll:x"})
+	c.Assert(err, Equals, ErrInvalidOption)
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 7, Data: "poll:5"})
+	c.Assert(err, Equals, ErrInvalidOption)
+	_, err = m.HandleButton(&webhook.Button{MessageID: 2, UserID: 7, Data: "poll:0"})
+	c.Assert(err, Equals, ErrPollNotFound)
+
+	p, err = m.Close(1)
+	c.Assert(err, IsNil)
+	c.Assert(p.IsClosed, Equals, true)
+	c.Assert(client.removed, DeepEquals, []uint{1})
+	c.Assert(client.messages[1].Content, Matches, `(?s).*_Voted: 3 · Closed_`)
+
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 8, Data: "poll:0"})
+	c.Assert(err, Equals, ErrPollIsClosed)
+
+	_, err = m.Close(1)
+	c.Assert(err, IsNil)
+	c.Assert(client.removed, HasLen, 1)
+
+	_, err = m.Close(2)
+	c.Assert(err, Equals, ErrPollNotFound)
+}
+
+func (s *PollSuite) TestMultipleChoice(c *C) {
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+	m, _ := New(client, NewMemoryStore())
+	m.Location = time.FixedZone("MSK", 3*3600)
+
+	deadline := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p, err := m.Create(&Poll{
+		ChatID: 10, Question: "Languages", Options: []string{"Go", "Rust", "Zig"},
+		IsMultiple: true, IsAnonymous: true, AllowChange: true, Deadline: deadline,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(client.messages[1].Content, Matches, `(?s).*_Anonymous · Multiple choice · Voted: 0 · Until 2030-01-01 15:00 MSK_`)
+
+	c.Assert(p.Vote(1, 0), IsNil)
+	c.Assert(p.Vote(1, 2), IsNil)
+	c.Assert(p.Vote(2, 2), IsNil)
+	c.Assert(p.Votes[1], DeepEquals, []int{0, 2})
+	c.Assert(p.Counts(), DeepEquals, []int{1, 0, 2})
+	c.Assert(p.Voters(2), DeepEquals, []uint{1, 2})
+
+	// Toggle vote off
+	c.Assert(p.Vote(2, 2), IsNil)
+	c.Assert(p.Votes[2], IsNil)
+	c.Assert(p.Vote(1, 0), IsNil)
+	c.Assert(p.Votes[1], DeepEquals, []int{2})
+
+	c.Assert(m.Render(p), Not(Matches), `.*<@1>.*`)
+
+	c.Assert(m.CloseExpired(deadline.Add(-time.Minute)), IsNil)
+	c.Assert(p.IsClosed, Equals, false)
+	c.Assert(m.CloseExpired(deadline), IsNil)
+	p, _ = m.store.Get(1)
+	c.Assert(p.IsClosed, Equals, true)
+	c.Assert(client.removed, DeepEquals, []uint{1})
+}
+
+func (s *PollSuite) TestSingleChoiceChange(c *C) {
+	p := &Poll{Options: []string{"A", "B"}, AllowChange: true}
+
+	c.Assert(p.Vote(1, 0), IsNil)
+	c.Assert(p.Vote(1, 1), IsNil)
+	c.Assert(p.Votes[1], DeepEquals, []int{1})
+	c.Assert(p.Vote(1, 1), IsNil)
+	c.Assert(p.Votes, HasLen, 0)
+	c.Assert(p.VotesNum(), Equals, 0)
+}
+
+func (s *PollSuite) TestMultipleChoiceWithoutChange(c *C) {
+	p := &Poll{Options: []string{"A", "B", "C"}, IsMultiple: true}
+
+	c.Assert(p.Vote(1, 0), IsNil)
+	c.Assert(p.Vote(1, 2), IsNil)
+	c.Assert(p.Votes[1], DeepEquals, []int{0, 2})
+	c.Assert(p.Vote(1, 0), Equals, ErrChangeDisabled)
+	c.Assert(p.Votes[1], DeepEquals, []int{0, 2})
+	c.Assert(p.VotesNum(), Equals, 2)
+}
+
+func (s *PollSuite) TestExpiredOnVote(c *C) {
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+	m, _ := New(client, NewMemoryStore())
+
+	_, err := m.Create(&Poll{
+		ChatID: 10, Question: "Q", Options: []string{"A", "B"},
+		Deadline: time.Now().Add(-time.Minute),
+	})
+	c.Assert(err, IsNil)
+
+	p, err := m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:0"})
+	c.Assert(err, IsNil)
+	c.Assert(p.IsClosed, Equals, true)
+	c.Assert(p.Votes, HasLen, 0)
+	c.Assert(client.removed, DeepEquals, []uint{1})
+}
+
+func (s *PollSuite) TestStoreFailure(c *C) {
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+	store := &failStore{MemoryStore: NewMemoryStore()}
+	m, _ := New(client, store)
+
+	_, err := m.Create(&Poll{ChatID: 10, Question: "Q", Options: []string{"A", "B"}})
+	c.Assert(err, IsNil)
+
+	store.fail = true
+
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:1"})
+	c.Assert(err, ErrorMatches, "can't save poll: store error")
+	_, err = m.Close(1)
+	c.Assert(err, ErrorMatches, "can't save poll: store error")
+
+	p, err := store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(p.Votes, HasLen, 0)
+	c.Assert(p.IsClosed, Equals, false)
+	c.Assert(client.removed, HasLen, 0)
+
+	store.fail = false
+
+	p, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:1"})
+	c.Assert(err, IsNil)
+	c.Assert(p.Votes, DeepEquals, map[uint][]int{5: {1}})
+}
+
+func (s *PollSuite) TestFileStore(c *C) {
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	file := c.MkDir() + "/polls.json"
+	store, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+	m, _ := New(client, store)
+
+	_, err = m.Create(&Poll{ChatID: 10, Question: "Q", Options: []string{"A", "B"}})
+	c.Assert(err, IsNil)
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:1"})
+	c.Assert(err, IsNil)
+
+	store, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	p, err := store.Get(1)
+	c.Assert(err, IsNil)
+	c.Assert(p.Votes, DeepEquals, map[uint][]int{5: {1}})
+
+	polls, err := store.List()
+	c.Assert(err, IsNil)
+	c.Assert(polls, HasLen, 1)
+
+	os.WriteFile(file, []byte("{broken"), 0600)
+	_, err = NewFileStore(file)
+	c.Assert(err, NotNil)
+}
+
+func (s *PollSuite) TestErrors(c *C) {
+	client := &fakeClient{messages: map[uint]*pachca.MessageRequest{}}
+
+	_, err := New(nil, NewMemoryStore())
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(client, nil)
+	c.Assert(err, Equals, ErrNilStore)
+
+	m, _ := New(client, NewMemoryStore())
+
+	_, err = m.Create(nil)
+	c.Assert(err, Equals, ErrNilPoll)
+	_, err = m.Create(&Poll{})
+	c.Assert(err, Equals, ErrInvalidChatID)
+	_, err = m.Create(&Poll{ChatID: 1})
+	c.Assert(err, Equals, ErrEmptyQuestion)
+	_, err = m.Create(&Poll{ChatID: 1, Question: "Q", Options: []string{"A"}})
+	c.Assert(err, Equals, ErrNotEnoughOpts)
+	_, err = m.Create(&Poll{ChatID: 1, Question: "Q", Options: strings.Split(strings.Repeat("A,", 21), ",")})
+	c.Assert(err, Equals, ErrTooManyOpts)
+	_, err = m.Create(&Poll{ChatID: 1, Question: "Q", Options: []string{"A", " "}})
+	c.Assert(err, Equals, ErrEmptyOption)
+
+	client.fail = true
+	_, err = m.Create(&Poll{ChatID: 1, Question: "Q", Options: []string{"A", "B"}})
+	c.Assert(err, ErrorMatches, "can't post poll: API error")
+
+	client.fail = false
+	m.Create(&Poll{ChatID: 1, Question: "Q", Options: []string{"A", "B"}})
+	client.fail = true
+
+	_, err = m.HandleButton(&webhook.Button{MessageID: 1, UserID: 5, Data: "poll:1"})
+	c.Assert(err, ErrorMatches, "can't update poll message: API error")
+	_, err = m.Close(1)
+	c.Assert(err, ErrorMatches, "can't update poll message: API error")
+
+	var nm *Manager
+	_, err = nm.Create(&Poll{})
+	c.Assert(err, Equals, ErrNilManager)
+	_, err = nm.HandleButton(nil)
+	c.Assert(err, Equals, ErrNilManager)
+	_, err = nm.Close(1)
+	c.Assert(err, Equals, ErrNilManager)
+	c.Assert(nm.CloseExpired(time.Now()), Equals, ErrNilManager)
+	c.Assert(nm.Render(nil), Equals, "")
+
+	var np *Poll
+	c.Assert(np.Vote(1, 0), Equals, ErrNilPoll)
+	c.Assert(np.Counts(), IsNil)
+	c.Assert(np.Voters(0), IsNil)
+	c.Assert(np.Buttons(), IsNil)
+	c.Assert(np.IsExpired(time.Now()), Equals, false)
+	c.Assert(IsPollButton(nil), Equals, false)
+
+	var nms *MemoryStore
+	_, err = nms.Get(1)
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nms.Set(&Poll{}), Equals, ErrNilStore)
+	_, err = nms.List()
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(NewMemoryStore().Set(nil), Equals, ErrNilPoll)
+
+	var nfs *FileStore
+	_, err = nfs.Get(1)
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nfs.Set(&Poll{}), Equals, ErrNilStore)
+	_, err = nfs.List()
+	c.Assert(err, Equals, ErrNilStore)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error) {
+	if c.fail {
+		return nil, fmt.Errorf("API error")
+	}
+
+	id := uint(len(c.messages) + 1)
+	c.messages[id] = message
+
+	return &pachca.Message{ID: id}, nil
+}
+
+func (c *fakeClient) EditMessage(messageID uint, message *pachca.MessageRequest) (*pachca.Message, error) {
+	if c.fail {
+		return nil, fmt.Errorf("API error")
+	}
+
+	c.messages[messageID] = message
+
+	return &pachca.Message{ID: messageID}, nil
+}
+
+func (c *fakeClient) DeleteMessageButtons(messageID uint) error {
+	c.removed = append(c.removed, messageID)
+	return nil
+}
+
+func (s *failStore) Set(poll *Poll) error {
+	if s.fail {
+		return fmt.Errorf("store error")
+	}
+
+	return s.MemoryStore.Set(poll)
+}
@@ -0,0 +1,451 @@
+package props
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TAG is name of struct tag with property name or ID
+const TAG = "prop"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// field contains info about struct field bound to property
+type field struct {
+	Name      string // Property name
+	ID        uint   // Property ID
+	OmitEmpty bool   // Skip zero value while encoding
+
+	path  string
+	value reflect.Value
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilUser       = errors.New("user is nil")
+	ErrInvalidTarget = errors.New("target must be a non-nil pointer to struct")
+	ErrInvalidSource = errors.New("source must be a struct or a non-nil pointer to struct")
+)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	urlType  = reflect.TypeFor[url.URL]()
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DecodeUser decodes user custom properties into struct
+func DecodeUser(user *pachca.User, v any) error {
+	if user == nil {
+		return ErrNilUser
+	}
+
+	return Decode(user.Properties, v)
+}
+
+// Decode decodes custom properties into struct. Struct fields are bound to
+// properties using "prop" tag with property name or ID:
+//
+//	type Employee struct {
+//	  Birthday time.Time `prop:"Birthday"`
+//	  Office   string    `prop:"12"`
+//	  Profile  *url.URL  `prop:"Profile,omitempty"`
+//	}
+//
+// Supported field types are string, bool, integers, floats, time.Time (date
+// properties), url.URL (link properties) and pointers to them. Fields bound to
+// missing or empty properties are set to zero values.
+func Decode(props pachca.Properties, v any) error {
+	fields, err := targetFields(v)
+
+	if err != nil {
+		return err
+	}
+
+	errs := errors.NewBundle()
+
+	for _, f := range fields {
+		prop := f.find(props)
+
+		if !prop.IsSet() {
+			f.value.Set(reflect.Zero(f.value.Type()))
+			continue
+		}
+
+		err = decodeValue(f.value, prop)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't decode property %q into field %s: %w", prop.Name, f.path, err))
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// Encode encodes struct into property requests. Properties referenced by
+// struct tags are resolved using given definitions (result of GetProperties).
+// Zero values clear properties unless field has "omitempty" option.
+func Encode(v any, defs pachca.Properties) (pachca.PropertyRequests, error) {
+	fields, err := sourceFields(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result pachca.PropertyRequests
+
+	errs := errors.NewBundle()
+
+	for _, f := range fields {
+		prop := f.find(defs)
+
+		if prop == nil {
+			errs.Add(fmt.Errorf("can't encode field %s: unknown property %s", f.path, f.ref()))
+			continue
+		}
+
+		if f.OmitEmpty && isEmpty(f.value) {
+			continue
+		}
+
+		value, err := encodeValue(f.value, prop)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't encode field %s into property %q: %w", f.path, prop.Name, err))
+			continue
+		}
+
+		result = append(result, &pachca.PropertyRequest{ID: prop.ID, Value: value})
+	}
+
+	if !errs.IsEmpty() {
+		return nil, errs.Join()
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// find finds property bound to field
+func (f *field) find(props pachca.Properties) *pachca.Property {
+	if f.ID != 0 {
+		for _, p := range props {
+			if p != nil && p.ID == f.ID {
+				return p
+			}
+		}
+
+		return nil
+	}
+
+	for _, p := range props {
+		if p != nil && strings.EqualFold(p.Name, f.Name) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// ref returns property reference for error messages
+func (f *field) ref() string {
+	if f.ID != 0 {
+		return "with ID " + strconv.FormatUint(uint64(f.ID), 10)
+	}
+
+	return strconv.Quote(f.Name)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// targetFields returns fields of struct used as decoding target
+func targetFields(v any) ([]*field, error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidTarget
+	}
+
+	return collectFields(rv.Elem(), "")
+}
+
+// sourceFields returns fields of struct used as encoding source
+func sourceFields(v any) ([]*field, error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrInvalidSource
+	}
+
+	return collectFields(rv, "")
+}
+
+// collectFields collects tagged fields of struct including fields of embedded
+// structs
+func collectFields(rv reflect.Value, prefix string) ([]*field, error) {
+	var result []*field
+
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		tag, hasTag := sf.Tag.Lookup(TAG)
+
+		if !hasTag {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				fields, err := collectFields(rv.Field(i), prefix+sf.Name+".")
+
+				if err != nil {
+					return nil, err
+				}
+
+				result = append(result, fields...)
+			}
+
+			continue
+		}
+
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		if !isSupported(sf.Type) {
+			return nil, fmt.Errorf("field %s has unsupported type %s", prefix+sf.Name, sf.Type)
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		f := &field{
+			Name:      strings.TrimSpace(name),
+			OmitEmpty: opts == "omitempty",
+			path:      prefix + sf.Name,
+			value:     rv.Field(i),
+		}
+
+		if id, err := strconv.ParseUint(f.Name, 10, 64); err == nil {
+			f.ID = uint(id)
+		}
+
+		if f.Name == "" {
+			return nil, fmt.Errorf("field %s has empty property name", f.path)
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// isSupported returns true if type is supported
+func isSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType || t == urlType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// decodeValue decodes property value into field
+func decodeValue(v reflect.Value, prop *pachca.Property) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		err := decodeValue(ptr.Elem(), prop)
+
+		if err != nil {
+			return err
+		}
+
+		v.Set(ptr)
+
+		return nil
+	}
+
+	switch {
+	case v.Type() == timeType:
+		d, err := prop.ToDate()
+
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(d))
+
+		return nil
+
+	case v.Type() == urlType:
+		if prop.Type != pachca.PROP_TYPE_LINK {
+			return fmt.Errorf("invalid property type for link (%s)", prop.Type)
+		}
+
+		u, err := url.Parse(prop.Value)
+
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(*u))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(prop.Value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(prop.Value)
+
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if prop.Type != pachca.PROP_TYPE_NUMBER {
+			return fmt.Errorf("invalid property type for number (%s)", prop.Type)
+		}
+
+		i, err := strconv.ParseInt(prop.Value, 10, v.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if prop.Type != pachca.PROP_TYPE_NUMBER {
+			return fmt.Errorf("invalid property type for number (%s)", prop.Type)
+		}
+
+		u, err := strconv.ParseUint(prop.Value, 10, v.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		if prop.Type != pachca.PROP_TYPE_NUMBER {
+			return fmt.Errorf("invalid property type for number (%s)", prop.Type)
+		}
+
+		f, err := strconv.ParseFloat(prop.Value, v.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	}
+
+	return nil
+}
+
+// encodeValue encodes field value into property value
+func encodeValue(v reflect.Value, prop *pachca.Property) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		if prop.Type != pachca.PROP_TYPE_DATE {
+			return "", fmt.Errorf("invalid property type for date (%s)", prop.Type)
+		}
+
+		t := v.Interface().(time.Time)
+
+		if t.IsZero() {
+			return "", nil
+		}
+
+		return pachca.NewPropertyRequest(prop.ID, t).Value, nil
+
+	case v.Type() == urlType:
+		if prop.Type != pachca.PROP_TYPE_LINK {
+			return "", fmt.Errorf("invalid property type for link (%s)", prop.Type)
+		}
+
+		u := v.Interface().(url.URL)
+
+		return u.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if prop.Type != pachca.PROP_TYPE_NUMBER {
+			return "", fmt.Errorf("invalid property type for number (%s)", prop.Type)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if prop.Type != pachca.PROP_TYPE_NUMBER {
+			return "", fmt.Errorf("invalid property type for number (%s)", prop.Type)
+		}
+
+		f := v.Float()
+
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("value %v isn't an integer", f)
+		}
+
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+
+	return pachca.NewPropertyRequest(prop.ID, v.Interface()).Value, nil
+}
+
+// isEmpty returns true if value is zero
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer {
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
@@ -0,0 +1,272 @@
+package props
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type Contacts struct {
+	Office string `prop:"12"`
+}
+
+type Employee struct {
+	Contacts
+
+	Birthday time.Time `prop:"Birthday"`
+	Level    int       `prop:"level"`
+	Rating   *float64  `prop:"Rating,omitempty"`
+	Profile  *url.URL  `prop:"Profile,omitempty"`
+	Blog     url.URL   `prop:"Blog,omitempty"`
+	Ignored  string    `prop:"-"`
+	Other    string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type PropsSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&PropsSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *PropsSuite) TestDecode(c *C) {
+	props := pachca.Properties{
+		nil,
+		{ID: 10, Type: pachca.PROP_TYPE_DATE, Name: "Birthday", Value: "1990-05-17T00:00:00.000Z"},
+		{ID: 11, Type: pachca.PROP_TYPE_NUMBER, Name: "Level", Value: "3"},
+		{ID: 12, Type: pachca.PROP_TYPE_TEXT, Name: "Office", Value: "Moscow"},
+		{ID: 13, Type: pachca.PROP_TYPE_NUMBER, Name: "Rating", Value: "5"},
+		{ID: 14, Type: pachca.PROP_TYPE_LINK, Name: "Profile", Value: "https://kaos.sh/john"},
+		{ID: 15, Type: pachca.PROP_TYPE_LINK, Name: "Blog", Value: ""},
+	}
+
+	e := &Employee{Ignored: "test", Level: 10}
+
+	c.Assert(Decode(props, e), IsNil)
+	c.Assert(e.Birthday.Equal(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)), Equals, true)
+	c.Assert(e.Level, Equals, 3)
+	c.Assert(e.Office, Equals, "Moscow")
+	c.Assert(e.Rating, NotNil)
+	c.Assert(*e.Rating, Equals, 5.0)
+	c.Assert(e.Profile, NotNil)
+	c.Assert(e.Profile.String(), Equals, "https://kaos.sh/john")
+	c.Assert(e.Blog.String(), Equals, "")
+	c.Assert(e.Ignored, Equals, "test")
+
+	e = &Employee{Level: 10}
+
+	c.Assert(DecodeUser(&pachca.User{}, e), IsNil)
+	c.Assert(e.Level, Equals, 0)
+	c.Assert(e.Rating, IsNil)
+}
+
+func (s *PropsSuite) TestDecodeErrors(c *C) {
+	c.Assert(DecodeUser(nil, &Employee{}), Equals, ErrNilUser)
+	c.Assert(Decode(nil, nil), Equals, ErrInvalidTarget)
+	c.Assert(Decode(nil, Employee{}), Equals, ErrInvalidTarget)
+	c.Assert(Decode(nil, (*Employee)(nil)), Equals, ErrInvalidTarget)
+
+	var s1 struct {
+		Data []string `prop:"Data"`
+	}
+
+	err := Decode(nil, &s1)
+	c.Assert(err, ErrorMatches, `field Data has unsupported type \[\]string`)
+
+	var s2 struct {
+		Data string `prop:",omitempty"`
+	}
+
+	err = Decode(nil, &s2)
+	c.Assert(err, ErrorMatches, `field Data has empty property name`)
+
+	props := pachca.Properties{
+		{ID: 10, Type: pachca.PROP_TYPE_TEXT, Name: "Birthday", Value: "Tomorrow"},
+		{ID: 11, Type: pachca.PROP_TYPE_NUMBER, Name: "Level", Value: "high"},
+		{ID: 12, Type: pachca.PROP_TYPE_TEXT, Name: "Office", Value: "Moscow"},
+		{ID: 13, Type: pachca.PROP_TYPE_TEXT, Name: "Rating", Value: "5"},
+		{ID: 14, Type: pachca.PROP_TYPE_TEXT, Name: "Profile", Value: "https://kaos.sh/john"},
+	}
+
+	e := &Employee{}
+	err = Decode(props, e)
+
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, `(?s).*can't decode property "Birthday" into field Birthday: invalid property type for date \(text\).*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't decode property "Level" into field Level: .*invalid syntax.*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't decode property "Rating" into field Rating: invalid property type for number \(text\).*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't decode property "Profile" into field Profile: invalid property type for link \(text\).*`)
+	c.Assert(e.Office, Equals, "Moscow")
+
+	var s3 struct {
+		Flag  bool   `prop:"Flag"`
+		Count uint8  `prop:"Count"`
+		Score uint16 `prop:"Score"`
+	}
+
+	props = pachca.Properties{
+		{ID: 1, Type: pachca.PROP_TYPE_TEXT, Name: "Flag", Value: "maybe"},
+		{ID: 2, Type: pachca.PROP_TYPE_NUMBER, Name: "Count", Value: "1000"},
+		{ID: 3, Type: pachca.PROP_TYPE_TEXT, Name: "Score", Value: "1"},
+	}
+
+	err = Decode(props, &s3)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, `(?s).*field Flag: .*invalid syntax.*`)
+	c.Assert(err.Error(), Matches, `(?s).*field Count: .*out of range.*`)
+	c.Assert(err.Error(), Matches, `(?s).*field Score: invalid property type for number \(text\).*`)
+}
+
+func (s *PropsSuite) TestEncode(c *C) {
+	defs := pachca.Properties{
+		{ID: 10, Type: pachca.PROP_TYPE_DATE, Name: "Birthday"},
+		{ID: 11, Type: pachca.PROP_TYPE_NUMBER, Name: "Level"},
+		{ID: 12, Type: pachca.PROP_TYPE_TEXT, Name: "Office"},
+		{ID: 13, Type: pachca.PROP_TYPE_NUMBER, Name: "Rating"},
+		{ID: 14, Type: pachca.PROP_TYPE_LINK, Name: "Profile"},
+		{ID: 15, Type: pachca.PROP_TYPE_LINK, Name: "Blog"},
+	}
+
+	rating := 4.0
+	profile, _ := url.Parse("https://kaos.sh/john")
+
+	e := Employee{
+		Contacts: Contacts{Office: "Moscow"},
+		Birthday: time.Date(1990, 5, 17, 12, 0, 0, 0, time.UTC),
+		Level:    3,
+		Rating:   &rating,
+		Profile:  profile,
+	}
+
+	reqs, err := Encode(e, defs)
+
+	c.Assert(err, IsNil)
+	c.Assert(reqs, DeepEquals, pachca.PropertyRequests{
+		{ID: 12, Value: "Moscow"},
+		{ID: 10, Value: "1990-05-17T12:00:00Z"},
+		{ID: 11, Value: "3"},
+		{ID: 13, Value: "4"},
+		{ID: 14, Value: "https://kaos.sh/john"},
+	})
+
+	reqs, err = Encode(&Employee{}, defs)
+
+	c.Assert(err, IsNil)
+	c.Assert(reqs, DeepEquals, pachca.PropertyRequests{
+		{ID: 12, Value: ""},
+		{ID: 10, Value: ""},
+		{ID: 11, Value: "0"},
+	})
+}
+
+func (s *PropsSuite) TestEncodeErrors(c *C) {
+	_, err := Encode(nil, nil)
+	c.Assert(err, Equals, ErrInvalidSource)
+
+	_, err = Encode((*Employee)(nil), nil)
+	c.Assert(err, Equals, ErrInvalidSource)
+
+	_, err = Encode("test", nil)
+	c.Assert(err, Equals, ErrInvalidSource)
+
+	defs := pachca.Properties{
+		{ID: 10, Type: pachca.PROP_TYPE_TEXT, Name: "Birthday"},
+		{ID: 11, Type: pachca.PROP_TYPE_TEXT, Name: "Level"},
+		{ID: 13, Type: pachca.PROP_TYPE_NUMBER, Name: "Rating"},
+		{ID: 14, Type: pachca.PROP_TYPE_NUMBER, Name: "Profile"},
+	}
+
+	rating := 4.5
+	profile, _ := url.Parse("https://kaos.sh/john")
+
+	_, err = Encode(&Employee{Rating: &rating, Profile: profile}, defs)
+
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Contacts.Office: unknown property with ID 12.*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Birthday into property "Birthday": invalid property type for date \(text\).*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Level into property "Level": invalid property type for number \(text\).*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Rating into property "Rating": value 4.5 isn't an integer.*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Profile into property "Profile": invalid property type for link \(number\).*`)
+	c.Assert(err.Error(), Matches, `(?s).*can't encode field Blog: unknown property "Blog".*`)
+}
+
+func (s *PropsSuite) TestValidate(c *C) {
+	defs := pachca.Properties{
+		nil,
+		{ID: 10, Type: pachca.PROP_TYPE_DATE, Name: "Birthday"},
+		{ID: 11, Type: pachca.PROP_TYPE_NUMBER, Name: "Level"},
+		{ID: 12, Type: pachca.PROP_TYPE_TEXT, Name: "Office"},
+		{ID: 14, Type: pachca.PROP_TYPE_LINK, Name: "Profile"},
+	}
+
+	c.Assert(Validate(nil, defs), IsNil)
+	c.Assert(ValidateUser(nil, defs), IsNil)
+
+	c.Assert(ValidateUser(&pachca.UserRequest{
+		Properties: pachca.PropertyRequests{
+			pachca.NewPropertyRequest(10, time.Date(1990, 5, 17, 12, 0, 0, 0, time.UTC)),
+			pachca.NewPropertyRequest(11, -3),
+			pachca.NewPropertyRequest(12, "Moscow"),
+			pachca.NewPropertyRequest(14, "https://kaos.sh/john"),
+		},
+	}, defs), IsNil)
+
+	c.Assert(Validate(pachca.PropertyRequests{
+		{ID: 10, Value: ""}, {ID: 11, Value: ""}, {ID: 14, Value: ""},
+	}, defs), IsNil)
+
+	err := Validate(pachca.PropertyRequests{
+		nil,
+		{ID: 10, Value: "17.05.1990"},
+		{ID: 11, Value: "4.5"},
+		{ID: 12, Value: "Moscow"},
+		{ID: 12, Value: "London"},
+		{ID: 13, Value: "test"},
+		{ID: 14, Value: "kaos.sh/john"},
+	}, defs)
+
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, ErrNilRequest), Equals, true)
+	c.Assert(errors.Is(err, ErrInvalidDate), Equals, true)
+	c.Assert(errors.Is(err, ErrInvalidNumber), Equals, true)
+	c.Assert(errors.Is(err, ErrDuplicateProperty), Equals, true)
+	c.Assert(errors.Is(err, ErrUnknownProperty), Equals, true)
+	c.Assert(errors.Is(err, ErrInvalidLink), Equals, true)
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+
+	c.Assert(errs, HasLen, 6)
+	c.Assert(errs[1].Error(), Equals, `property "Birthday" (10): invalid date value "17.05.1990": expected format is 2006-01-02T15:04:05.999Z`)
+	c.Assert(errs[2].Error(), Equals, `property "Level" (11): invalid number value "4.5": value must be an integer`)
+	c.Assert(errs[3].Error(), Equals, `property "Office" (12): duplicate property`)
+	c.Assert(errs[4].Error(), Equals, `property 13: unknown property`)
+	c.Assert(errs[5].Error(), Equals, `property "Profile" (14): invalid link value "kaos.sh/john": value must be an absolute HTTP(S) URL`)
+
+	var propErr *PropertyError
+
+	c.Assert(errors.As(err, &propErr), Equals, true)
+	c.Assert(propErr.Err, Equals, ErrNilRequest)
+
+	// Fractional numbers must not be truncated on request creation
+	err = Validate(pachca.PropertyRequests{pachca.NewPropertyRequest(11, 2.7)}, defs)
+	c.Assert(err, ErrorMatches, `property "Level" \(11\): invalid number value "2.7": value must be an integer`)
+}
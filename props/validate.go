@@ -0,0 +1,149 @@
+package props
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DATE_LAYOUT is layout of date property values
+const DATE_LAYOUT = "2006-01-02T15:04:05.999Z"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// PropertyError is validation error of single property request
+type PropertyError struct {
+	ID   uint   // Property ID
+	Name string // Property name (empty for unknown properties)
+	Err  error  // Validation error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilRequest        = errors.New("property request is nil")
+	ErrUnknownProperty   = errors.New("unknown property")
+	ErrDuplicateProperty = errors.New("duplicate property")
+	ErrInvalidDate       = errors.New("invalid date value")
+	ErrInvalidNumber     = errors.New("invalid number value")
+	ErrInvalidLink       = errors.New("invalid link value")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ValidateUser validates custom properties of user request
+func ValidateUser(user *pachca.UserRequest, defs pachca.Properties) error {
+	if user == nil {
+		return nil
+	}
+
+	return Validate(user.Properties, defs)
+}
+
+// Validate validates property requests against property definitions (result
+// of GetProperties). Returned error wraps *PropertyError for every invalid
+// request and can be inspected using errors.As. Empty values are always valid
+// because they clear properties.
+func Validate(reqs pachca.PropertyRequests, defs pachca.Properties) error {
+	errs := errors.NewBundle()
+	seen := make(map[uint]bool, len(reqs))
+	index := make(map[uint]*pachca.Property, len(defs))
+
+	for _, p := range defs {
+		if p != nil {
+			index[p.ID] = p
+		}
+	}
+
+	for _, r := range reqs {
+		if r == nil {
+			errs.Add(&PropertyError{Err: ErrNilRequest})
+			continue
+		}
+
+		def := index[r.ID]
+
+		switch {
+		case def == nil:
+			errs.Add(&PropertyError{ID: r.ID, Err: ErrUnknownProperty})
+			continue
+		case seen[r.ID]:
+			errs.Add(&PropertyError{ID: r.ID, Name: def.Name, Err: ErrDuplicateProperty})
+			continue
+		}
+
+		seen[r.ID] = true
+
+		err := validateValue(def.Type, r.Value)
+
+		if err != nil {
+			errs.Add(&PropertyError{ID: r.ID, Name: def.Name, Err: err})
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Error returns error message
+func (e *PropertyError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("property %d: %v", e.ID, e.Err)
+	}
+
+	return fmt.Sprintf("property %q (%d): %v", e.Name, e.ID, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e *PropertyError) Unwrap() error {
+	return e.Err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validateValue validates property value using property type
+func validateValue(typ pachca.PropertyType, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch typ {
+	case pachca.PROP_TYPE_DATE:
+		if _, err := time.Parse(DATE_LAYOUT, value); err != nil {
+			return fmt.Errorf("%w %q: expected format is %s", ErrInvalidDate, value, DATE_LAYOUT)
+		}
+
+	case pachca.PROP_TYPE_NUMBER:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%w %q: value must be an integer", ErrInvalidNumber, value)
+		}
+
+	case pachca.PROP_TYPE_LINK:
+		u, err := url.Parse(value)
+
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w %q: value must be an absolute HTTP(S) URL", ErrInvalidLink, value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,309 @@
+package query
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Predicate is function which reports whether item matches the condition
+type Predicate[T any] func(item T) bool
+
+// User is user predicate
+type User = Predicate[*pachca.User]
+
+// Chat is chat predicate
+type Chat = Predicate[*pachca.Chat]
+
+// Tag is tag predicate
+type Tag = Predicate[*pachca.Tag]
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// And returns predicate which matches items matching all given predicates
+func And[T any](preds ...Predicate[T]) Predicate[T] {
+	return func(item T) bool {
+		for _, p := range preds {
+			if p != nil && !p(item) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns predicate which matches items matching at least one of given
+// predicates
+func Or[T any](preds ...Predicate[T]) Predicate[T] {
+	return func(item T) bool {
+		for _, p := range preds {
+			if p != nil && p(item) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns predicate which matches items not matching given predicate
+func Not[T any](pred Predicate[T]) Predicate[T] {
+	return func(item T) bool {
+		return pred == nil || !pred(item)
+	}
+}
+
+// Filter returns iterator over items from pages which match given predicate.
+// It can be used with paginators:
+//
+//	users := client.PaginateUsers(50)
+//
+//	for user := range query.Filter(users.Pages, query.Active()) {
+//	  fmt.Println(user.FullName())
+//	}
+func Filter[S ~[]T, T any](pages iter.Seq[S], pred Predicate[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if pages == nil {
+			return
+		}
+
+		for page := range pages {
+			for _, item := range page {
+				if pred != nil && !pred(item) {
+					continue
+				}
+
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Active matches active users
+func Active() User {
+	return func(u *pachca.User) bool { return u.IsActive() }
+}
+
+// Suspended matches suspended users
+func Suspended() User {
+	return func(u *pachca.User) bool { return u != nil && u.IsSuspended }
+}
+
+// Bot matches bots
+func Bot() User {
+	return func(u *pachca.User) bool { return u != nil && u.IsBot }
+}
+
+// Guest matches guests and multi-guests
+func Guest() User {
+	return func(u *pachca.User) bool { return u.IsGuest() }
+}
+
+// Role matches users with any of given roles
+func Role(roles ...pachca.UserRole) User {
+	return func(u *pachca.User) bool {
+		return u != nil && slices.Contains(roles, u.Role)
+	}
+}
+
+// InviteStatus matches users with any of given invite statuses
+func InviteStatus(statuses ...pachca.InviteStatus) User {
+	return func(u *pachca.User) bool {
+		return u != nil && slices.Contains(statuses, u.InviteStatus)
+	}
+}
+
+// Department matches users from any of given departments (case-insensitive)
+func Department(names ...string) User {
+	return func(u *pachca.User) bool {
+		return u != nil && containsFold(names, u.Department)
+	}
+}
+
+// Title matches users with any of given titles (case-insensitive)
+func Title(titles ...string) User {
+	return func(u *pachca.User) bool {
+		return u != nil && containsFold(titles, u.Title)
+	}
+}
+
+// HasTag matches users with given tag
+func HasTag(tag string) User {
+	return func(u *pachca.User) bool { return u.HasTag(tag) }
+}
+
+// AnyTag matches users with at least one of given tags
+func AnyTag(tags ...string) User {
+	return func(u *pachca.User) bool {
+		return u != nil && slices.ContainsFunc(tags, u.HasTag)
+	}
+}
+
+// Property matches users with custom property (found by name) equal to given
+// value (case-insensitive)
+func Property(name, value string) User {
+	return func(u *pachca.User) bool {
+		return u != nil && strings.EqualFold(u.Properties.Find(name).String(), value)
+	}
+}
+
+// HasProperty matches users with non-empty custom property (found by name)
+func HasProperty(name string) User {
+	return func(u *pachca.User) bool {
+		return u != nil && u.Properties.Find(name).IsSet()
+	}
+}
+
+// CreatedAfter matches users created after given moment
+func CreatedAfter(t time.Time) User {
+	return func(u *pachca.User) bool {
+		return u != nil && u.CreatedAt.After(t)
+	}
+}
+
+// CreatedBefore matches users created before given moment
+func CreatedBefore(t time.Time) User {
+	return func(u *pachca.User) bool {
+		return u != nil && !u.CreatedAt.IsZero() && u.CreatedAt.Before(t)
+	}
+}
+
+// ActiveAfter matches users with last activity after given moment
+func ActiveAfter(t time.Time) User {
+	return func(u *pachca.User) bool {
+		return u != nil && u.LastActivityAt.After(t)
+	}
+}
+
+// ActiveBefore matches users with last activity before given moment. Users
+// without any activity also match.
+func ActiveBefore(t time.Time) User {
+	return func(u *pachca.User) bool {
+		return u != nil && u.LastActivityAt.Before(t)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Public matches public chats
+func Public() Chat {
+	return func(c *pachca.Chat) bool { return c != nil && c.IsPublic }
+}
+
+// Channel matches channels
+func Channel() Chat {
+	return func(c *pachca.Chat) bool { return c != nil && c.IsChannel }
+}
+
+// Personal matches p2p chats
+func Personal() Chat {
+	return func(c *pachca.Chat) bool { return c != nil && c.Name == "" }
+}
+
+// ChatName matches chats with name containing given substring (case-insensitive)
+func ChatName(substr string) Chat {
+	substr = strings.ToLower(substr)
+
+	return func(c *pachca.Chat) bool {
+		return c != nil && strings.Contains(strings.ToLower(c.Name), substr)
+	}
+}
+
+// OwnedBy matches chats owned by given user
+func OwnedBy(userID uint) Chat {
+	return func(c *pachca.Chat) bool { return c != nil && c.OwnerID == userID }
+}
+
+// HasMember matches chats with given member
+func HasMember(userID uint) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && slices.Contains(c.Members, userID)
+	}
+}
+
+// HasGroupTag matches chats with given group tag
+func HasGroupTag(tagID uint) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && slices.Contains(c.GroupTags, tagID)
+	}
+}
+
+// ChatCreatedAfter matches chats created after given moment
+func ChatCreatedAfter(t time.Time) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && c.CreatedAt.After(t)
+	}
+}
+
+// ChatCreatedBefore matches chats created before given moment
+func ChatCreatedBefore(t time.Time) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && !c.CreatedAt.IsZero() && c.CreatedAt.Before(t)
+	}
+}
+
+// LastMessageAfter matches chats with last message after given moment
+func LastMessageAfter(t time.Time) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && c.LastMessageAt.After(t)
+	}
+}
+
+// LastMessageBefore matches chats with last message before given moment. Chats
+// without messages also match.
+func LastMessageBefore(t time.Time) Chat {
+	return func(c *pachca.Chat) bool {
+		return c != nil && c.LastMessageAt.Before(t)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TagName matches tags with name containing given substring (case-insensitive)
+func TagName(substr string) Tag {
+	substr = strings.ToLower(substr)
+
+	return func(t *pachca.Tag) bool {
+		return t != nil && strings.Contains(strings.ToLower(t.Name), substr)
+	}
+}
+
+// MinUsers matches tags with at least given number of users
+func MinUsers(count int) Tag {
+	return func(t *pachca.Tag) bool { return t != nil && t.UsersCount >= count }
+}
+
+// MaxUsers matches tags with at most given number of users
+func MaxUsers(count int) Tag {
+	return func(t *pachca.Tag) bool { return t != nil && t.UsersCount <= count }
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// containsFold returns true if slice contains given string (case-insensitive)
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
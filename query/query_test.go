@@ -0,0 +1,230 @@
+package query_test
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/query"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type QuerySuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&QuerySuite{})
+
+var (
+	d2024 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d2025 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d2026 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+var users = pachca.Users{
+	{
+		ID: 1, Role: pachca.ROLE_ADMIN, InviteStatus: pachca.INVITE_CONFIRMED,
+		Department: "Engineering", Title: "CTO", Tags: []string{"dev", "lead"},
+		CreatedAt:      pachca.Date{Time: d2024},
+		LastActivityAt: pachca.Date{Time: d2026},
+		Properties: pachca.Properties{
+			{ID: 1, Type: pachca.PROP_TYPE_TEXT, Name: "Office", Value: "Moscow"},
+		},
+	},
+	{
+		ID: 2, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+		Department: "engineering", Title: "Developer", Tags: []string{"dev"},
+		CreatedAt:      pachca.Date{Time: d2025.AddDate(0, 6, 0)},
+		LastActivityAt: pachca.Date{Time: d2025},
+		Properties: pachca.Properties{
+			{ID: 1, Type: pachca.PROP_TYPE_TEXT, Name: "Office", Value: "London"},
+		},
+	},
+	{
+		ID: 3, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+		Department: "Engineering", Title: "Developer", Tags: []string{"dev", "contractor"},
+		CreatedAt: pachca.Date{Time: d2026},
+	},
+	{
+		ID: 4, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+		Department: "Engineering", IsBot: true,
+		CreatedAt: pachca.Date{Time: d2026},
+	},
+	{
+		ID: 5, Role: pachca.ROLE_GUEST, InviteStatus: pachca.INVITE_SENT,
+		Department: "Sales", Title: "Manager",
+		CreatedAt: pachca.Date{Time: d2026},
+	},
+	{
+		ID: 6, Role: pachca.ROLE_REGULAR, InviteStatus: pachca.INVITE_CONFIRMED,
+		Department: "Engineering", IsSuspended: true,
+		CreatedAt: pachca.Date{Time: d2026},
+	},
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *QuerySuite) TestCombinators(c *C) {
+	c.Assert(ids(users.Filter(query.And[*pachca.User]())), DeepEquals, []uint{1, 2, 3, 4, 5, 6})
+	c.Assert(ids(users.Filter(query.Or[*pachca.User]())), HasLen, 0)
+	c.Assert(ids(users.Filter(query.Not[*pachca.User](nil))), DeepEquals, []uint{1, 2, 3, 4, 5, 6})
+
+	q := query.And(
+		query.Active(),
+		query.Not(query.Bot()),
+		query.Department("ENGINEERING"),
+		query.CreatedAfter(d2025),
+		query.Not(query.HasTag("contractor")),
+		nil,
+	)
+
+	c.Assert(ids(users.Filter(q)), DeepEquals, []uint{2})
+
+	q = query.Or(query.Role(pachca.ROLE_ADMIN), query.Title("manager"), nil)
+	c.Assert(ids(users.Filter(q)), DeepEquals, []uint{1, 5})
+}
+
+func (s *QuerySuite) TestUserPredicates(c *C) {
+	c.Assert(ids(users.Filter(query.Active())), DeepEquals, []uint{1, 2, 3, 4})
+	c.Assert(ids(users.Filter(query.Suspended())), DeepEquals, []uint{6})
+	c.Assert(ids(users.Filter(query.Bot())), DeepEquals, []uint{4})
+	c.Assert(ids(users.Filter(query.Guest())), DeepEquals, []uint{5})
+	c.Assert(ids(users.Filter(query.Role(pachca.ROLE_GUEST, pachca.ROLE_ADMIN))), DeepEquals, []uint{1, 5})
+	c.Assert(ids(users.Filter(query.InviteStatus(pachca.INVITE_SENT))), DeepEquals, []uint{5})
+	c.Assert(ids(users.Filter(query.Department("sales"))), DeepEquals, []uint{5})
+	c.Assert(ids(users.Filter(query.Title("cto", "manager"))), DeepEquals, []uint{1, 5})
+	c.Assert(ids(users.Filter(query.HasTag("lead"))), DeepEquals, []uint{1})
+	c.Assert(ids(users.Filter(query.AnyTag("lead", "contractor"))), DeepEquals, []uint{1, 3})
+	c.Assert(ids(users.Filter(query.Property("office", "LONDON"))), DeepEquals, []uint{2})
+	c.Assert(ids(users.Filter(query.HasProperty("Office"))), DeepEquals, []uint{1, 2})
+	c.Assert(ids(users.Filter(query.CreatedAfter(d2025))), DeepEquals, []uint{2, 3, 4, 5, 6})
+	c.Assert(ids(users.Filter(query.CreatedBefore(d2025))), DeepEquals, []uint{1})
+	c.Assert(ids(users.Filter(query.ActiveAfter(d2025))), DeepEquals, []uint{1})
+	c.Assert(ids(users.Filter(query.ActiveBefore(d2026))), DeepEquals, []uint{2, 3, 4, 5, 6})
+
+	for _, p := range []query.User{
+		query.Active(), query.Suspended(), query.Bot(), query.Guest(), query.Role(pachca.ROLE_ADMIN),
+		query.InviteStatus(pachca.INVITE_SENT), query.Department(""), query.Title(""),
+		query.HasTag(""), query.AnyTag(""), query.Property("", ""), query.HasProperty(""),
+		query.CreatedAfter(d2025), query.CreatedBefore(d2025), query.ActiveAfter(d2025),
+		query.ActiveBefore(d2025),
+	} {
+		c.Assert(p(nil), Equals, false)
+	}
+}
+
+func (s *QuerySuite) TestChatPredicates(c *C) {
+	chats := pachca.Chats{
+		{ID: 1, Name: "Backend", OwnerID: 1, Members: []uint{1, 2}, GroupTags: []uint{10}, CreatedAt: pachca.Date{Time: d2024}, LastMessageAt: pachca.Date{Time: d2026}},
+		{ID: 2, Name: "News", OwnerID: 2, IsPublic: true, IsChannel: true, CreatedAt: pachca.Date{Time: d2026}},
+		{ID: 3, OwnerID: 1, Members: []uint{1, 3}, CreatedAt: pachca.Date{Time: d2025}, LastMessageAt: pachca.Date{Time: d2025}},
+	}
+
+	c.Assert(chatIDs(chats.Filter(query.Public())), DeepEquals, []uint{2})
+	c.Assert(chatIDs(chats.Filter(query.Channel())), DeepEquals, []uint{2})
+	c.Assert(chatIDs(chats.Filter(query.Personal())), DeepEquals, []uint{3})
+	c.Assert(chatIDs(chats.Filter(query.ChatName("END"))), DeepEquals, []uint{1})
+	c.Assert(chatIDs(chats.Filter(query.OwnedBy(1))), DeepEquals, []uint{1, 3})
+	c.Assert(chatIDs(chats.Filter(query.HasMember(3))), DeepEquals, []uint{3})
+	c.Assert(chatIDs(chats.Filter(query.HasGroupTag(10))), DeepEquals, []uint{1})
+	c.Assert(chatIDs(chats.Filter(query.ChatCreatedAfter(d2024))), DeepEquals, []uint{2, 3})
+	c.Assert(chatIDs(chats.Filter(query.ChatCreatedBefore(d2025))), DeepEquals, []uint{1})
+	c.Assert(chatIDs(chats.Filter(query.LastMessageAfter(d2025))), DeepEquals, []uint{1})
+	c.Assert(chatIDs(chats.Filter(query.LastMessageBefore(d2026))), DeepEquals, []uint{2, 3})
+	c.Assert(chatIDs(chats.Filter(query.And(query.Not(query.Personal()), query.Not(query.Public())))), DeepEquals, []uint{1})
+
+	for _, p := range []query.Chat{
+		query.Public(), query.Channel(), query.Personal(), query.ChatName(""), query.OwnedBy(0), query.HasMember(0),
+		query.HasGroupTag(0), query.ChatCreatedAfter(d2025), query.ChatCreatedBefore(d2025),
+		query.LastMessageAfter(d2025), query.LastMessageBefore(d2025),
+	} {
+		c.Assert(p(nil), Equals, false)
+	}
+}
+
+func (s *QuerySuite) TestTagPredicates(c *C) {
+	tags := pachca.Tags{
+		{ID: 1, Name: "Developers", UsersCount: 10},
+		{ID: 2, Name: "Designers", UsersCount: 3},
+		{ID: 3, Name: "Managers", UsersCount: 0},
+	}
+
+	c.Assert(tags.Filter(query.TagName("DE")).Names(), DeepEquals, []string{"Developers", "Designers"})
+	c.Assert(tags.Filter(query.MinUsers(3)).Names(), DeepEquals, []string{"Developers", "Designers"})
+	c.Assert(tags.Filter(query.MaxUsers(3)).Names(), DeepEquals, []string{"Designers", "Managers"})
+	c.Assert(tags.Filter(query.Or(query.MinUsers(5), query.TagName("man"))).Names(), DeepEquals, []string{"Developers", "Managers"})
+
+	for _, p := range []query.Tag{query.TagName(""), query.MinUsers(0), query.MaxUsers(0)} {
+		c.Assert(p(nil), Equals, false)
+	}
+}
+
+func (s *QuerySuite) TestFilter(c *C) {
+	pages := func(yield func(pachca.Users) bool) {
+		if !yield(users[:3]) {
+			return
+		}
+
+		yield(users[3:])
+	}
+
+	var result []uint
+
+	for u := range query.Filter(pages, query.And(query.Active(), query.Not(query.Bot()))) {
+		result = append(result, u.ID)
+	}
+
+	c.Assert(result, DeepEquals, []uint{1, 2, 3})
+
+	result = nil
+
+	for u := range query.Filter(pages, nil) {
+		result = append(result, u.ID)
+
+		if len(result) == 4 {
+			break
+		}
+	}
+
+	c.Assert(result, DeepEquals, []uint{1, 2, 3, 4})
+
+	paginator := (&pachca.Client{}).PaginateUsers(10)
+
+	c.Assert(slices.Collect(query.Filter(paginator.Pages, query.Active())), HasLen, 0)
+	c.Assert(slices.Collect(query.Filter[pachca.Users](nil, query.Active())), HasLen, 0)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func ids(users pachca.Users) []uint {
+	var result []uint
+
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+
+	return result
+}
+
+func chatIDs(chats pachca.Chats) []uint {
+	var result []uint
+
+	for _, c := range chats {
+		result = append(result, c.ID)
+	}
+
+	return result
+}
@@ -0,0 +1,400 @@
+package receipts
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	MODE_DIRECT Mode = iota // Send direct message to every user
+	MODE_THREAD             // Mention all users in message thread
+)
+
+const (
+	DEFAULT_DIRECT_TEXT = "Please read this important message: {url}"
+	DEFAULT_THREAD_TEXT = "{mentions} please read this message"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used for tracking receipts
+type Client interface {
+	GetMessage(messageID uint) (*pachca.Message, error)
+	GetMessageReads(messageID uint) ([]uint, error)
+	GetChatUsers(chatID uint, memberRole pachca.ChatRole) (pachca.Users, error)
+	SendMessageToUser(userID uint, text string) (*pachca.Message, error)
+	AddThreadMessageText(messageID uint, text string) (*pachca.Thread, *pachca.Message, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Mode is escalation mode
+type Mode uint8
+
+// Receipt contains info about message readers
+type Receipt struct {
+	Message *pachca.Message
+	Read    pachca.Users
+	Unread  pachca.Users
+}
+
+// Step is escalation step
+type Step struct {
+	// Delay is delay since the start of tracking
+	Delay time.Duration
+
+	// Mode is notification mode
+	Mode Mode
+
+	// Text is notification text. Text can contain {url} (message URL) and
+	// {mentions} (mentions of users who haven't read the message) placeholders.
+	Text string
+}
+
+// Runner periodically checks tracked messages and notifies users who haven't
+// read them
+type Runner struct {
+	// OnError is callback for errors which occurred during background checks
+	OnError func(messageID uint, err error)
+
+	client Client
+
+	mu      sync.Mutex
+	checkMu sync.Mutex
+	tracked map[uint]*tracking
+}
+
+// tracking contains escalation state for message
+type tracking struct {
+	start    time.Time
+	steps    []Step
+	index    int
+	notified map[uint]bool // Users who received direct message on current step
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient        = errors.New("client is nil")
+	ErrNilRunner        = errors.New("runner is nil")
+	ErrNoSteps          = errors.New("escalation must have at least one step")
+	ErrInvalidMessageID = errors.New("message ID must be greater than 0")
+	ErrInvalidInterval  = errors.New("check interval must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// GetReceipt returns info about chat members who have and haven't read the
+// message. Message author, bots and inactive users are ignored.
+func GetReceipt(client Client, messageID uint) (*Receipt, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case messageID == 0:
+		return nil, ErrInvalidMessageID
+	}
+
+	msg, err := client.GetMessage(messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reads, err := client.GetMessageReads(messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chatID := msg.ChatID
+
+	// Members of thread are members of the root chat
+	if msg.RootChatID != 0 {
+		chatID = msg.RootChatID
+	}
+
+	members, err := client.GetChatUsers(chatID, pachca.CHAT_ROLE_ANY)
+
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{Message: msg}
+
+	for _, user := range members.Active().People() {
+		switch {
+		case user.ID == msg.UserID:
+			continue
+		case slices.Contains(reads, user.ID):
+			receipt.Read = append(receipt.Read, user)
+		default:
+			receipt.Unread = append(receipt.Unread, user)
+		}
+	}
+
+	return receipt, nil
+}
+
+// Unread returns chat members who haven't read the message
+func Unread(client Client, messageID uint) (pachca.Users, error) {
+	receipt, err := GetReceipt(client, messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.Unread, nil
+}
+
+// Notify notifies users who haven't read the message using given mode
+func Notify(client Client, receipt *Receipt, mode Mode, text string) error {
+	return notify(client, receipt, mode, text, nil)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewRunner creates new escalation runner
+func NewRunner(client Client) (*Runner, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Runner{client: client, tracked: map[uint]*tracking{}}, nil
+}
+
+// Track starts tracking of message with given escalation steps
+func (r *Runner) Track(messageID uint, steps ...Step) error {
+	switch {
+	case r == nil:
+		return ErrNilRunner
+	case messageID == 0:
+		return ErrInvalidMessageID
+	case len(steps) == 0:
+		return ErrNoSteps
+	}
+
+	steps = slices.Clone(steps)
+
+	slices.SortStableFunc(steps, func(s1, s2 Step) int {
+		return cmp.Compare(s1.Delay, s2.Delay)
+	})
+
+	r.mu.Lock()
+	r.tracked[messageID] = &tracking{start: time.Now(), steps: steps}
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Untrack stops tracking of message
+func (r *Runner) Untrack(messageID uint) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	delete(r.tracked, messageID)
+	r.mu.Unlock()
+}
+
+// Tracked returns IDs of tracked messages
+func (r *Runner) Tracked() []uint {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]uint, 0, len(r.tracked))
+
+	for id := range r.tracked {
+		result = append(result, id)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// Check executes all escalation steps which are due at given time. Message is
+// no longer tracked if all users have read it or all steps are executed.
+func (r *Runner) Check(now time.Time) error {
+	if r == nil {
+		return ErrNilRunner
+	}
+
+	r.checkMu.Lock()
+	defer r.checkMu.Unlock()
+
+	errs := errors.NewBundle()
+
+	for _, messageID := range r.Tracked() {
+		err := r.checkMessage(messageID, now)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't check message %d: %w", messageID, err))
+
+			if r.OnError != nil {
+				r.OnError(messageID, err)
+			}
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// Run periodically checks tracked messages until stop channel is closed
+func (r *Runner) Run(interval time.Duration, stop <-chan struct{}) error {
+	switch {
+	case r == nil:
+		return ErrNilRunner
+	case interval <= 0:
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			r.Check(now)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkMessage executes due escalation steps for message
+func (r *Runner) checkMessage(messageID uint, now time.Time) error {
+	r.mu.Lock()
+	t := r.tracked[messageID]
+	r.mu.Unlock()
+
+	if t == nil || now.Sub(t.start) < t.steps[t.index].Delay {
+		return nil
+	}
+
+	receipt, err := GetReceipt(r.client, messageID)
+
+	if err != nil {
+		return err
+	}
+
+	if len(receipt.Unread) == 0 {
+		r.Untrack(messageID)
+		return nil
+	}
+
+	// If several steps are due, only the latest one is executed
+	for t.index < len(t.steps)-1 && now.Sub(t.start) >= t.steps[t.index+1].Delay {
+		t.index++
+		t.notified = nil
+	}
+
+	if t.notified == nil {
+		t.notified = map[uint]bool{}
+	}
+
+	// Users who were notified before failure are skipped on retry
+	step := t.steps[t.index]
+	err = notify(r.client, receipt, step.Mode, step.Text, t.notified)
+
+	if err != nil {
+		return err
+	}
+
+	t.index++
+	t.notified = nil
+
+	if t.index >= len(t.steps) {
+		r.Untrack(messageID)
+	}
+
+	return nil
+}
+
+// notify notifies users who haven't read the message using given mode. Users
+// from notified map are skipped in direct mode, and users who received direct
+// message are added to it.
+func notify(client Client, receipt *Receipt, mode Mode, text string, notified map[uint]bool) error {
+	switch {
+	case client == nil:
+		return ErrNilClient
+	case receipt == nil || receipt.Message == nil || len(receipt.Unread) == 0:
+		return nil
+	}
+
+	if text == "" {
+		text = DEFAULT_DIRECT_TEXT
+
+		if mode == MODE_THREAD {
+			text = DEFAULT_THREAD_TEXT
+		}
+	}
+
+	mentions := make([]string, 0, len(receipt.Unread))
+
+	for _, user := range receipt.Unread {
+		mentions = append(mentions, user.Mention())
+	}
+
+	text = strings.NewReplacer(
+		"{url}", receipt.Message.URL(),
+		"{mentions}", strings.Join(mentions, " "),
+	).Replace(text)
+
+	if mode == MODE_THREAD {
+		_, _, err := client.AddThreadMessageText(receipt.Message.ID, text)
+		return err
+	}
+
+	errs := errors.NewBundle()
+
+	for _, user := range receipt.Unread {
+		if notified[user.ID] {
+			continue
+		}
+
+		_, err := client.SendMessageToUser(user.ID, text)
+
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+
+		if notified != nil {
+			notified[user.ID] = true
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package receipts
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ReceiptsSuite struct{}
+
+type fakeClient struct {
+	mu      sync.Mutex
+	reads   []uint
+	direct  map[uint][]string
+	thread  []string
+	failDM  bool
+	failGet bool
+	failTo  uint
+	chatIDs []uint
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ReceiptsSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ReceiptsSuite) TestReceipt(c *C) {
+	client := newFakeClient()
+
+	r, err := GetReceipt(client, 1)
+	c.Assert(err, IsNil)
+	c.Assert(r.Message.ID, Equals, uint(1))
+	c.Assert(userIDs(r.Read), DeepEquals, []uint{2})
+	c.Assert(userIDs(r.Unread), DeepEquals, []uint{3, 4})
+	c.Assert(client.chatIDs, DeepEquals, []uint{10})
+
+	users, err := Unread(client, 2)
+	c.Assert(err, IsNil)
+	c.Assert(userIDs(users), DeepEquals, []uint{3, 4})
+	c.Assert(client.chatIDs, DeepEquals, []uint{10, 10})
+
+	_, err = GetReceipt(nil, 1)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = GetReceipt(client, 0)
+	c.Assert(err, Equals, ErrInvalidMessageID)
+	_, err = Unread(client, 0)
+	c.Assert(err, Equals, ErrInvalidMessageID)
+
+	client.failGet = true
+	_, err = GetReceipt(client, 1)
+	c.Assert(err, NotNil)
+}
+
+func (s *ReceiptsSuite) TestNotify(c *C) {
+	client := newFakeClient()
+	r, _ := GetReceipt(client, 1)
+
+	c.Assert(Notify(client, r, MODE_DIRECT, ""), IsNil)
+	c.Assert(client.direct[3], DeepEquals, []string{
+		"Please read this important message: https://app.pachca.com/chats/10?message=1",
+	})
+	c.Assert(client.direct[4], HasLen, 1)
+
+	c.Assert(Notify(client, r, MODE_THREAD, ""), IsNil)
+	c.Assert(client.thread, DeepEquals, []string{"<@3> <@4> please read this message"})
+
+	c.Assert(Notify(client, r, MODE_THREAD, "Ping {mentions}"), IsNil)
+	c.Assert(client.thread[1], Equals, "Ping <@3> <@4>")
+
+	client.failDM = true
+	c.Assert(Notify(client, r, MODE_DIRECT, "test"), NotNil)
+
+	c.Assert(Notify(nil, r, MODE_DIRECT, ""), Equals, ErrNilClient)
+	c.Assert(Notify(client, nil, MODE_DIRECT, ""), IsNil)
+	c.Assert(Notify(client, &Receipt{Message: r.Message}, MODE_DIRECT, ""), IsNil)
+}
+
+func (s *ReceiptsSuite) TestRunner(c *C) {
+	client := newFakeClient()
+	runner, err := NewRunner(client)
+	c.Assert(err, IsNil)
+
+	c.Assert(runner.Track(1,
+		Step{Delay: time.Hour, Mode: MODE_THREAD, Text: "Last call {mentions}"},
+		Step{Delay: time.Minute, Mode: MODE_DIRECT, Text: "Read it"},
+	), IsNil)
+	c.Assert(runner.Track(2, Step{Delay: time.Minute}), IsNil)
+	c.Assert(runner.Tracked(), DeepEquals, []uint{1, 2})
+
+	now := time.Now()
+
+	c.Assert(runner.Check(now), IsNil)
+	c.Assert(client.direct, HasLen, 0)
+
+	c.Assert(runner.Check(now.Add(2*time.Minute)), IsNil)
+	c.Assert(client.direct[3], DeepEquals, []string{
+		"Read it", "Please read this important message: https://app.pachca.com/chats/20?message=2",
+	})
+	c.Assert(runner.Tracked(), DeepEquals, []uint{1})
+
+	// User 3 read message, so only user 4 should be mentioned
+	client.reads = []uint{2, 3}
+
+	c.Assert(runner.Check(now.Add(2*time.Hour)), IsNil)
+	c.Assert(client.thread, DeepEquals, []string{"Last call <@4>"})
+	c.Assert(runner.Tracked(), HasLen, 0)
+
+	// Everybody read message, so tracking stops without notifications
+	client.reads = []uint{2, 3, 4}
+	c.Assert(runner.Track(1, Step{Delay: 0}, Step{Delay: time.Hour}), IsNil)
+	c.Assert(runner.Check(time.Now()), IsNil)
+	c.Assert(client.thread, HasLen, 1)
+	c.Assert(runner.Tracked(), HasLen, 0)
+
+	// Several due steps are collapsed into the latest one
+	client.reads = nil
+	c.Assert(runner.Track(1,
+		Step{Delay: 0, Text: "first"},
+		Step{Delay: 0, Mode: MODE_THREAD, Text: "second"},
+	), IsNil)
+	c.Assert(runner.Check(time.Now()), IsNil)
+	c.Assert(client.thread, DeepEquals, []string{"Last call <@4>", "second"})
+
+	c.Assert(runner.Track(1, Step{Delay: time.Minute}), IsNil)
+	runner.Untrack(1)
+	c.Assert(runner.Tracked(), HasLen, 0)
+}
+
+func (s *ReceiptsSuite) TestRunnerPartialFailure(c *C) {
+	client := newFakeClient()
+	runner, _ := NewRunner(client)
+
+	c.Assert(runner.Track(1, Step{Text: "Read it"}, Step{Delay: time.Hour, Text: "Read it now"}), IsNil)
+
+	client.failTo = 4
+
+	now := time.Now()
+
+	c.Assert(runner.Check(now), NotNil)
+	c.Assert(client.direct[3], DeepEquals, []string{"Read it"})
+	c.Assert(client.direct[4], HasLen, 0)
+
+	// Users notified before failure must not receive the same message again
+	client.failTo = 0
+
+	c.Assert(runner.Check(now.Add(time.Minute)), IsNil)
+	c.Assert(client.direct[3], DeepEquals, []string{"Read it"})
+	c.Assert(client.direct[4], DeepEquals, []string{"Read it"})
+
+	c.Assert(runner.Check(now.Add(2*time.Hour)), IsNil)
+	c.Assert(client.direct[3], DeepEquals, []string{"Read it", "Read it now"})
+	c.Assert(client.direct[4], DeepEquals, []string{"Read it", "Read it now"})
+	c.Assert(runner.Tracked(), HasLen, 0)
+}
+
+func (s *ReceiptsSuite) TestRunnerErrors(c *C) {
+	_, err := NewRunner(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	client := newFakeClient()
+	runner, _ := NewRunner(client)
+
+	c.Assert(runner.Track(0, Step{}), Equals, ErrInvalidMessageID)
+	c.Assert(runner.Track(1), Equals, ErrNoSteps)
+	c.Assert(runner.Run(0, nil), Equals, ErrInvalidInterval)
+
+	var errored []uint
+
+	runner.OnError = func(messageID uint, err error) {
+		errored = append(errored, messageID)
+	}
+
+	client.failGet = true
+	runner.Track(1, Step{})
+	c.Assert(runner.Check(time.Now()), ErrorMatches, "can't check message 1: .*")
+	c.Assert(errored, DeepEquals, []uint{1})
+	c.Assert(runner.Tracked(), DeepEquals, []uint{1})
+
+	client.failGet = false
+	stop := make(chan struct{})
+	done := make(chan error)
+
+	go func() { done <- runner.Run(5*time.Millisecond, stop) }()
+
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(runner.Tracked(), HasLen, 0)
+
+	var nr *Runner
+	c.Assert(nr.Track(1, Step{}), Equals, ErrNilRunner)
+	c.Assert(nr.Check(time.Now()), Equals, ErrNilRunner)
+	c.Assert(nr.Run(time.Second, nil), Equals, ErrNilRunner)
+	c.Assert(nr.Tracked(), IsNil)
+	nr.Untrack(1)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{reads: []uint{2}, direct: map[uint][]string{}}
+}
+
+func (c *fakeClient) GetMessage(messageID uint) (*pachca.Message, error) {
+	if c.failGet {
+		return nil, fmt.Errorf("API error")
+	}
+
+	msg := &pachca.Message{ID: messageID, ChatID: 10, UserID: 1}
+
+	// Message 2 is a message in a thread of chat 10
+	if messageID == 2 {
+		msg.ChatID, msg.RootChatID = 20, 10
+	}
+
+	return msg, nil
+}
+
+func (c *fakeClient) GetMessageReads(messageID uint) ([]uint, error) {
+	return c.reads, nil
+}
+
+func (c *fakeClient) GetChatUsers(chatID uint, memberRole pachca.ChatRole) (pachca.Users, error) {
+	c.chatIDs = append(c.chatIDs, chatID)
+
+	return pachca.Users{
+		{ID: 1, InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 2, InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 3, InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 4, InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 5, InviteStatus: pachca.INVITE_CONFIRMED, IsBot: true},
+		{ID: 6, InviteStatus: pachca.INVITE_CONFIRMED, IsSuspended: true},
+		{ID: 7, InviteStatus: pachca.INVITE_SENT},
+	}, nil
+}
+
+func (c *fakeClient) SendMessageToUser(userID uint, text string) (*pachca.Message, error) {
+	if c.failDM || userID == c.failTo {
+		return nil, fmt.Errorf("API error")
+	}
+
+	c.mu.Lock()
+	c.direct[userID] = append(c.direct[userID], text)
+	c.mu.Unlock()
+
+	return &pachca.Message{}, nil
+}
+
+func (c *fakeClient) AddThreadMessageText(messageID uint, text string) (*pachca.Thread, *pachca.Message, error) {
+	c.thread = append(c.thread, text)
+	return &pachca.Thread{}, &pachca.Message{}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func userIDs(users pachca.Users) []uint {
+	var result []uint
+
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+
+	return result
+}
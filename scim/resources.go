@@ -0,0 +1,237 @@
+package scim
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	SCHEMA_USER       = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCHEMA_GROUP      = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCHEMA_ENTERPRISE = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SCHEMA_LIST       = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCHEMA_PATCH      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCHEMA_ERROR      = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCHEMA_SP_CONFIG  = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// User is SCIM user resource
+type User struct {
+	Schemas      []string        `json:"schemas"`
+	ID           string          `json:"id,omitempty"`
+	ExternalID   string          `json:"externalId,omitempty"`
+	UserName     string          `json:"userName"`
+	Name         *Name           `json:"name,omitempty"`
+	DisplayName  string          `json:"displayName,omitempty"`
+	NickName     string          `json:"nickName,omitempty"`
+	Title        string          `json:"title,omitempty"`
+	Active       *bool           `json:"active,omitempty"`
+	Emails       []*MultiValue   `json:"emails,omitempty"`
+	PhoneNumbers []*MultiValue   `json:"phoneNumbers,omitempty"`
+	Groups       []*Member       `json:"groups,omitempty"`
+	Enterprise   *EnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta         *Meta           `json:"meta,omitempty"`
+}
+
+// Name contains user name components
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValue is multi-valued attribute (email or phone number)
+type MultiValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// EnterpriseUser contains enterprise user extension attributes
+type EnterpriseUser struct {
+	Department string `json:"department,omitempty"`
+}
+
+// Group is SCIM group resource
+type Group struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Members     []*Member `json:"members,omitempty"`
+	Meta        *Meta     `json:"meta,omitempty"`
+}
+
+// Member is reference to group member or user group
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// Meta contains resource metadata
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+}
+
+// ListResponse is response with list of resources
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// PatchRequest is PATCH request
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is single PATCH operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is SCIM error response
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Email returns user email. It uses user name if it looks like email, primary
+// email or the first email from the list.
+func (u *User) Email() string {
+	if u == nil {
+		return ""
+	}
+
+	if strings.Contains(u.UserName, "@") {
+		return u.UserName
+	}
+
+	return primaryValue(u.Emails)
+}
+
+// Phone returns primary phone number of user
+func (u *User) Phone() string {
+	if u == nil {
+		return ""
+	}
+
+	return primaryValue(u.PhoneNumbers)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// toUser converts Pachca user to SCIM resource
+func toUser(u *pachca.User, tags map[string]uint) *User {
+	active := !u.IsSuspended
+	result := &User{
+		Schemas:     []string{SCHEMA_USER, SCHEMA_ENTERPRISE},
+		ID:          strconv.FormatUint(uint64(u.ID), 10),
+		UserName:    u.Email,
+		DisplayName: u.FullName(),
+		NickName:    u.Nickname,
+		Title:       u.Title,
+		Active:      &active,
+		Meta:        &Meta{ResourceType: "User", Created: formatTime(u.CreatedAt.Time)},
+	}
+
+	if u.FirstName != "" || u.LastName != "" {
+		result.Name = &Name{
+			Formatted:  u.FullName(),
+			GivenName:  u.FirstName,
+			FamilyName: u.LastName,
+		}
+	}
+
+	if u.Email != "" {
+		result.Emails = []*MultiValue{{Value: u.Email, Type: "work", Primary: true}}
+	}
+
+	if u.PhoneNumber != "" {
+		result.PhoneNumbers = []*MultiValue{{Value: u.PhoneNumber, Type: "work", Primary: true}}
+	}
+
+	if u.Department != "" {
+		result.Enterprise = &EnterpriseUser{Department: u.Department}
+	}
+
+	for _, tag := range u.Tags {
+		if id, ok := tags[tag]; ok {
+			result.Groups = append(result.Groups, &Member{
+				Value: strconv.FormatUint(uint64(id), 10), Display: tag,
+			})
+		}
+	}
+
+	return result
+}
+
+// toGroup converts Pachca group tag to SCIM resource
+func toGroup(t *pachca.Tag, users pachca.Users) *Group {
+	result := &Group{
+		Schemas:     []string{SCHEMA_GROUP},
+		ID:          strconv.FormatUint(uint64(t.ID), 10),
+		DisplayName: t.Name,
+		Meta:        &Meta{ResourceType: "Group"},
+	}
+
+	for _, u := range users {
+		if u != nil {
+			result.Members = append(result.Members, &Member{
+				Value: strconv.FormatUint(uint64(u.ID), 10), Display: u.FullName(),
+			})
+		}
+	}
+
+	return result
+}
+
+// primaryValue returns primary or the first value from multi-valued attribute
+func primaryValue(values []*MultiValue) string {
+	for _, v := range values {
+		if v != nil && v.Primary {
+			return v.Value
+		}
+	}
+
+	for _, v := range values {
+		if v != nil {
+			return v.Value
+		}
+	}
+
+	return ""
+}
+
+// formatTime formats time in RFC3339 format
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,1215 @@
+package scim
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CONTENT_TYPE is content type of SCIM responses
+const CONTENT_TYPE = "application/scim+json"
+
+const (
+	DEFAULT_COUNT = 100
+	MAX_COUNT     = 1000
+)
+
+// MAX_BODY_SIZE is maximum size of request body
+const MAX_BODY_SIZE = 1024 * 1024 // 1 MB
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by SCIM handler
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+	GetUser(userID uint) (*pachca.User, error)
+	AddUser(user *pachca.UserRequest) (*pachca.User, error)
+	EditUser(userID uint, user *pachca.UserRequest) (*pachca.User, error)
+	DeleteUser(userID uint) error
+	SuspendUser(userID uint) error
+	UnsuspendUser(userID uint) error
+	SetUserTags(userID uint, tags []string) error
+	GetTags(names ...string) (pachca.Tags, error)
+	GetTag(groupTagID uint) (*pachca.Tag, error)
+	GetTagUsers(groupTagID uint) (pachca.Users, error)
+	AddTag(groupTagName string) (*pachca.Tag, error)
+	EditTag(groupTagID uint, groupTagName string) (*pachca.Tag, error)
+	DeleteTag(groupTagID uint) error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Handler is HTTP handler implementing SCIM 2.0 Users and Groups resources.
+// Handler must be mounted at SCIM base URL:
+//
+//	mux.Handle("/scim/v2/", http.StripPrefix("/scim/v2", handler))
+type Handler struct {
+	// HardDelete enables deleting users on DELETE requests (users are suspended
+	// by default)
+	HardDelete bool
+
+	// OnError is callback for Pachca API errors
+	OnError func(err error)
+
+	client Client
+	token  string
+	mux    *http.ServeMux
+}
+
+// scimError is error with SCIM status and type
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+// handlerFunc is SCIM request handler
+type handlerFunc func(r *http.Request) (int, any, error)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient  = errors.New("client is nil")
+	ErrEmptyToken = errors.New("token is empty")
+)
+
+// filterRegex is regex for simple "attribute eq value" filters
+var filterRegex = regexp.MustCompile(`(?i)^\s*([\w.:]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// memberPathRegex is regex for member value filter in PATCH path
+var memberPathRegex = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new SCIM handler. Requests must be authorized with given bearer
+// token.
+func New(client Client, token string) (*Handler, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case token == "":
+		return nil, ErrEmptyToken
+	}
+
+	h := &Handler{client: client, token: token, mux: http.NewServeMux()}
+
+	h.handle("GET /ServiceProviderConfig", h.getConfig)
+
+	h.handle("GET /Users", h.listUsers)
+	h.handle("POST /Users", h.createUser)
+	h.handle("GET /Users/{id}", h.getUser)
+	h.handle("PUT /Users/{id}", h.replaceUser)
+	h.handle("PATCH /Users/{id}", h.patchUser)
+	h.handle("DELETE /Users/{id}", h.deleteUser)
+
+	h.handle("GET /Groups", h.listGroups)
+	h.handle("POST /Groups", h.createGroup)
+	h.handle("GET /Groups/{id}", h.getGroup)
+	h.handle("PUT /Groups/{id}", h.replaceGroup)
+	h.handle("PATCH /Groups/{id}", h.patchGroup)
+	h.handle("DELETE /Groups/{id}", h.deleteGroup)
+
+	return h, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ServeHTTP serves SCIM requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.mux == nil {
+		writeError(w, http.StatusInternalServerError, "", "handler is not initialized")
+		return
+	}
+
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if !ok || subtle.ConstantTimeCompare([]byte(auth), []byte(h.token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "", "authorization failure")
+		return
+	}
+
+	_, pattern := h.mux.Handler(r)
+
+	if pattern == "" {
+		writeError(w, http.StatusNotFound, "", "unknown endpoint "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Error returns error message
+func (e *scimError) Error() string {
+	return e.detail
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// handle registers handler for given pattern
+func (h *Handler) handle(pattern string, fn handlerFunc) {
+	h.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		status, data, err := fn(r)
+
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		if data == nil {
+			w.WriteHeader(status)
+			return
+		}
+
+		writeJSON(w, status, data)
+	})
+}
+
+// writeError writes error response
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	var se *scimError
+
+	switch {
+	case errors.As(err, &se):
+		writeError(w, se.status, se.scimType, se.detail)
+	case errors.Is(err, pachca.ErrRateLimited):
+		writeError(w, http.StatusTooManyRequests, "", err.Error())
+	default:
+		if h.OnError != nil {
+			h.OnError(err)
+		}
+
+		// Internal error details are reported only to OnError callback
+		writeError(w, http.StatusInternalServerError, "", "internal server error")
+	}
+}
+
+// getConfig returns service provider configuration
+func (h *Handler) getConfig(r *http.Request) (int, any, error) {
+	return http.StatusOK, map[string]any{
+		"schemas":        []string{SCHEMA_SP_CONFIG},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": MAX_COUNT},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type": "oauthbearertoken",
+			"name": "OAuth Bearer Token",
+		}},
+	}, nil
+}
+
+// USERS //////////////////////////////////////////////////////////////////////////// //
+
+// listUsers returns list of users
+func (h *Handler) listUsers(r *http.Request) (int, any, error) {
+	attr, value, err := parseFilter(r.URL.Query().Get("filter"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var query []string
+
+	switch attr {
+	case "", "id":
+		// no search query
+	case "username", "emails", "emails.value":
+		query = append(query, value)
+	case "externalid":
+		return http.StatusOK, listResponse(nil, r), nil
+	default:
+		return 0, nil, badRequest("invalidFilter", "unsupported filter attribute %q", attr)
+	}
+
+	users, err := h.client.GetUsers(query...)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tags, err := h.tagIDs()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var result []any
+
+	for _, u := range users {
+		if u == nil || u.IsBot {
+			continue
+		}
+
+		switch attr {
+		case "id":
+			if strconv.FormatUint(uint64(u.ID), 10) != value {
+				continue
+			}
+		case "username", "emails", "emails.value":
+			if !strings.EqualFold(u.Email, value) {
+				continue
+			}
+		}
+
+		result = append(result, toUser(u, tags))
+	}
+
+	return http.StatusOK, listResponse(result, r), nil
+}
+
+// getUser returns user
+func (h *Handler) getUser(r *http.Request) (int, any, error) {
+	user, err := h.findUser(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.userResponse(http.StatusOK, user)
+}
+
+// createUser creates new user
+func (h *Handler) createUser(r *http.Request) (int, any, error) {
+	su := &User{}
+	err := readJSON(r, su)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	email := su.Email()
+
+	if email == "" {
+		return 0, nil, badRequest("invalidValue", "user must have userName or email")
+	}
+
+	users, err := h.client.GetUsers(email)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, u := range users {
+		if u != nil && strings.EqualFold(u.Email, email) {
+			return 0, nil, &scimError{
+				http.StatusConflict, "uniqueness",
+				fmt.Sprintf("user with email %q already exists", email),
+			}
+		}
+	}
+
+	request := &pachca.UserRequest{
+		Email:       email,
+		Nickname:    su.NickName,
+		Title:       su.Title,
+		PhoneNumber: su.Phone(),
+		IsSuspended: su.Active != nil && !*su.Active,
+	}
+
+	if su.Name != nil {
+		request.FirstName = su.Name.GivenName
+		request.LastName = su.Name.FamilyName
+	}
+
+	if su.Enterprise != nil {
+		request.Department = su.Enterprise.Department
+	}
+
+	user, err := h.client.AddUser(request)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.userResponse(http.StatusCreated, user)
+}
+
+// replaceUser replaces user attributes
+func (h *Handler) replaceUser(r *http.Request) (int, any, error) {
+	user, err := h.findUser(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	su := &User{}
+	err = readJSON(r, su)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.updateUser(user, su)
+}
+
+// patchUser modifies user attributes
+func (h *Handler) patchUser(r *http.Request) (int, any, error) {
+	user, err := h.findUser(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	patch := &PatchRequest{}
+	err = readJSON(r, patch)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	su := toUser(user, nil)
+	err = applyUserPatch(su, patch.Operations)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.updateUser(user, su)
+}
+
+// deleteUser suspends or deletes user
+func (h *Handler) deleteUser(r *http.Request) (int, any, error) {
+	user, err := h.findUser(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch {
+	case h.HardDelete:
+		err = h.client.DeleteUser(user.ID)
+	case !user.IsSuspended:
+		err = h.client.SuspendUser(user.ID)
+	}
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusNoContent, nil, nil
+}
+
+// updateUser applies attributes of SCIM user to Pachca user. Empty attributes
+// clear user fields, except email which is required.
+func (h *Handler) updateUser(user *pachca.User, su *User) (int, any, error) {
+	request := &pachca.UserRequest{}
+	changed := false
+
+	update := func(field string, target *string, current, value string) {
+		switch {
+		case value == current:
+			return
+		case value == "":
+			request.ClearFields = append(request.ClearFields, field)
+		default:
+			*target = value
+		}
+
+		changed = true
+	}
+
+	if email := su.Email(); email != "" && !strings.EqualFold(email, user.Email) {
+		update("email", &request.Email, user.Email, email)
+	}
+
+	var firstName, lastName, department string
+
+	if su.Name != nil {
+		firstName, lastName = su.Name.GivenName, su.Name.FamilyName
+	}
+
+	if su.Enterprise != nil {
+		department = su.Enterprise.Department
+	}
+
+	update("first_name", &request.FirstName, user.FirstName, firstName)
+	update("last_name", &request.LastName, user.LastName, lastName)
+	update("nickname", &request.Nickname, user.Nickname, su.NickName)
+	update("title", &request.Title, user.Title, su.Title)
+	update("phone_number", &request.PhoneNumber, user.PhoneNumber, su.Phone())
+	update("department", &request.Department, user.Department, department)
+
+	var err error
+
+	if changed {
+		_, err = h.client.EditUser(user.ID, request)
+
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if su.Active != nil && *su.Active == user.IsSuspended {
+		if *su.Active {
+			err = h.client.UnsuspendUser(user.ID)
+		} else {
+			err = h.client.SuspendUser(user.ID)
+		}
+
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	user, err = h.client.GetUser(user.ID)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.userResponse(http.StatusOK, user)
+}
+
+// findUser returns user with given ID
+func (h *Handler) findUser(id string) (*pachca.User, error) {
+	userID, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil || userID == 0 {
+		return nil, notFound("user %s not found", id)
+	}
+
+	user, err := h.client.GetUser(uint(userID))
+
+	if err != nil && !errors.Is(err, pachca.ErrNotFound) {
+		return nil, err
+	}
+
+	if user == nil || user.IsBot {
+		return nil, notFound("user %s not found", id)
+	}
+
+	return user, nil
+}
+
+// userResponse returns response with SCIM user
+func (h *Handler) userResponse(status int, user *pachca.User) (int, any, error) {
+	tags, err := h.tagIDs()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return status, toUser(user, tags), nil
+}
+
+// tagIDs returns map tag name → tag ID
+func (h *Handler) tagIDs() (map[string]uint, error) {
+	tags, err := h.client.GetTags()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint, len(tags))
+
+	for _, t := range tags {
+		if t != nil {
+			result[t.Name] = t.ID
+		}
+	}
+
+	return result, nil
+}
+
+// GROUPS /////////////////////////////////////////////////////////////////////////// //
+
+// listGroups returns list of groups
+func (h *Handler) listGroups(r *http.Request) (int, any, error) {
+	attr, value, err := parseFilter(r.URL.Query().Get("filter"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch attr {
+	case "", "id", "displayname":
+		// ok
+	case "externalid":
+		return http.StatusOK, listResponse(nil, r), nil
+	default:
+		return 0, nil, badRequest("invalidFilter", "unsupported filter attribute %q", attr)
+	}
+
+	tags, err := h.client.GetTags()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	withMembers := !strings.Contains(
+		strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members",
+	)
+
+	var result []any
+
+	for _, t := range tags {
+		if t == nil ||
+			(attr == "id" && strconv.FormatUint(uint64(t.ID), 10) != value) ||
+			(attr == "displayname" && !strings.EqualFold(t.Name, value)) {
+			continue
+		}
+
+		result = append(result, t)
+	}
+
+	resp := listResponse(result, r)
+
+	// Members are fetched only for groups on requested page
+	for i, item := range resp.Resources {
+		var users pachca.Users
+
+		tag := item.(*pachca.Tag)
+
+		if withMembers {
+			users, err = h.client.GetTagUsers(tag.ID)
+
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+
+		resp.Resources[i] = toGroup(tag, users)
+	}
+
+	return http.StatusOK, resp, nil
+}
+
+// getGroup returns group
+func (h *Handler) getGroup(r *http.Request) (int, any, error) {
+	tag, err := h.findTag(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.groupResponse(http.StatusOK, tag)
+}
+
+// createGroup creates new group
+func (h *Handler) createGroup(r *http.Request) (int, any, error) {
+	sg := &Group{}
+	err := readJSON(r, sg)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if sg.DisplayName == "" {
+		return 0, nil, badRequest("invalidValue", "group must have displayName")
+	}
+
+	tags, err := h.client.GetTags()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if tags.Find(sg.DisplayName) != nil {
+		return 0, nil, &scimError{
+			http.StatusConflict, "uniqueness",
+			fmt.Sprintf("group %q already exists", sg.DisplayName),
+		}
+	}
+
+	tag, err := h.client.AddTag(sg.DisplayName)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = h.setMembers(tag, nil, memberIDs(sg.Members))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.groupResponse(http.StatusCreated, tag)
+}
+
+// replaceGroup replaces group name and members
+func (h *Handler) replaceGroup(r *http.Request) (int, any, error) {
+	tag, err := h.findTag(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sg := &Group{}
+	err = readJSON(r, sg)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.updateGroup(tag, sg.DisplayName, memberIDs(sg.Members))
+}
+
+// patchGroup modifies group name and members
+func (h *Handler) patchGroup(r *http.Request) (int, any, error) {
+	tag, err := h.findTag(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	patch := &PatchRequest{}
+	err = readJSON(r, patch)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	users, err := h.client.GetTagUsers(tag.ID)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var members []uint
+
+	for _, u := range users {
+		if u != nil {
+			members = append(members, u.ID)
+		}
+	}
+
+	name, members, err := applyGroupPatch(tag.Name, members, patch.Operations)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.updateGroup(tag, name, members)
+}
+
+// deleteGroup deletes group
+func (h *Handler) deleteGroup(r *http.Request) (int, any, error) {
+	tag, err := h.findTag(r.PathValue("id"))
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = h.client.DeleteTag(tag.ID)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusNoContent, nil, nil
+}
+
+// updateGroup renames group and syncs its members
+func (h *Handler) updateGroup(tag *pachca.Tag, name string, members []uint) (int, any, error) {
+	if name != "" && name != tag.Name {
+		renamed, err := h.client.EditTag(tag.ID, name)
+
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if renamed != nil {
+			tag = renamed
+		} else {
+			tag.Name = name
+		}
+	}
+
+	users, err := h.client.GetTagUsers(tag.ID)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = h.setMembers(tag, users, members)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return h.groupResponse(http.StatusOK, tag)
+}
+
+// setMembers adds and removes group tag from users
+func (h *Handler) setMembers(tag *pachca.Tag, current pachca.Users, members []uint) error {
+	for _, u := range current {
+		if u != nil && !slices.Contains(members, u.ID) {
+			err := h.updateUserTags(u.ID, tag.Name, false)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range members {
+		if current.Get(id) == nil {
+			err := h.updateUserTags(id, tag.Name, true)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateUserTags adds or removes tag from user with given ID
+func (h *Handler) updateUserTags(userID uint, tag string, add bool) error {
+	user, err := h.client.GetUser(userID)
+
+	if err != nil && !errors.Is(err, pachca.ErrNotFound) {
+		return err
+	}
+
+	if user == nil {
+		return badRequest("invalidValue", "user %d not found", userID)
+	}
+
+	if user.HasTag(tag) == add {
+		return nil
+	}
+
+	tags := slices.Clone(user.Tags)
+
+	if add {
+		tags = append(tags, tag)
+	} else {
+		tags = slices.DeleteFunc(tags, func(t string) bool { return t == tag })
+	}
+
+	return h.client.SetUserTags(userID, tags)
+}
+
+// findTag returns group tag with given ID
+func (h *Handler) findTag(id string) (*pachca.Tag, error) {
+	tagID, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil || tagID == 0 {
+		return nil, notFound("group %s not found", id)
+	}
+
+	tag, err := h.client.GetTag(uint(tagID))
+
+	if err != nil && !errors.Is(err, pachca.ErrNotFound) {
+		return nil, err
+	}
+
+	if tag == nil {
+		return nil, notFound("group %s not found", id)
+	}
+
+	return tag, nil
+}
+
+// groupResponse returns response with SCIM group
+func (h *Handler) groupResponse(status int, tag *pachca.Tag) (int, any, error) {
+	users, err := h.client.GetTagUsers(tag.ID)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return status, toGroup(tag, users), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// applyUserPatch applies PATCH operations to SCIM user. Attributes which
+// can't be modified are ignored.
+func applyUserPatch(su *User, ops []*PatchOperation) error {
+	for _, op := range ops {
+		if op == nil {
+			continue
+		}
+
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			// ok
+		case "remove":
+			err := removeUserAttr(su, op.Path)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		default:
+			return badRequest("invalidSyntax", "unsupported operation %q", op.Op)
+		}
+
+		if op.Path != "" {
+			err := setUserAttr(su, op.Path, op.Value)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		values := map[string]json.RawMessage{}
+		err := json.Unmarshal(op.Value, &values)
+
+		if err != nil {
+			return badRequest("invalidValue", "invalid operation value: %v", err)
+		}
+
+		for path, value := range values {
+			err = setUserAttr(su, path, value)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setUserAttr sets value of user attribute with given path
+func setUserAttr(su *User, path string, value json.RawMessage) error {
+	var err error
+
+	p := strings.ToLower(path)
+
+	if su.Name == nil {
+		su.Name = &Name{}
+	}
+
+	if su.Enterprise == nil {
+		su.Enterprise = &EnterpriseUser{}
+	}
+
+	switch {
+	case p == "active":
+		var active bool
+		active, err = parseBool(value)
+		su.Active = &active
+	case p == "username":
+		err = json.Unmarshal(value, &su.UserName)
+	case p == "name":
+		err = json.Unmarshal(value, su.Name)
+	case p == "name.givenname":
+		err = json.Unmarshal(value, &su.Name.GivenName)
+	case p == "name.familyname":
+		err = json.Unmarshal(value, &su.Name.FamilyName)
+	case p == "nickname":
+		err = json.Unmarshal(value, &su.NickName)
+	case p == "title":
+		err = json.Unmarshal(value, &su.Title)
+	case p == "emails":
+		su.Emails = nil
+		err = json.Unmarshal(value, &su.Emails)
+	case strings.HasPrefix(p, "emails[") && strings.HasSuffix(p, "].value"):
+		su.Emails, err = setPrimaryValue(value)
+	case p == "phonenumbers":
+		su.PhoneNumbers = nil
+		err = json.Unmarshal(value, &su.PhoneNumbers)
+	case strings.HasPrefix(p, "phonenumbers[") && strings.HasSuffix(p, "].value"):
+		su.PhoneNumbers, err = setPrimaryValue(value)
+	case p == strings.ToLower(SCHEMA_ENTERPRISE):
+		err = json.Unmarshal(value, su.Enterprise)
+	case p == strings.ToLower(SCHEMA_ENTERPRISE)+":department":
+		err = json.Unmarshal(value, &su.Enterprise.Department)
+	}
+
+	if err != nil {
+		return badRequest("invalidValue", "invalid value for %q: %v", path, err)
+	}
+
+	// If user name is email, new email from emails must replace it
+	if strings.HasPrefix(p, "emails") && strings.Contains(su.UserName, "@") {
+		if email := primaryValue(su.Emails); email != "" {
+			su.UserName = email
+		}
+	}
+
+	return nil
+}
+
+// removeUserAttr removes value of user attribute with given path
+func removeUserAttr(su *User, path string) error {
+	p := strings.ToLower(path)
+
+	switch {
+	case p == "":
+		return badRequest("noTarget", "path is required for remove operation")
+	case p == "active", p == "username", strings.HasPrefix(p, "emails"):
+		return badRequest("mutability", "attribute %q can't be removed", path)
+	case p == "name":
+		su.Name = nil
+	case p == "name.givenname" && su.Name != nil:
+		su.Name.GivenName = ""
+	case p == "name.familyname" && su.Name != nil:
+		su.Name.FamilyName = ""
+	case p == "nickname":
+		su.NickName = ""
+	case p == "title":
+		su.Title = ""
+	case strings.HasPrefix(p, "phonenumbers"):
+		su.PhoneNumbers = nil
+	case p == strings.ToLower(SCHEMA_ENTERPRISE),
+		p == strings.ToLower(SCHEMA_ENTERPRISE)+":department":
+		su.Enterprise = nil
+	}
+
+	return nil
+}
+
+// applyGroupPatch applies PATCH operations to group name and members
+func applyGroupPatch(name string, members []uint, ops []*PatchOperation) (string, []uint, error) {
+	for _, op := range ops {
+		if op == nil {
+			continue
+		}
+
+		path := strings.ToLower(op.Path)
+		opType := strings.ToLower(op.Op)
+
+		switch {
+		case path == "" && (opType == "add" || opType == "replace"):
+			group := &Group{}
+			err := json.Unmarshal(op.Value, group)
+
+			if err != nil {
+				return "", nil, badRequest("invalidValue", "invalid operation value: %v", err)
+			}
+
+			if group.DisplayName != "" {
+				name = group.DisplayName
+			}
+
+			if group.Members != nil {
+				members = mergeMembers(members, memberIDs(group.Members), opType == "replace")
+			}
+
+		case path == "displayname" && opType != "remove":
+			err := json.Unmarshal(op.Value, &name)
+
+			if err != nil {
+				return "", nil, badRequest("invalidValue", "invalid value for %q: %v", op.Path, err)
+			}
+
+		case path == "members" && (opType == "add" || opType == "replace"):
+			var list []*Member
+			err := json.Unmarshal(op.Value, &list)
+
+			if err != nil {
+				return "", nil, badRequest("invalidValue", "invalid value for %q: %v", op.Path, err)
+			}
+
+			members = mergeMembers(members, memberIDs(list), opType == "replace")
+
+		case path == "members" && opType == "remove":
+			if len(op.Value) == 0 || string(op.Value) == "null" {
+				members = nil
+				continue
+			}
+
+			var list []*Member
+			err := json.Unmarshal(op.Value, &list)
+
+			if err != nil {
+				return "", nil, badRequest("invalidValue", "invalid value for %q: %v", op.Path, err)
+			}
+
+			remove := memberIDs(list)
+			members = slices.DeleteFunc(members, func(id uint) bool {
+				return slices.Contains(remove, id)
+			})
+
+		case memberPathRegex.MatchString(op.Path) && opType == "remove":
+			id, _ := strconv.ParseUint(memberPathRegex.FindStringSubmatch(op.Path)[1], 10, 64)
+			members = slices.DeleteFunc(members, func(m uint) bool {
+				return m == uint(id)
+			})
+
+		default:
+			return "", nil, badRequest(
+				"invalidPath", "unsupported operation %q for path %q", op.Op, op.Path,
+			)
+		}
+	}
+
+	return name, members, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseFilter parses simple filter expression
+func parseFilter(filter string) (string, string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", "", nil
+	}
+
+	m := filterRegex.FindStringSubmatch(filter)
+
+	if m == nil {
+		return "", "", badRequest("invalidFilter", "unsupported filter %q", filter)
+	}
+
+	value, err := strconv.Unquote(`"` + m[2] + `"`)
+
+	if err != nil {
+		return "", "", badRequest("invalidFilter", "invalid filter value %q", m[2])
+	}
+
+	return strings.ToLower(m[1]), value, nil
+}
+
+// listResponse creates list response with paging
+func listResponse(resources []any, r *http.Request) *ListResponse {
+	query := r.URL.Query()
+	start, _ := strconv.Atoi(query.Get("startIndex"))
+	count, err := strconv.Atoi(query.Get("count"))
+
+	if err != nil {
+		count = DEFAULT_COUNT
+	}
+
+	start = max(start, 1)
+	count = min(max(count, 0), MAX_COUNT)
+
+	page := []any{}
+
+	if start <= len(resources) {
+		page = resources[start-1 : min(start-1+count, len(resources))]
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SCHEMA_LIST},
+		TotalResults: len(resources),
+		StartIndex:   start,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// readJSON decodes JSON request body
+func readJSON(r *http.Request, v any) error {
+	err := json.NewDecoder(io.LimitReader(r.Body, MAX_BODY_SIZE)).Decode(v)
+
+	if err != nil {
+		return badRequest("invalidSyntax", "can't decode request body: %v", err)
+	}
+
+	return nil
+}
+
+// writeJSON writes JSON response
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeError writes SCIM error response
+func writeError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeJSON(w, status, &Error{
+		Schemas:  []string{SCHEMA_ERROR},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// badRequest creates error for invalid request
+func badRequest(scimType, format string, args ...any) error {
+	return &scimError{http.StatusBadRequest, scimType, fmt.Sprintf(format, args...)}
+}
+
+// notFound creates error for unknown resource
+func notFound(format string, args ...any) error {
+	return &scimError{http.StatusNotFound, "", fmt.Sprintf(format, args...)}
+}
+
+// parseBool parses boolean value which can be encoded as string
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+
+	if json.Unmarshal(value, &b) == nil {
+		return b, nil
+	}
+
+	var s string
+
+	err := json.Unmarshal(value, &s)
+
+	if err != nil {
+		return false, err
+	}
+
+	return strconv.ParseBool(s)
+}
+
+// setPrimaryValue creates multi-valued attribute with single primary value
+func setPrimaryValue(value json.RawMessage) ([]*MultiValue, error) {
+	var v string
+
+	err := json.Unmarshal(value, &v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MultiValue{{Value: v, Type: "work", Primary: true}}, nil
+}
+
+// memberIDs returns IDs of members
+func memberIDs(members []*Member) []uint {
+	var result []uint
+
+	for _, m := range members {
+		if m == nil {
+			continue
+		}
+
+		id, err := strconv.ParseUint(m.Value, 10, 64)
+
+		if err == nil && id != 0 && !slices.Contains(result, uint(id)) {
+			result = append(result, uint(id))
+		}
+	}
+
+	return result
+}
+
+// mergeMembers adds new members to list or replaces it
+func mergeMembers(members, add []uint, replace bool) []uint {
+	if replace {
+		return add
+	}
+
+	for _, id := range add {
+		if !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+
+	return members
+}
@@ -0,0 +1,720 @@
+package scim
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const TOKEN = "scim-token"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type SCIMSuite struct{}
+
+type fakeClient struct {
+	users  map[uint]*pachca.User
+	tags   map[uint]*pachca.Tag
+	nextID uint
+
+	deleted       []uint
+	tagUsersCalls int
+	fail          error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&SCIMSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *SCIMSuite) TestAuth(c *C) {
+	h, _ := New(newFakeClient(), TOKEN)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/Users", nil))
+	c.Assert(w.Code, Equals, 401)
+	c.Assert(w.Header().Get("Content-Type"), Equals, CONTENT_TYPE)
+
+	r := httptest.NewRequest("GET", "/Users", nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, 401)
+
+	status, data := request(h, "GET", "/Unknown", "")
+	c.Assert(status, Equals, 404)
+	c.Assert(data["detail"], Equals, "unknown endpoint GET /Unknown")
+
+	status, data = request(h, "GET", "/ServiceProviderConfig", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["schemas"], DeepEquals, []any{SCHEMA_SP_CONFIG})
+}
+
+func (s *SCIMSuite) TestUsers(c *C) {
+	client := newFakeClient()
+	h, _ := New(client, TOKEN)
+
+	status, data := request(h, "GET", "/Users", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 2.0)
+
+	status, data = request(h, "GET", `/Users?filter=userName+eq+"JOHN@domain.com"`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 1.0)
+
+	user := data["Resources"].([]any)[0].(map[string]any)
+	c.Assert(user["id"], Equals, "1")
+	c.Assert(user["userName"], Equals, "john@domain.com")
+	c.Assert(user["active"], Equals, true)
+	c.Assert(user["name"], DeepEquals, map[string]any{
+		"formatted": "John Doe", "givenName": "John", "familyName": "Doe",
+	})
+	c.Assert(user["groups"], DeepEquals, []any{map[string]any{"value": "10", "display": "dev"}})
+	c.Assert(user[SCHEMA_ENTERPRISE], DeepEquals, map[string]any{"department": "R&D"})
+	c.Assert(user["meta"], DeepEquals, map[string]any{
+		"resourceType": "User", "created": "2024-01-02T03:04:05Z",
+	})
+
+	status, data = request(h, "GET", `/Users?filter=id eq "2"&startIndex=1&count=10`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 1.0)
+
+	status, data = request(h, "GET", `/Users?filter=externalId eq "abc"`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 0.0)
+	c.Assert(data["Resources"], DeepEquals, []any{})
+
+	status, data = request(h, "GET", "/Users?startIndex=2&count=5", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["startIndex"], Equals, 2.0)
+	c.Assert(data["itemsPerPage"], Equals, 1.0)
+
+	status, data = request(h, "GET", "/Users/2", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["userName"], Equals, "jane@domain.com")
+	c.Assert(data["active"], Equals, false)
+
+	status, data = request(h, "GET", "/Users/3", "")
+	c.Assert(status, Equals, 404)
+	c.Assert(data["detail"], Equals, "user 3 not found")
+
+	status, _ = request(h, "GET", "/Users/100", "")
+	c.Assert(status, Equals, 404)
+	status, _ = request(h, "GET", "/Users/abc", "")
+	c.Assert(status, Equals, 404)
+
+	// Create
+
+	status, data = request(h, "POST", "/Users", `{
+		"schemas": ["`+SCHEMA_USER+`"],
+		"userName": "bob",
+		"name": {"givenName": "Bob", "familyName": "Smith"},
+		"emails": [{"value": "bob.old@domain.com"}, {"value": "bob@domain.com", "primary": true}],
+		"phoneNumbers": [{"value": "+1234567"}],
+		"title": "Manager",
+		"active": false,
+		"`+SCHEMA_ENTERPRISE+`": {"department": "Sales"}
+	}`)
+	c.Assert(status, Equals, 201)
+	c.Assert(data["id"], Equals, "100")
+	c.Assert(data["userName"], Equals, "bob@domain.com")
+	c.Assert(data["active"], Equals, false)
+
+	bob := client.users[100]
+	c.Assert(bob.FirstName, Equals, "Bob")
+	c.Assert(bob.PhoneNumber, Equals, "+1234567")
+	c.Assert(bob.Department, Equals, "Sales")
+	c.Assert(bob.IsSuspended, Equals, true)
+
+	status, data = request(h, "POST", "/Users", `{"userName": "Bob@domain.com"}`)
+	c.Assert(status, Equals, 409)
+	c.Assert(data["scimType"], Equals, "uniqueness")
+
+	status, data = request(h, "POST", "/Users", `{"userName": "bob"}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "invalidValue")
+
+	status, data = request(h, "POST", "/Users", `{`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "invalidSyntax")
+
+	// Replace
+
+	status, data = request(h, "PUT", "/Users/100", `{
+		"userName": "bob@domain.com",
+		"name": {"givenName": "Robert", "familyName": "Smith"},
+		"title": "Director",
+		"active": true
+	}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(data["title"], Equals, "Director")
+	c.Assert(data["active"], Equals, true)
+	c.Assert(bob.FirstName, Equals, "Robert")
+	c.Assert(bob.IsSuspended, Equals, false)
+	c.Assert(bob.Email, Equals, "bob@domain.com")
+	c.Assert(bob.PhoneNumber, Equals, "")
+	c.Assert(bob.Department, Equals, "")
+
+	status, _ = request(h, "PUT", "/Users/100", `{
+		"userName": "bob@domain.com",
+		"name": {"givenName": "Robert", "familyName": "Smith"},
+		"title": "Director",
+		"phoneNumbers": [{"value": "+1234567"}],
+		"`+SCHEMA_ENTERPRISE+`": {"department": "Sales"}
+	}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(bob.PhoneNumber, Equals, "+1234567")
+	c.Assert(bob.Department, Equals, "Sales")
+
+	status, _ = request(h, "PUT", "/Users/100", `[`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "PUT", "/Users/500", `{}`)
+	c.Assert(status, Equals, 404)
+
+	// Patch
+
+	status, data = request(h, "PATCH", "/Users/100", `{
+		"schemas": ["`+SCHEMA_PATCH+`"],
+		"Operations": [
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "path": "name.familyName", "value": "Brown"},
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "robert@domain.com"},
+			{"op": "add", "value": {"title": "CEO", "nickName": "rob", "`+SCHEMA_ENTERPRISE+`:department": "Board"}},
+			{"op": "replace", "path": "phoneNumbers[type eq \"work\"].value", "value": "+7654321"},
+			{"op": "remove", "path": "title"},
+			{"op": "replace", "path": "unknown", "value": 1}
+		]
+	}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(data["userName"], Equals, "robert@domain.com")
+	c.Assert(bob.LastName, Equals, "Brown")
+	c.Assert(bob.Email, Equals, "robert@domain.com")
+	c.Assert(bob.Title, Equals, "")
+	c.Assert(bob.Nickname, Equals, "rob")
+	c.Assert(bob.Department, Equals, "Board")
+	c.Assert(bob.PhoneNumber, Equals, "+7654321")
+	c.Assert(bob.IsSuspended, Equals, true)
+
+	status, _ = request(h, "PATCH", "/Users/100", `{"Operations": [
+		{"op": "replace", "value": {"active": true, "name": {"givenName": "Bobby"}, "emails": [{"value": "bobby@domain.com"}]}}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(bob.IsSuspended, Equals, false)
+	c.Assert(bob.FirstName, Equals, "Bobby")
+	c.Assert(bob.Email, Equals, "bobby@domain.com")
+
+	status, _ = request(h, "PATCH", "/Users/100", `{"Operations": [
+		{"op": "remove", "path": "nickName"},
+		{"op": "remove", "path": "name.givenName"},
+		{"op": "remove", "path": "phoneNumbers[type eq \"work\"].value"},
+		{"op": "remove", "path": "`+SCHEMA_ENTERPRISE+`:department"}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(bob.Nickname, Equals, "")
+	c.Assert(bob.FirstName, Equals, "")
+	c.Assert(bob.LastName, Equals, "Brown")
+	c.Assert(bob.PhoneNumber, Equals, "")
+	c.Assert(bob.Department, Equals, "")
+
+	status, data = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "remove"}]}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "noTarget")
+	status, data = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "remove", "path": "emails"}]}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "mutability")
+
+	status, data = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "move", "path": "title"}]}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "invalidSyntax")
+	status, data = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "add", "value": "title"}]}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "invalidValue")
+	status, data = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "add", "path": "active", "value": "maybe"}]}`)
+	c.Assert(status, Equals, 400)
+	c.Assert(data["detail"], Matches, `invalid value for "active": .*`)
+	status, _ = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "add", "value": {"active": {}}}]}`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "PATCH", "/Users/100", `{"Operations": [{"op": "add", "path": "emails[type eq \"work\"].value", "value": 1}]}`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "PATCH", "/Users/100", `{`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "PATCH", "/Users/500", `{}`)
+	c.Assert(status, Equals, 404)
+
+	// Delete
+
+	status, _ = request(h, "DELETE", "/Users/100", "")
+	c.Assert(status, Equals, 204)
+	c.Assert(bob.IsSuspended, Equals, true)
+
+	status, _ = request(h, "DELETE", "/Users/100", "")
+	c.Assert(status, Equals, 204)
+
+	h.HardDelete = true
+	status, _ = request(h, "DELETE", "/Users/100", "")
+	c.Assert(status, Equals, 204)
+	c.Assert(client.deleted, DeepEquals, []uint{100})
+
+	status, _ = request(h, "DELETE", "/Users/500", "")
+	c.Assert(status, Equals, 404)
+}
+
+func (s *SCIMSuite) TestGroups(c *C) {
+	client := newFakeClient()
+	h, _ := New(client, TOKEN)
+
+	status, data := request(h, "GET", "/Groups", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 2.0)
+	c.Assert(client.tagUsersCalls, Equals, 2)
+
+	client.tagUsersCalls = 0
+
+	status, data = request(h, "GET", "/Groups?startIndex=2&count=1", "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 2.0)
+	c.Assert(data["itemsPerPage"], Equals, 1.0)
+	c.Assert(data["Resources"].([]any)[0].(map[string]any)["id"], Equals, "11")
+	c.Assert(client.tagUsersCalls, Equals, 1)
+
+	status, data = request(h, "GET", `/Groups?filter=displayName eq "DEV"`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["Resources"], DeepEquals, []any{map[string]any{
+		"schemas":     []any{SCHEMA_GROUP},
+		"id":          "10",
+		"displayName": "dev",
+		"members":     []any{map[string]any{"value": "1", "display": "John Doe"}},
+		"meta":        map[string]any{"resourceType": "Group"},
+	}})
+
+	status, data = request(h, "GET", `/Groups?filter=id eq "11"&excludedAttributes=members`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["Resources"].([]any)[0].(map[string]any)["members"], IsNil)
+
+	status, data = request(h, "GET", `/Groups?filter=externalId eq "11"`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 0.0)
+
+	status, _ = request(h, "GET", "/Groups/10", "")
+	c.Assert(status, Equals, 200)
+	status, _ = request(h, "GET", "/Groups/99", "")
+	c.Assert(status, Equals, 404)
+	status, _ = request(h, "GET", "/Groups/x", "")
+	c.Assert(status, Equals, 404)
+
+	// Create
+
+	status, data = request(h, "POST", "/Groups", `{"displayName": "ops", "members": [{"value": "1"}, {"value": "2"}, {"value": "x"}]}`)
+	c.Assert(status, Equals, 201)
+	c.Assert(data["id"], Equals, "100")
+	c.Assert(data["members"], HasLen, 2)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev", "ops"})
+	c.Assert(client.users[2].Tags, DeepEquals, []string{"ops"})
+
+	status, data = request(h, "POST", "/Groups", `{"displayName": "OPS"}`)
+	c.Assert(status, Equals, 409)
+	c.Assert(data["scimType"], Equals, "uniqueness")
+
+	status, _ = request(h, "POST", "/Groups", `{}`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "POST", "/Groups", `{"displayName": "support", "members": [{"value": "50"}]}`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "POST", "/Groups", `[`)
+	c.Assert(status, Equals, 400)
+
+	// Replace
+
+	status, data = request(h, "PUT", "/Groups/100", `{"displayName": "devops", "members": [{"value": "2"}]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(data["displayName"], Equals, "devops")
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev"})
+	c.Assert(client.users[2].Tags, DeepEquals, []string{"devops"})
+
+	status, _ = request(h, "PUT", "/Groups/100", `[`)
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "PUT", "/Groups/99", `{}`)
+	c.Assert(status, Equals, 404)
+
+	// Patch
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [
+		{"op": "add", "path": "members", "value": [{"value": "1"}]},
+		{"op": "replace", "path": "displayName", "value": "sre"}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev", "sre"})
+	c.Assert(client.users[2].Tags, DeepEquals, []string{"sre"})
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [
+		{"op": "remove", "path": "members[value eq \"2\"]"}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[2].Tags, DeepEquals, []string{})
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [
+		{"op": "replace", "value": {"displayName": "infra", "members": [{"value": "2"}]}},
+		{"op": "add", "value": {"members": [{"value": "1"}]}}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev", "infra"})
+	c.Assert(client.users[2].Tags, DeepEquals, []string{"infra"})
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [
+		{"op": "remove", "path": "members", "value": [{"value": "1"}]}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev"})
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [
+		{"op": "replace", "path": "members", "value": [{"value": "1"}]}
+	]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev", "infra"})
+	c.Assert(client.users[2].Tags, DeepEquals, []string{})
+
+	status, _ = request(h, "PATCH", "/Groups/100", `{"Operations": [{"op": "remove", "path": "members"}]}`)
+	c.Assert(status, Equals, 200)
+	c.Assert(client.users[1].Tags, DeepEquals, []string{"dev"})
+
+	for _, body := range []string{
+		`{"Operations": [{"op": "remove", "path": "displayName"}]}`,
+		`{"Operations": [{"op": "add", "value": "x"}]}`,
+		`{"Operations": [{"op": "replace", "path": "displayName", "value": 1}]}`,
+		`{"Operations": [{"op": "add", "path": "members", "value": 1}]}`,
+		`{"Operations": [{"op": "remove", "path": "members", "value": 1}]}`,
+		`{`,
+	} {
+		status, _ = request(h, "PATCH", "/Groups/100", body)
+		c.Assert(status, Equals, 400, Commentf("Body: %s", body))
+	}
+
+	status, _ = request(h, "PATCH", "/Groups/99", `{}`)
+	c.Assert(status, Equals, 404)
+
+	// Delete
+
+	status, _ = request(h, "DELETE", "/Groups/100", "")
+	c.Assert(status, Equals, 204)
+	c.Assert(client.tags[100], IsNil)
+
+	status, _ = request(h, "DELETE", "/Groups/100", "")
+	c.Assert(status, Equals, 404)
+}
+
+func (s *SCIMSuite) TestFilters(c *C) {
+	h, _ := New(newFakeClient(), TOKEN)
+
+	status, data := request(h, "GET", `/Users?filter=title eq "CEO"`, "")
+	c.Assert(status, Equals, 400)
+	c.Assert(data["scimType"], Equals, "invalidFilter")
+
+	status, _ = request(h, "GET", `/Users?filter=userName sw "j"`, "")
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "GET", `/Users?filter=userName eq "\x"`, "")
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "GET", `/Groups?filter=members eq "1"`, "")
+	c.Assert(status, Equals, 400)
+	status, _ = request(h, "GET", `/Groups?filter=displayName co "1"`, "")
+	c.Assert(status, Equals, 400)
+
+	status, data = request(h, "GET", `/Users?filter=emails.value eq "jane@domain.com"&count=-1`, "")
+	c.Assert(status, Equals, 200)
+	c.Assert(data["totalResults"], Equals, 1.0)
+	c.Assert(data["itemsPerPage"], Equals, 0.0)
+}
+
+func (s *SCIMSuite) TestAPIErrors(c *C) {
+	client := newFakeClient()
+	h, _ := New(client, TOKEN)
+
+	var errs []error
+	h.OnError = func(err error) { errs = append(errs, err) }
+
+	client.fail = fmt.Errorf("API error")
+
+	for _, r := range [][2]string{
+		{"GET", "/Users"}, {"GET", "/Users/1"}, {"POST", "/Users"},
+		{"PUT", "/Users/1"}, {"PATCH", "/Users/1"}, {"DELETE", "/Users/1"},
+		{"GET", "/Groups"}, {"GET", "/Groups/10"}, {"POST", "/Groups"},
+		{"PUT", "/Groups/10"}, {"PATCH", "/Groups/10"}, {"DELETE", "/Groups/10"},
+	} {
+		status, data := request(h, r[0], r[1], `{"userName":"new@domain.com","displayName":"new"}`)
+		c.Assert(status, Equals, 500, Commentf("Request: %v", r))
+		c.Assert(data["detail"], Equals, "internal server error")
+	}
+
+	c.Assert(errs, HasLen, 12)
+	c.Assert(errs[0], ErrorMatches, "API error")
+
+	client.fail = fmt.Errorf("%w (retry-after: 10)", pachca.ErrRateLimited)
+	status, _ := request(h, "GET", "/Users", "")
+	c.Assert(status, Equals, 429)
+
+	client.fail = fmt.Errorf("%w: (not_found) Not found", pachca.ErrNotFound)
+
+	for _, r := range [][2]string{
+		{"GET", "/Users/1"}, {"PUT", "/Users/1"}, {"PATCH", "/Users/1"}, {"DELETE", "/Users/1"},
+		{"GET", "/Groups/10"}, {"PUT", "/Groups/10"}, {"PATCH", "/Groups/10"}, {"DELETE", "/Groups/10"},
+	} {
+		status, _ = request(h, r[0], r[1], `{}`)
+		c.Assert(status, Equals, 404, Commentf("Request: %v", r))
+	}
+
+	c.Assert(errs, HasLen, 12)
+}
+
+func (s *SCIMSuite) TestErrors(c *C) {
+	_, err := New(nil, TOKEN)
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(newFakeClient(), "")
+	c.Assert(err, Equals, ErrEmptyToken)
+
+	var h *Handler
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/Users", nil))
+	c.Assert(w.Code, Equals, 500)
+
+	var u *User
+	c.Assert(u.Email(), Equals, "")
+	c.Assert(u.Phone(), Equals, "")
+	c.Assert((&User{Emails: []*MultiValue{nil, {Value: "a@b.c"}}}).Email(), Equals, "a@b.c")
+	c.Assert(formatTime(time.Time{}), Equals, "")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func request(h http.Handler, method, path, body string) (int, map[string]any) {
+	r := httptest.NewRequest(method, strings.ReplaceAll(path, " ", "+"), strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+TOKEN)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	data := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &data)
+
+	return w.Code, data
+}
+
+func newFakeClient() *fakeClient {
+	created := pachca.Date{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	return &fakeClient{
+		users: map[uint]*pachca.User{
+			1: {
+				ID: 1, Email: "john@domain.com", FirstName: "John", LastName: "Doe",
+				Department: "R&D", Tags: []string{"dev"}, CreatedAt: created,
+			},
+			2: {ID: 2, Email: "jane@domain.com", IsSuspended: true, CreatedAt: created},
+			3: {ID: 3, Email: "bot@domain.com", IsBot: true},
+		},
+		tags: map[uint]*pachca.Tag{
+			10: {ID: 10, Name: "dev"},
+			11: {ID: 11, Name: "qa"},
+		},
+		nextID: 100,
+	}
+}
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	var result pachca.Users
+
+	for _, id := range sortedKeys(c.users) {
+		u := c.users[id]
+
+		if len(searchQuery) == 0 || strings.Contains(strings.ToLower(u.Email), strings.ToLower(searchQuery[0])) {
+			result = append(result, u)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) GetUser(userID uint) (*pachca.User, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	user := c.users[userID]
+
+	if user == nil {
+		return nil, fmt.Errorf("%w: (not_found) User not found", pachca.ErrNotFound)
+	}
+
+	return user, nil
+}
+
+func (c *fakeClient) AddUser(user *pachca.UserRequest) (*pachca.User, error) {
+	u := &pachca.User{
+		ID: c.nextID, Email: user.Email, FirstName: user.FirstName, LastName: user.LastName,
+		Nickname: user.Nickname, Title: user.Title, Department: user.Department,
+		PhoneNumber: user.PhoneNumber, IsSuspended: user.IsSuspended, Tags: user.Tags,
+	}
+
+	c.users[u.ID] = u
+	c.nextID++
+
+	return u, nil
+}
+
+func (c *fakeClient) EditUser(userID uint, user *pachca.UserRequest) (*pachca.User, error) {
+	u := c.users[userID]
+
+	set := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+
+	set(&u.Email, user.Email)
+	set(&u.FirstName, user.FirstName)
+	set(&u.LastName, user.LastName)
+	set(&u.Nickname, user.Nickname)
+	set(&u.Title, user.Title)
+	set(&u.Department, user.Department)
+	set(&u.PhoneNumber, user.PhoneNumber)
+
+	fields := map[string]*string{
+		"first_name": &u.FirstName, "last_name": &u.LastName, "nickname": &u.Nickname,
+		"title": &u.Title, "department": &u.Department, "phone_number": &u.PhoneNumber,
+	}
+
+	for _, field := range user.ClearFields {
+		*fields[field] = ""
+	}
+
+	return u, nil
+}
+
+func (c *fakeClient) DeleteUser(userID uint) error {
+	c.deleted = append(c.deleted, userID)
+	return nil
+}
+
+func (c *fakeClient) SuspendUser(userID uint) error {
+	c.users[userID].IsSuspended = true
+	return nil
+}
+
+func (c *fakeClient) UnsuspendUser(userID uint) error {
+	c.users[userID].IsSuspended = false
+	return nil
+}
+
+func (c *fakeClient) SetUserTags(userID uint, tags []string) error {
+	c.users[userID].Tags = tags
+	return nil
+}
+
+func (c *fakeClient) GetTags(names ...string) (pachca.Tags, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	var result pachca.Tags
+
+	for _, id := range sortedKeys(c.tags) {
+		result = append(result, c.tags[id])
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) GetTag(groupTagID uint) (*pachca.Tag, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	tag := c.tags[groupTagID]
+
+	if tag == nil {
+		return nil, fmt.Errorf("%w: (not_found) Tag not found", pachca.ErrNotFound)
+	}
+
+	return tag, nil
+}
+
+func (c *fakeClient) GetTagUsers(groupTagID uint) (pachca.Users, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	c.tagUsersCalls++
+
+	var result pachca.Users
+
+	for _, id := range sortedKeys(c.users) {
+		if c.users[id].HasTag(c.tags[groupTagID].Name) {
+			result = append(result, c.users[id])
+		}
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) AddTag(groupTagName string) (*pachca.Tag, error) {
+	t := &pachca.Tag{ID: c.nextID, Name: groupTagName}
+
+	c.tags[t.ID] = t
+	c.nextID++
+
+	return t, nil
+}
+
+func (c *fakeClient) EditTag(groupTagID uint, groupTagName string) (*pachca.Tag, error) {
+	t := c.tags[groupTagID]
+
+	for _, u := range c.users {
+		for i, tag := range u.Tags {
+			if tag == t.Name {
+				u.Tags[i] = groupTagName
+			}
+		}
+	}
+
+	t.Name = groupTagName
+
+	return t, nil
+}
+
+func (c *fakeClient) DeleteTag(groupTagID uint) error {
+	delete(c.tags, groupTagID)
+	return nil
+}
+
+func sortedKeys[T any](m map[uint]T) []uint {
+	var result []uint
+
+	for k := range m {
+		result = append(result, k)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,335 @@
+package slackimport
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	FILE_USERS    = "users.json"
+	FILE_CHANNELS = "channels.json"
+	FILE_GROUPS   = "groups.json"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Archive is Slack export archive
+type Archive struct {
+	Users    []*User
+	Channels []*Channel
+
+	fs     fs.FS
+	closer io.Closer
+}
+
+// User is Slack user
+type User struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	RealName  string       `json:"real_name"`
+	Profile   *UserProfile `json:"profile"`
+	IsBot     bool         `json:"is_bot"`
+	IsDeleted bool         `json:"deleted"`
+}
+
+// UserProfile is Slack user profile
+type UserProfile struct {
+	Email       string `json:"email"`
+	RealName    string `json:"real_name"`
+	DisplayName string `json:"display_name"`
+	Image72     string `json:"image_72"`
+	Image192    string `json:"image_192"`
+}
+
+// Channel is Slack channel
+type Channel struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Created    int64    `json:"created"`
+	Members    []string `json:"members"`
+	Purpose    *Topic   `json:"purpose"`
+	IsArchived bool     `json:"is_archived"`
+	IsPrivate  bool     `json:"is_private"`
+}
+
+// Topic is channel topic or purpose
+type Topic struct {
+	Value string `json:"value"`
+}
+
+// Message is Slack message
+type Message struct {
+	Type        string       `json:"type"`
+	Subtype     string       `json:"subtype"`
+	User        string       `json:"user"`
+	Username    string       `json:"username"`
+	Text        string       `json:"text"`
+	TS          string       `json:"ts"`
+	ThreadTS    string       `json:"thread_ts"`
+	Files       []*File      `json:"files"`
+	UserProfile *UserProfile `json:"user_profile"`
+}
+
+// File is Slack file
+type File struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Title              string `json:"title"`
+	Mode               string `json:"mode"`
+	URLPrivate         string `json:"url_private"`
+	URLPrivateDownload string `json:"url_private_download"`
+	Permalink          string `json:"permalink"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Open opens Slack export archive. Path can point to ZIP file or directory
+// with unpacked archive.
+func Open(file string) (*Archive, error) {
+	info, err := os.Stat(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't open archive: %w", err)
+	}
+
+	a := &Archive{}
+
+	if info.IsDir() {
+		a.fs = os.DirFS(file)
+	} else {
+		zr, err := zip.OpenReader(file)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't open archive: %w", err)
+		}
+
+		a.fs, a.closer = zr, zr
+	}
+
+	err = a.load()
+
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Close closes archive
+func (a *Archive) Close() error {
+	if a == nil || a.closer == nil {
+		return nil
+	}
+
+	return a.closer.Close()
+}
+
+// User returns user with given ID
+func (a *Archive) User(id string) *User {
+	if a == nil {
+		return nil
+	}
+
+	for _, u := range a.Users {
+		if u.ID == id {
+			return u
+		}
+	}
+
+	return nil
+}
+
+// Channel returns channel with given ID
+func (a *Archive) Channel(id string) *Channel {
+	if a == nil {
+		return nil
+	}
+
+	for _, c := range a.Channels {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Messages returns all messages from given channel sorted by date
+func (a *Archive) Messages(channel *Channel) ([]*Message, error) {
+	if a == nil || channel == nil {
+		return nil, nil
+	}
+
+	files, err := fs.Glob(a.fs, path.Join(channel.Name, "*.json"))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't list messages of channel %q: %w", channel.Name, err)
+	}
+
+	var result []*Message
+
+	for _, file := range files {
+		var messages []*Message
+
+		err = readJSON(a.fs, file, &messages)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, messages...)
+	}
+
+	slices.SortStableFunc(result, func(m1, m2 *Message) int {
+		return m1.Date().Compare(m2.Date())
+	})
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Email returns user email
+func (u *User) Email() string {
+	if u == nil || u.Profile == nil {
+		return ""
+	}
+
+	return u.Profile.Email
+}
+
+// DisplayName returns name of user
+func (u *User) DisplayName() string {
+	switch {
+	case u == nil:
+		return ""
+	case u.Profile != nil && u.Profile.RealName != "":
+		return u.Profile.RealName
+	case u.RealName != "":
+		return u.RealName
+	case u.Profile != nil && u.Profile.DisplayName != "":
+		return u.Profile.DisplayName
+	}
+
+	return u.Name
+}
+
+// AvatarURL returns URL of user avatar
+func (u *User) AvatarURL() string {
+	switch {
+	case u == nil || u.Profile == nil:
+		return ""
+	case u.Profile.Image192 != "":
+		return u.Profile.Image192
+	}
+
+	return u.Profile.Image72
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Date returns message date
+func (m *Message) Date() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+
+	return parseTS(m.TS)
+}
+
+// IsReply returns true if message is a reply in thread
+func (m *Message) IsReply() bool {
+	return m != nil && m.ThreadTS != "" && m.ThreadTS != m.TS
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// load reads users and channels info
+func (a *Archive) load() error {
+	err := readJSON(a.fs, FILE_USERS, &a.Users)
+
+	if err != nil {
+		return err
+	}
+
+	err = readJSON(a.fs, FILE_CHANNELS, &a.Channels)
+
+	if err != nil {
+		return err
+	}
+
+	var groups []*Channel
+
+	err = readJSON(a.fs, FILE_GROUPS, &groups)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, g := range groups {
+		g.IsPrivate = true
+	}
+
+	a.Channels = append(a.Channels, groups...)
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readJSON reads and decodes JSON file from archive
+func readJSON(fsys fs.FS, file string, v any) error {
+	data, err := fs.ReadFile(fsys, file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+
+		return fmt.Errorf("can't read %s: %w", file, err)
+	}
+
+	err = json.Unmarshal(data, v)
+
+	if err != nil {
+		return fmt.Errorf("can't decode %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// parseTS parses Slack timestamp
+func parseTS(ts string) time.Time {
+	sec, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	frac = (frac + "000000")[:6]
+	us, _ := strconv.ParseInt(frac, 10, 64)
+
+	return time.Unix(s, us*1000)
+}
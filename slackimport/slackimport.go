@@ -0,0 +1,611 @@
+package slackimport
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/req"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	PROGRESS_CHAT    = "chat"
+	PROGRESS_MESSAGE = "message"
+	PROGRESS_THREAD  = "thread"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by importer
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+	AddChat(chat *pachca.ChatRequest) (*pachca.Chat, error)
+	AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error)
+	AddThreadMessage(messageID uint, message *pachca.MessageRequest) (*pachca.Thread, *pachca.Message, error)
+	UploadFile(file string) (*pachca.File, error)
+	Engine() *req.Engine
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Importer imports Slack export archive into Pachca
+type Importer struct {
+	Channels     []string // Names of channels to import (all if empty)
+	SkipArchived bool     // Skip archived channels
+	ProgressFile string   // Path to file with import progress
+	SlackToken   string   // Slack token for downloading files (files added as links if empty)
+	DateLayout   string   // Layout of original message date added to content (not added if empty)
+	DryRun       bool     // Only calculate what would be imported
+
+	client Client
+}
+
+// Report contains import results
+type Report struct {
+	Chats          int      // Number of created chats
+	Messages       int      // Number of posted messages
+	ThreadMessages int      // Number of posted thread messages
+	Files          int      // Number of uploaded files
+	Skipped        int      // Number of skipped service or empty messages
+	UnmappedUsers  []string // Slack users without Pachca account
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// session is import session
+type session struct {
+	*Importer
+
+	archive  *Archive
+	progress *progress
+	report   *Report
+	users    map[string]*pachca.User
+}
+
+// progressRecord is import progress log record
+type progressRecord struct {
+	Type string `json:"t"`
+	Key  string `json:"k"`
+	ID   uint   `json:"id"`
+}
+
+// progress contains info about imported entities
+type progress struct {
+	chats    map[string]uint
+	messages map[string]uint
+	threads  map[string]uint
+
+	fd *os.File
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient   = errors.New("client is nil")
+	ErrNilImporter = errors.New("importer is nil")
+	ErrNilArchive  = errors.New("archive is nil")
+)
+
+// supportedSubtypes is a list of message subtypes which contain user content
+var supportedSubtypes = []string{
+	"", "bot_message", "file_share", "me_message", "thread_broadcast",
+}
+
+// entityRegex is regex for Slack special entities (mentions and links)
+var entityRegex = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new importer
+func New(client Client) (*Importer, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Importer{client: client}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Import imports channels with messages from given archive. If progress file
+// is set, import can be safely restarted after failure, already imported chats
+// and messages will be skipped.
+func (i *Importer) Import(archive *Archive) (*Report, error) {
+	switch {
+	case i == nil:
+		return nil, ErrNilImporter
+	case archive == nil:
+		return nil, ErrNilArchive
+	}
+
+	users, err := i.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch Pachca users: %w", err)
+	}
+
+	p, err := openProgress(i.ProgressFile, i.DryRun)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer p.Close()
+
+	s := &session{
+		Importer: i,
+		archive:  archive,
+		progress: p,
+		report:   &Report{},
+		users:    map[string]*pachca.User{},
+	}
+
+	for _, u := range archive.Users {
+		user := users.Find(u.Email())
+
+		if u.Email() != "" && user != nil {
+			s.users[u.ID] = user
+		} else if !u.IsBot && u.ID != "USLACKBOT" {
+			s.report.UnmappedUsers = append(s.report.UnmappedUsers, u.Name)
+		}
+	}
+
+	slices.Sort(s.report.UnmappedUsers)
+
+	for _, channel := range archive.Channels {
+		if !i.isChannelAllowed(channel) {
+			continue
+		}
+
+		err = s.importChannel(channel)
+
+		if err != nil {
+			return s.report, err
+		}
+	}
+
+	return s.report, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// importChannel imports channel and its messages
+func (s *session) importChannel(channel *Channel) error {
+	chatID := s.progress.chats[channel.ID]
+
+	if chatID == 0 {
+		chatReq := &pachca.ChatRequest{
+			Name:     channel.Name,
+			IsPublic: !channel.IsPrivate,
+		}
+
+		for _, id := range channel.Members {
+			if s.users[id] != nil {
+				chatReq.Members = append(chatReq.Members, s.users[id].ID)
+			}
+		}
+
+		s.report.Chats++
+
+		if !s.DryRun {
+			chat, err := s.client.AddChat(chatReq)
+
+			if err != nil {
+				return err
+			}
+
+			chatID = chat.ID
+
+			err = s.progress.Add(PROGRESS_CHAT, channel.ID, chatID)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	messages, err := s.archive.Messages(channel)
+
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		err = s.importMessage(channel, chatID, msg)
+
+		if err != nil {
+			return fmt.Errorf("can't import message %s from channel %q: %w", msg.TS, channel.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// importMessage posts message to chat or thread
+func (s *session) importMessage(channel *Channel, chatID uint, msg *Message) error {
+	key := channel.ID + "/" + msg.TS
+
+	if s.progress.messages[key] != 0 {
+		return nil
+	}
+
+	if msg.Type != "message" || !slices.Contains(supportedSubtypes, msg.Subtype) {
+		s.report.Skipped++
+		return nil
+	}
+
+	msgReq := &pachca.MessageRequest{
+		EntityType:         pachca.ENTITY_TYPE_DISCUSSION,
+		EntityID:           chatID,
+		Content:            s.convertText(msg.Text),
+		SkipInviteMentions: true,
+	}
+
+	msgReq.DisplayName, msgReq.DisplayAvatarURL = s.getAuthor(msg)
+
+	err := s.attachFiles(msgReq, msg)
+
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(msgReq.Content) == "" && len(msgReq.Files) == 0 {
+		s.report.Skipped++
+		return nil
+	}
+
+	if s.DateLayout != "" {
+		msgReq.Content = msg.Date().Format(s.DateLayout) + "\n" + msgReq.Content
+	}
+
+	if msg.IsReply() {
+		s.report.ThreadMessages++
+	} else {
+		s.report.Messages++
+	}
+
+	if s.DryRun {
+		return nil
+	}
+
+	var message *pachca.Message
+
+	parentKey := channel.ID + "/" + msg.ThreadTS
+	parentID, threadID := s.progress.messages[parentKey], s.progress.threads[parentKey]
+
+	switch {
+	case !msg.IsReply() || parentID == 0:
+		message, err = s.client.AddMessage(msgReq)
+
+	case threadID != 0:
+		msgReq.EntityType, msgReq.EntityID = pachca.ENTITY_TYPE_THREAD, threadID
+		message, err = s.client.AddMessage(msgReq)
+
+	default:
+		var thread *pachca.Thread
+
+		thread, message, err = s.client.AddThreadMessage(parentID, msgReq)
+
+		if err == nil {
+			err = s.progress.Add(PROGRESS_THREAD, parentKey, thread.ID)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.progress.Add(PROGRESS_MESSAGE, key, message.ID)
+}
+
+// attachFiles uploads message files or adds links to them
+func (s *session) attachFiles(msgReq *pachca.MessageRequest, msg *Message) error {
+	for _, f := range msg.Files {
+		if f.Mode == "tombstone" || f.Mode == "hidden_by_limit" {
+			continue
+		}
+
+		if s.SlackToken == "" || s.DryRun || f.URLPrivateDownload == "" {
+			link := f.Permalink
+
+			if link == "" {
+				link = f.URLPrivate
+			}
+
+			msgReq.Content += fmt.Sprintf("\n📎 [%s](%s)", getFileName(f), link)
+			continue
+		}
+
+		file, err := s.uploadFile(f)
+
+		if err != nil {
+			return err
+		}
+
+		msgReq.Files = append(msgReq.Files, file)
+		s.report.Files++
+	}
+
+	return nil
+}
+
+// uploadFile downloads file from Slack and uploads it to Pachca
+func (s *session) uploadFile(f *File) (*pachca.File, error) {
+	resp, err := s.client.Engine().Get(req.Request{
+		URL:         f.URLPrivateDownload,
+		Auth:        req.AuthBearer{Token: s.SlackToken},
+		AutoDiscard: true,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't download file %q: %w", f.Name, err)
+	}
+
+	if resp.StatusCode != req.STATUS_OK {
+		return nil, fmt.Errorf("can't download file %q: server returned status code %d", f.Name, resp.StatusCode)
+	}
+
+	dir, err := os.MkdirTemp("", "slackimport-")
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create temporary directory: %w", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, filepath.Base(getFileName(f)))
+	err = resp.Save(file, 0600)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save file %q: %w", f.Name, err)
+	}
+
+	return s.client.UploadFile(file)
+}
+
+// getAuthor returns name and avatar URL of message author
+func (s *session) getAuthor(msg *Message) (string, string) {
+	user := s.users[msg.User]
+
+	if user != nil {
+		name := user.FullName()
+
+		if name == "" {
+			name = user.Nickname
+		}
+
+		return name, user.ImageURL
+	}
+
+	su := s.archive.User(msg.User)
+
+	if su == nil && msg.UserProfile != nil {
+		su = &User{Name: msg.User, Profile: msg.UserProfile}
+	}
+
+	if su != nil {
+		return su.DisplayName(), su.AvatarURL()
+	}
+
+	return msg.Username, ""
+}
+
+// convertText converts Slack markup to Pachca markdown
+func (s *session) convertText(text string) string {
+	text = entityRegex.ReplaceAllStringFunc(text, func(m string) string {
+		sm := entityRegex.FindStringSubmatch(m)
+		target, label := sm[1], sm[2]
+
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if user := s.users[target[1:]]; user != nil && user.Nickname != "" {
+				return "@" + user.Nickname
+			}
+
+			if label != "" {
+				return "@" + label
+			}
+
+			if su := s.archive.User(target[1:]); su != nil {
+				return "@" + su.Name
+			}
+
+			return m
+
+		case strings.HasPrefix(target, "#"):
+			if label == "" {
+				if ch := s.archive.Channel(target[1:]); ch != nil {
+					label = ch.Name
+				}
+			}
+
+			return "#" + label
+
+		case target == "!here", target == "!channel", target == "!everyone":
+			return "@all"
+
+		case strings.HasPrefix(target, "!"):
+			return label
+
+		case strings.HasPrefix(target, "mailto:"):
+			return strings.TrimPrefix(target, "mailto:")
+
+		case label != "" && label != target:
+			return "[" + label + "](" + target + ")"
+		}
+
+		return target
+	})
+
+	return html.UnescapeString(text)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isChannelAllowed returns true if channel must be imported
+func (i *Importer) isChannelAllowed(channel *Channel) bool {
+	switch {
+	case channel.IsArchived && i.SkipArchived:
+		return false
+	case len(i.Channels) == 0:
+		return true
+	}
+
+	return slices.Contains(i.Channels, channel.Name)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// openProgress reads import progress from given file and opens it for writing
+func openProgress(file string, readOnly bool) (*progress, error) {
+	p := &progress{
+		chats:    map[string]uint{},
+		messages: map[string]uint{},
+		threads:  map[string]uint{},
+	}
+
+	if file == "" {
+		return p, nil
+	}
+
+	flags := os.O_CREATE | os.O_RDWR | os.O_APPEND
+
+	if readOnly {
+		flags = os.O_RDONLY
+	}
+
+	fd, err := os.OpenFile(file, flags, 0640)
+
+	if err != nil {
+		if readOnly && os.IsNotExist(err) {
+			return p, nil
+		}
+
+		return nil, fmt.Errorf("can't open progress file: %w", err)
+	}
+
+	var offset int64
+
+	br := bufio.NewReader(fd)
+
+	for {
+		line, err := br.ReadBytes('\n')
+
+		// Last line without line break is a record which was partially
+		// written before crash, so we drop it
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			fd.Close()
+			return nil, fmt.Errorf("can't read progress file: %w", err)
+		}
+
+		offset += int64(len(line))
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		r := &progressRecord{}
+		err = json.Unmarshal(line, r)
+
+		if err != nil {
+			fd.Close()
+			return nil, fmt.Errorf("can't decode progress file: %w", err)
+		}
+
+		p.set(r.Type, r.Key, r.ID)
+	}
+
+	if !readOnly {
+		err = fd.Truncate(offset)
+
+		if err != nil {
+			fd.Close()
+			return nil, fmt.Errorf("can't restore progress file state: %w", err)
+		}
+	}
+
+	if readOnly {
+		fd.Close()
+	} else {
+		p.fd = fd
+	}
+
+	return p, nil
+}
+
+// Add adds info about imported entity
+func (p *progress) Add(typ, key string, id uint) error {
+	p.set(typ, key, id)
+
+	if p.fd == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(&progressRecord{typ, key, id})
+	_, err := p.fd.Write(append(data, '\n'))
+
+	if err != nil {
+		return fmt.Errorf("can't save progress: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes progress file
+func (p *progress) Close() error {
+	if p.fd == nil {
+		return nil
+	}
+
+	return p.fd.Close()
+}
+
+// set sets entity ID
+func (p *progress) set(typ, key string, id uint) {
+	switch typ {
+	case PROGRESS_CHAT:
+		p.chats[key] = id
+	case PROGRESS_MESSAGE:
+		p.messages[key] = id
+	case PROGRESS_THREAD:
+		p.threads[key] = id
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getFileName returns name of file
+func getFileName(f *File) string {
+	switch {
+	case f.Name != "":
+		return f.Name
+	case f.Title != "":
+		return f.Title
+	}
+
+	return f.ID
+}
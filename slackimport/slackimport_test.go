@@ -0,0 +1,409 @@
+package slackimport
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/essentialkaos/ek/v14/req"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var testFiles = map[string]string{
+	FILE_USERS: `[
+		{"id":"U1","name":"john","profile":{"email":"JOHN@domain.com","real_name":"John Doe"}},
+		{"id":"U2","name":"bob","profile":{"email":"bob@other.com","real_name":"Bob Smith","image_192":"https://a/bob.png"}},
+		{"id":"B1","name":"jira","is_bot":true}
+	]`,
+	FILE_CHANNELS: `[
+		{"id":"C1","name":"general","members":["U1","U2"]},
+		{"id":"C2","name":"old","members":["U1"],"is_archived":true}
+	]`,
+	FILE_GROUPS: `[{"id":"G1","name":"secret","members":["U1"]}]`,
+	"general/2024-01-02.json": `[
+		{"type":"message","user":"U1","text":"Reply &lt;2&gt;","ts":"1704200000.000200","thread_ts":"1704100000.000100"},
+		{"type":"message","subtype":"file_share","user":"U2","text":"","ts":"1704200001.000100",
+		 "files":[{"id":"F1","name":"report.txt","url_private_download":"https://files.slack.com/report.txt","permalink":"https://slack.com/F1"}]}
+	]`,
+	"general/2024-01-01.json": `[
+		{"type":"message","subtype":"channel_join","user":"U1","text":"joined","ts":"1704000000.000100"},
+		{"type":"message","user":"U2","text":"Hi <@U1>, see <#C2> and <https://kaos.sh|site> <!here>","ts":"1704100000.000100","thread_ts":"1704100000.000100"},
+		{"type":"message","user":"U1","text":"Reply 1","ts":"1704100500.000100","thread_ts":"1704100000.000100"},
+		{"type":"message","subtype":"bot_message","username":"Jira","text":"<mailto:a@b.c|a@b.c> <!subteam^S1|@devs>","ts":"1704100600.000100"}
+	]`,
+	"old/2024-01-01.json":    `[{"type":"message","user":"U1","text":"Old","ts":"1704000000.000100"}]`,
+	"secret/2024-01-01.json": `[{"type":"message","user":"U1","text":"Secret","ts":"1704000000.000100"}]`,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type SlackImportSuite struct {
+	srv *httptest.Server
+}
+
+type fakeClient struct {
+	engine   *req.Engine
+	chats    []*pachca.ChatRequest
+	messages []*pachca.MessageRequest
+	threads  []uint
+	uploads  []string
+	failAt   int
+}
+
+type rewriteTransport struct {
+	target string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&SlackImportSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *SlackImportSuite) SetUpSuite(c *C) {
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			w.WriteHeader(403)
+			return
+		}
+
+		w.Write([]byte("REPORT"))
+	}))
+}
+
+func (s *SlackImportSuite) TearDownSuite(c *C) {
+	s.srv.Close()
+}
+
+func (s *SlackImportSuite) TestArchive(c *C) {
+	_, err := Open("/_unknown_")
+	c.Assert(err, NotNil)
+
+	dir := c.MkDir()
+	_, err = Open(dir)
+	c.Assert(err, NotNil)
+
+	a, err := Open(createArchive(c, true))
+	c.Assert(err, IsNil)
+	defer a.Close()
+
+	c.Assert(a.Users, HasLen, 3)
+	c.Assert(a.Channels, HasLen, 3)
+	c.Assert(a.Channels[2].IsPrivate, Equals, true)
+	c.Assert(a.User("U2").DisplayName(), Equals, "Bob Smith")
+	c.Assert(a.User("U2").AvatarURL(), Equals, "https://a/bob.png")
+	c.Assert(a.User("B1").DisplayName(), Equals, "jira")
+	c.Assert(a.User("B1").AvatarURL(), Equals, "")
+	c.Assert(a.User("U0"), IsNil)
+	c.Assert(a.Channel("C0"), IsNil)
+
+	msgs, err := a.Messages(a.Channel("C1"))
+	c.Assert(err, IsNil)
+	c.Assert(msgs, HasLen, 6)
+	c.Assert(msgs[0].Subtype, Equals, "channel_join")
+	c.Assert(msgs[1].IsReply(), Equals, false)
+	c.Assert(msgs[2].IsReply(), Equals, true)
+	c.Assert(msgs[5].Date().Unix(), Equals, int64(1704200001))
+
+	var na *Archive
+	c.Assert(na.Close(), IsNil)
+	c.Assert(na.User("U1"), IsNil)
+	c.Assert(na.Channel("C1"), IsNil)
+
+	msgs, err = na.Messages(nil)
+	c.Assert(err, IsNil)
+	c.Assert(msgs, IsNil)
+
+	var nu *User
+	c.Assert(nu.Email(), Equals, "")
+	c.Assert(nu.DisplayName(), Equals, "")
+
+	var nm *Message
+	c.Assert(nm.Date().IsZero(), Equals, true)
+	c.Assert(parseTS("abc").IsZero(), Equals, true)
+}
+
+func (s *SlackImportSuite) TestDryRun(c *C) {
+	a, err := Open(createArchive(c, false))
+	c.Assert(err, IsNil)
+
+	client := &fakeClient{}
+	imp, err := New(client)
+	c.Assert(err, IsNil)
+
+	imp.DryRun = true
+	imp.SlackToken = "xoxb-test"
+	imp.ProgressFile = c.MkDir() + "/progress.log"
+
+	report, err := imp.Import(a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Chats, Equals, 3)
+	c.Assert(report.Messages, Equals, 5)
+	c.Assert(report.ThreadMessages, Equals, 2)
+	c.Assert(report.Files, Equals, 0)
+	c.Assert(report.Skipped, Equals, 1)
+	c.Assert(report.UnmappedUsers, DeepEquals, []string{"bob"})
+	c.Assert(client.chats, HasLen, 0)
+	c.Assert(client.messages, HasLen, 0)
+
+	_, err = os.Stat(imp.ProgressFile)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *SlackImportSuite) TestImport(c *C) {
+	a, err := Open(createArchive(c, false))
+	c.Assert(err, IsNil)
+
+	client := &fakeClient{failAt: 4, engine: newEngine(s.srv)}
+	imp, err := New(client)
+	c.Assert(err, IsNil)
+
+	imp.SkipArchived = true
+	imp.SlackToken = "xoxb-test"
+	imp.DateLayout = "2006-01-02"
+	imp.ProgressFile = c.MkDir() + "/progress.log"
+
+	_, err = imp.Import(a)
+	c.Assert(err, NotNil)
+	c.Assert(client.messages, HasLen, 3)
+
+	client.failAt = 0
+
+	report, err := imp.Import(a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Chats, Equals, 1)
+	c.Assert(report.Messages, Equals, 2)
+	c.Assert(report.ThreadMessages, Equals, 1)
+	c.Assert(report.Files, Equals, 1)
+	c.Assert(report.Skipped, Equals, 1)
+
+	c.Assert(client.chats, HasLen, 2)
+	c.Assert(client.chats[0].Name, Equals, "general")
+	c.Assert(client.chats[0].Members, DeepEquals, []uint{1})
+	c.Assert(client.chats[0].IsPublic, Equals, true)
+	c.Assert(client.chats[1].IsPublic, Equals, false)
+
+	m := client.messages
+	c.Assert(m, HasLen, 6)
+	c.Assert(m[0].Content, Matches, `\d{4}-\d{2}-\d{2}\nHi @john, see #old and \[site\]\(https://kaos.sh\) @all`)
+	c.Assert(m[0].DisplayName, Equals, "Bob Smith")
+	c.Assert(m[0].DisplayAvatarURL, Equals, "https://a/bob.png")
+	c.Assert(m[0].EntityID, Equals, uint(100))
+	c.Assert(m[1].DisplayName, Equals, "John Doe")
+	c.Assert(m[1].DisplayAvatarURL, Equals, "https://p/john.png")
+	c.Assert(m[2].Content, Matches, `.*\na@b.c @devs`)
+	c.Assert(m[2].DisplayName, Equals, "Jira")
+	c.Assert(m[3].Content, Matches, `.*\nReply <2>`)
+	c.Assert(m[3].EntityType, Equals, pachca.ENTITY_TYPE_THREAD)
+	c.Assert(m[3].EntityID, Equals, uint(1000))
+	c.Assert(m[4].Files, HasLen, 1)
+	c.Assert(m[4].Files[0].Key, Equals, "uploads/report.txt")
+	c.Assert(m[5].Content, Matches, `.*\nSecret`)
+	c.Assert(client.threads, DeepEquals, []uint{1})
+
+	// Everything is imported, nothing to do
+	report, err = imp.Import(a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Chats+report.Messages+report.ThreadMessages, Equals, 0)
+	c.Assert(client.messages, HasLen, 6)
+}
+
+func (s *SlackImportSuite) TestFilters(c *C) {
+	a, err := Open(createArchive(c, false))
+	c.Assert(err, IsNil)
+
+	client := &fakeClient{engine: newEngine(s.srv)}
+	imp, _ := New(client)
+	imp.Channels = []string{"old"}
+
+	report, err := imp.Import(a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Chats, Equals, 1)
+	c.Assert(report.Messages, Equals, 1)
+
+	client = &fakeClient{engine: newEngine(s.srv)}
+	imp, _ = New(client)
+	imp.Channels = []string{"general"}
+
+	report, err = imp.Import(a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Files, Equals, 0)
+	c.Assert(client.messages[4].Content, Equals, "\n📎 [report.txt](https://slack.com/F1)")
+
+	imp.SlackToken = "xoxb-wrong"
+	_, err = imp.Import(a)
+	c.Assert(err, ErrorMatches, `.*server returned status code 403`)
+}
+
+func (s *SlackImportSuite) TestTruncatedProgress(c *C) {
+	file := c.MkDir() + "/progress.log"
+
+	os.WriteFile(file, []byte(
+		`{"t":"chat","k":"C1","id":10}`+"\n"+
+			`{"t":"message","k":"C1/1","id":20}`+"\n"+
+			`{"t":"message","k":"C1/2","i`,
+	), 0644)
+
+	p, err := openProgress(file, true)
+	c.Assert(err, IsNil)
+	c.Assert(p.chats, DeepEquals, map[string]uint{"C1": 10})
+	c.Assert(p.messages, DeepEquals, map[string]uint{"C1/1": 20})
+
+	p, err = openProgress(file, false)
+	c.Assert(err, IsNil)
+	c.Assert(p.messages, HasLen, 1)
+	c.Assert(p.Add(PROGRESS_MESSAGE, "C1/2", 21), IsNil)
+	c.Assert(p.Close(), IsNil)
+
+	p, err = openProgress(file, true)
+	c.Assert(err, IsNil)
+	c.Assert(p.messages, DeepEquals, map[string]uint{"C1/1": 20, "C1/2": 21})
+}
+
+func (s *SlackImportSuite) TestErrors(c *C) {
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	var ni *Importer
+	_, err = ni.Import(&Archive{})
+	c.Assert(err, Equals, ErrNilImporter)
+
+	imp, _ := New(&fakeClient{})
+	_, err = imp.Import(nil)
+	c.Assert(err, Equals, ErrNilArchive)
+
+	imp.ProgressFile = c.MkDir() + "/progress.log"
+	os.WriteFile(imp.ProgressFile, []byte("{broken}\n"), 0644)
+	_, err = imp.Import(&Archive{})
+	c.Assert(err, ErrorMatches, "can't decode progress file: .*")
+
+	imp.ProgressFile = "/_unknown_/progress.log"
+	_, err = imp.Import(&Archive{})
+	c.Assert(err, ErrorMatches, "can't open progress file: .*")
+
+	dir := createArchive(c, true)
+	os.WriteFile(filepath.Join(dir, "general", "broken.json"), []byte("{"), 0644)
+
+	a, err := Open(dir)
+	c.Assert(err, IsNil)
+
+	imp.ProgressFile = ""
+	_, err = imp.Import(a)
+	c.Assert(err, ErrorMatches, "can't decode general/broken.json: .*")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	return pachca.Users{
+		{ID: 1, Email: "john@domain.com", Nickname: "john", FirstName: "John", LastName: "Doe", ImageURL: "https://p/john.png"},
+		{ID: 3, Email: "bob@domain.com", Nickname: "bob"},
+	}, nil
+}
+
+func (c *fakeClient) AddChat(chat *pachca.ChatRequest) (*pachca.Chat, error) {
+	c.chats = append(c.chats, chat)
+	return &pachca.Chat{ID: uint(len(c.chats) * 100), Name: chat.Name}, nil
+}
+
+func (c *fakeClient) AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error) {
+	if c.failAt != 0 && len(c.messages)+1 == c.failAt {
+		return nil, fmt.Errorf("API error")
+	}
+
+	c.messages = append(c.messages, message)
+
+	return &pachca.Message{ID: uint(len(c.messages))}, nil
+}
+
+func (c *fakeClient) AddThreadMessage(messageID uint, message *pachca.MessageRequest) (*pachca.Thread, *pachca.Message, error) {
+	c.threads = append(c.threads, messageID)
+	message.EntityType, message.EntityID = pachca.ENTITY_TYPE_THREAD, 1000
+
+	msg, err := c.AddMessage(message)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &pachca.Thread{ID: 1000}, msg, nil
+}
+
+func (c *fakeClient) UploadFile(file string) (*pachca.File, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil || string(data) != "REPORT" {
+		return nil, fmt.Errorf("invalid file")
+	}
+
+	c.uploads = append(c.uploads, file)
+
+	return &pachca.File{Key: "uploads/" + filepath.Base(file), Name: filepath.Base(file)}, nil
+}
+
+func (c *fakeClient) Engine() *req.Engine {
+	return c.engine
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = "http", t.target
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// createArchive creates test archive and returns path to it
+func createArchive(c *C, unpacked bool) string {
+	dir := c.MkDir()
+
+	if unpacked {
+		for name, data := range testFiles {
+			os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+			c.Assert(os.WriteFile(filepath.Join(dir, name), []byte(data), 0644), IsNil)
+		}
+
+		return dir
+	}
+
+	file := filepath.Join(dir, "export.zip")
+	fd, err := os.Create(file)
+	c.Assert(err, IsNil)
+
+	zw := zip.NewWriter(fd)
+
+	for name, data := range testFiles {
+		w, err := zw.Create(name)
+		c.Assert(err, IsNil)
+		w.Write([]byte(data))
+	}
+
+	c.Assert(zw.Close(), IsNil)
+	c.Assert(fd.Close(), IsNil)
+
+	return file
+}
+
+// newEngine creates new HTTP engine which sends all requests to test server
+func newEngine(srv *httptest.Server) *req.Engine {
+	return &req.Engine{
+		Client: &http.Client{Transport: &rewriteTransport{srv.Listener.Addr().String()}},
+	}
+}
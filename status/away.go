@@ -0,0 +1,251 @@
+package status
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	DEFAULT_AWAY_EMOJI   = "🌴"
+	DEFAULT_AWAY_TITLE   = "Out of office"
+	DEFAULT_AWAY_MESSAGE = "I'm out of office until {until}"
+	DEFAULT_DATE_LAYOUT  = "2006-01-02"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// AwayClient is the subset of Pachca API client methods used by out-of-office
+// sync
+type AwayClient interface {
+	StatusClient
+
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// AwaySync keeps out-of-office statuses in sync with calendar events. Statuses
+// are identified by emoji and title, so statuses set manually by users are
+// never modified or removed.
+type AwaySync struct {
+	// Emoji is status emoji
+	Emoji string
+
+	// Title is status title
+	Title string
+
+	// AwayMessage is away message. Message can contain {until} (the last day of
+	// absence) and {summary} (event summary) placeholders.
+	AwayMessage string
+
+	// DateLayout is layout of date in away message
+	DateLayout string
+
+	// Location is time zone for dates in away message (UTC by default)
+	Location *time.Location
+
+	client AwayClient
+}
+
+// AwayReport contains info about sync results
+type AwayReport struct {
+	Set     []uint   // Users with set or updated status
+	Cleared []uint   // Users with removed status
+	Skipped []uint   // Users with manually set status
+	Unknown []string // Attendees emails without users
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrNilAwaySync = errors.New("away sync is nil")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewAwaySync creates new out-of-office sync
+func NewAwaySync(client AwayClient) (*AwaySync, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &AwaySync{
+		Emoji:       DEFAULT_AWAY_EMOJI,
+		Title:       DEFAULT_AWAY_TITLE,
+		AwayMessage: DEFAULT_AWAY_MESSAGE,
+		DateLayout:  DEFAULT_DATE_LAYOUT,
+		client:      client,
+	}, nil
+}
+
+// Sync sets statuses for users with events active at given time and removes
+// statuses of users without active events
+func (s *AwaySync) Sync(events Events, now time.Time) (*AwayReport, error) {
+	if s == nil {
+		return nil, ErrNilAwaySync
+	}
+
+	users, err := s.client.GetUsers()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users: %w", err)
+	}
+
+	report := &AwayReport{}
+	errs := errors.NewBundle()
+	known := map[string]bool{}
+
+	for _, user := range users {
+		if !user.IsActive() || user.IsBot || user.Email == "" {
+			continue
+		}
+
+		known[strings.ToLower(user.Email)] = true
+
+		err = s.syncUser(report, user, events.Active(user.Email, now), now)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't sync status of user %d: %w", user.ID, err))
+		}
+	}
+
+	for _, event := range events {
+		if !event.IsActive(now) {
+			continue
+		}
+
+		for _, email := range event.Attendees {
+			if !known[email] {
+				known[email] = true
+				report.Unknown = append(report.Unknown, email)
+			}
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return report, errs.Join()
+	}
+
+	return report, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// syncUser syncs status of single user
+func (s *AwaySync) syncUser(report *AwayReport, user *pachca.User, events Events, now time.Time) error {
+	template := &pachca.Status{Emoji: s.Emoji, Title: s.Title}
+
+	if len(events) == 0 {
+		if !IsSame(user.Status, template) {
+			return nil
+		}
+
+		err := ClearIfMatch(s.client, user.ID, template)
+
+		if err != nil {
+			return err
+		}
+
+		report.Cleared = append(report.Cleared, user.ID)
+
+		return nil
+	}
+
+	event := events[0]
+
+	for _, e := range events[1:] {
+		if e.End.After(event.End) {
+			event = e
+		}
+	}
+
+	current, err := s.client.GetStatus(user.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if current != nil && !current.ExpiresAt.IsZero() && !current.ExpiresAt.After(now) {
+		current = nil
+	}
+
+	desired := s.status(event)
+
+	switch {
+	case current == nil:
+		// Set new status
+	case !IsSame(current, desired):
+		report.Skipped = append(report.Skipped, user.ID)
+		return nil
+	case current.ExpiresAt.Equal(desired.ExpiresAt.Time) && current.IsAway &&
+		current.AwayMessageText() == desired.AwayMessageText():
+		return nil
+	}
+
+	_, err = s.client.UpdateStatus(user.ID, desired)
+
+	if err != nil {
+		return err
+	}
+
+	report.Set = append(report.Set, user.ID)
+
+	return nil
+}
+
+// status creates status for event
+func (s *AwaySync) status(event *Event) *pachca.Status {
+	status := &pachca.Status{
+		Emoji:     s.Emoji,
+		Title:     s.Title,
+		ExpiresAt: pachca.Date{Time: event.End.UTC()},
+		IsAway:    true,
+	}
+
+	if s.AwayMessage == "" {
+		return status
+	}
+
+	until := event.End.In(s.getLocation())
+
+	// End of all-day event is exclusive and has no time zone
+	if event.IsAllDay {
+		until = event.End.AddDate(0, 0, -1)
+	}
+
+	layout := s.DateLayout
+
+	if layout == "" {
+		layout = DEFAULT_DATE_LAYOUT
+	}
+
+	status.AwayMessage = &pachca.AwayMessage{
+		Text: strings.NewReplacer(
+			"{until}", until.Format(layout),
+			"{summary}", event.Summary,
+		).Replace(s.AwayMessage),
+	}
+
+	return status
+}
+
+// getLocation returns location for dates
+func (s *AwaySync) getLocation() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+
+	return s.Location
+}
@@ -0,0 +1,322 @@
+package status
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/req"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_ICS_SIZE is maximum size of fetched ICS feed
+const MAX_ICS_SIZE = 10 * 1024 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Event is calendar event
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Attendees   []string // Emails of attendees (or organizer if there are no attendees)
+	IsAllDay    bool
+	IsCancelled bool
+}
+
+// Events is a slice of events
+type Events []*Event
+
+// icsProperty is single content line of iCalendar data
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseICS parses VEVENT components from iCalendar data. Floating times (without
+// time zone) are parsed in given location (UTC if nil). Recurring events are
+// not expanded, only the first occurrence is used.
+func ParseICS(r io.Reader, loc *time.Location) (Events, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	props, err := readICSProperties(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result Events
+	var event *Event
+	var organizer string
+
+	for _, p := range props {
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VEVENT"):
+			event, organizer = &Event{}, ""
+			continue
+
+		case p.Name == "END" && strings.EqualFold(p.Value, "VEVENT"):
+			if event == nil {
+				continue
+			}
+
+			if len(event.Attendees) == 0 && organizer != "" {
+				event.Attendees = []string{organizer}
+			}
+
+			if event.End.IsZero() {
+				event.End = event.Start
+
+				if event.IsAllDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+
+			result = append(result, event)
+			event = nil
+			continue
+
+		case event == nil:
+			continue
+		}
+
+		switch p.Name {
+		case "UID":
+			event.UID = p.Value
+		case "SUMMARY":
+			event.Summary = unescapeICS(p.Value)
+		case "DESCRIPTION":
+			event.Description = unescapeICS(p.Value)
+		case "STATUS":
+			event.IsCancelled = strings.EqualFold(p.Value, "CANCELLED")
+		case "ATTENDEE":
+			if email := parseMailto(p.Value); email != "" {
+				event.Attendees = append(event.Attendees, email)
+			}
+		case "ORGANIZER":
+			organizer = parseMailto(p.Value)
+		case "DTSTART", "DTEND":
+			t, allDay, err := parseICSTime(p, loc)
+
+			if err != nil {
+				return nil, fmt.Errorf("can't parse %s of event %q: %w", p.Name, event.UID, err)
+			}
+
+			if p.Name == "DTSTART" {
+				event.Start, event.IsAllDay = t, allDay
+			} else {
+				event.End = t
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ReadICSFile reads events from iCalendar file
+func ReadICSFile(file string, loc *time.Location) (Events, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return ParseICS(fd, loc)
+}
+
+// FetchICS fetches and parses iCalendar feed
+func FetchICS(ctx context.Context, url string, loc *time.Location) (Events, error) {
+	resp, err := req.Global.Get(req.Request{
+		URL:         url,
+		Accept:      "text/calendar",
+		Ctx:         ctx,
+		AutoDiscard: true,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch calendar: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != req.STATUS_OK {
+		return nil, fmt.Errorf("can't fetch calendar: server returned status code %d", resp.StatusCode)
+	}
+
+	return ParseICS(io.LimitReader(resp.Body, MAX_ICS_SIZE), loc)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsActive returns true if event is active at given moment
+func (e *Event) IsActive(now time.Time) bool {
+	return e != nil && !e.IsCancelled && !now.Before(e.Start) && now.Before(e.End)
+}
+
+// HasAttendee returns true if event has attendee with given email
+func (e *Event) HasAttendee(email string) bool {
+	if e == nil || email == "" {
+		return false
+	}
+
+	for _, a := range e.Attendees {
+		if strings.EqualFold(a, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Active returns events which are active at given moment for attendee with
+// given email
+func (e Events) Active(email string, now time.Time) Events {
+	var result Events
+
+	for _, event := range e {
+		if event.IsActive(now) && event.HasAttendee(email) {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readICSProperties reads and unfolds content lines
+func readICSProperties(r io.Reader) ([]*icsProperty, error) {
+	var result []*icsProperty
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read calendar data: %w", err)
+	}
+
+	for _, line := range lines {
+		if p := parseICSLine(line); p != nil {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+// parseICSLine parses single content line
+func parseICSLine(line string) *icsProperty {
+	// Colon in quoted parameter value is not a value separator
+	sep, quoted := -1, false
+
+	for i, ch := range line {
+		if ch == '"' {
+			quoted = !quoted
+		} else if ch == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+
+	if sep <= 0 {
+		return nil
+	}
+
+	nameParams := strings.Split(line[:sep], ";")
+	p := &icsProperty{
+		Name:   strings.ToUpper(strings.TrimSpace(nameParams[0])),
+		Params: map[string]string{},
+		Value:  line[sep+1:],
+	}
+
+	for _, param := range nameParams[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return p
+}
+
+// parseICSTime parses DATE or DATE-TIME value
+func parseICSTime(p *icsProperty, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+
+	return t, false, err
+}
+
+// parseMailto extracts email from mailto URI
+func parseMailto(value string) string {
+	value = strings.TrimSpace(value)
+
+	if len(value) > 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+
+	if !strings.Contains(value, "@") {
+		return ""
+	}
+
+	return strings.ToLower(value)
+}
+
+// unescapeICS unescapes text value
+func unescapeICS(value string) string {
+	return strings.NewReplacer(
+		`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`,
+	).Replace(value)
+}
@@ -0,0 +1,663 @@
+package status
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"cmp"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	STATE_PENDING State = "pending" // Status is not applied yet
+	STATE_ACTIVE  State = "active"  // Status is applied
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// StatusClient is the subset of Pachca API client methods used for managing
+// statuses
+type StatusClient interface {
+	GetStatus(userID uint) (*pachca.Status, error)
+	UpdateStatus(userID uint, status *pachca.Status) (*pachca.Status, error)
+	DeleteStatus(userID uint) error
+}
+
+// Client is the subset of Pachca API client methods used by status scheduler
+type Client interface {
+	StatusClient
+
+	GetTags(names ...string) (pachca.Tags, error)
+	GetTagUsers(groupTagID uint) (pachca.Users, error)
+}
+
+// Store is storage for scheduled statuses
+type Store interface {
+	// Get returns entry with given ID or nil if there is no such entry
+	Get(id string) (*Entry, error)
+
+	// Set saves entry
+	Set(entry *Entry) error
+
+	// Delete removes entry with given ID
+	Delete(id string) error
+
+	// List returns all entries
+	List() ([]*Entry, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// State is state of scheduled status
+type State string
+
+// Entry is scheduled status
+type Entry struct {
+	ID string `json:"id"`
+
+	// UserID is ID of user (mutually exclusive with Tag)
+	UserID uint `json:"user_id,omitempty"`
+
+	// Tag is name of group tag (status is set for all users with this tag)
+	Tag string `json:"tag,omitempty"`
+
+	Emoji       string `json:"emoji"`
+	Title       string `json:"title"`
+	AwayMessage string `json:"away_message,omitempty"`
+	IsAway      bool   `json:"is_away"`
+
+	// Start is the moment when status must be set
+	Start time.Time `json:"start"`
+
+	// End is the moment when status must be removed (optional)
+	End time.Time `json:"end,omitzero"`
+
+	State   State  `json:"state"`
+	Applied []uint `json:"applied,omitempty"` // Users with applied status
+}
+
+// Scheduler sets and removes statuses by schedule
+type Scheduler struct {
+	// OnError is callback for errors which occurred during background checks
+	OnError func(entry *Entry, err error)
+
+	client Client
+	store  Store
+
+	mu sync.Mutex
+}
+
+// MemoryStore is in-memory schedule store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string]*Entry
+}
+
+// FileStore is schedule store which keeps entries in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data map[string]*Entry
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient       = errors.New("client is nil")
+	ErrNilStore        = errors.New("store is nil")
+	ErrNilScheduler    = errors.New("scheduler is nil")
+	ErrNilEntry        = errors.New("entry is nil")
+	ErrEmptyFilePath   = errors.New("store file path is empty")
+	ErrNoTarget        = errors.New("entry must have either user ID or tag")
+	ErrBothTargets     = errors.New("entry can't have both user ID and tag")
+	ErrEmptyStatus     = errors.New("status emoji and title are empty")
+	ErrEmptyStart      = errors.New("status start time is empty")
+	ErrInvalidEnd      = errors.New("status end time must be after start time")
+	ErrEntryNotFound   = errors.New("scheduled status not found")
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrInvalidInterval = errors.New("check interval must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ClearIfMatch removes status of user only if current status has the same
+// emoji and title as given status, so statuses changed manually by user are
+// kept intact
+func ClearIfMatch(client StatusClient, userID uint, status *pachca.Status) error {
+	if client == nil {
+		return ErrNilClient
+	}
+
+	current, err := client.GetStatus(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !IsSame(current, status) {
+		return nil
+	}
+
+	return client.DeleteStatus(userID)
+}
+
+// IsSame returns true if statuses have the same emoji and title
+func IsSame(s1, s2 *pachca.Status) bool {
+	return s1 != nil && s2 != nil && s1.Emoji == s2.Emoji && s1.Title == s2.Title
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new status scheduler
+func New(client Client, store Store) (*Scheduler, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	}
+
+	return &Scheduler{client: client, store: store}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string]*Entry{}}
+}
+
+// NewFileStore creates new file store and loads entries from given file if it
+// exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file, data: map[string]*Entry{}}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Schedule validates and saves scheduled status
+func (s *Scheduler) Schedule(entry *Entry) (*Entry, error) {
+	if s == nil {
+		return nil, ErrNilScheduler
+	}
+
+	err := entry.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	entry = entry.clone()
+	entry.ID = rand.Text()
+	entry.State = STATE_PENDING
+	entry.Applied = nil
+
+	err = s.store.Set(entry)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save scheduled status: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Cancel removes scheduled status. If status is already applied, it is removed
+// from users.
+func (s *Scheduler) Cancel(id string) error {
+	if s == nil {
+		return ErrNilScheduler
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.store.Get(id)
+
+	if err != nil {
+		return fmt.Errorf("can't get scheduled status: %w", err)
+	}
+
+	if entry == nil {
+		return ErrEntryNotFound
+	}
+
+	if entry.State == STATE_ACTIVE || len(entry.Applied) > 0 {
+		err = s.clear(entry)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.store.Delete(id)
+}
+
+// List returns all scheduled statuses sorted by start time
+func (s *Scheduler) List() ([]*Entry, error) {
+	if s == nil {
+		return nil, ErrNilScheduler
+	}
+
+	entries, err := s.store.List()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't list scheduled statuses: %w", err)
+	}
+
+	slices.SortStableFunc(entries, func(e1, e2 *Entry) int {
+		return cmp.Or(e1.Start.Compare(e2.Start), cmp.Compare(e1.ID, e2.ID))
+	})
+
+	return entries, nil
+}
+
+// Check applies and removes statuses which are due at given time. Failed
+// operations are retried on the next check.
+func (s *Scheduler) Check(now time.Time) error {
+	if s == nil {
+		return ErrNilScheduler
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.List()
+
+	if err != nil {
+		return err
+	}
+
+	errs := errors.NewBundle()
+
+	for _, entry := range entries {
+		err = s.checkEntry(entry, now)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't process scheduled status %s: %w", entry.ID, err))
+
+			if s.OnError != nil {
+				s.OnError(entry, err)
+			}
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// Run periodically checks schedule until stop channel is closed
+func (s *Scheduler) Run(interval time.Duration, stop <-chan struct{}) error {
+	switch {
+	case s == nil:
+		return ErrNilScheduler
+	case interval <= 0:
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			s.Check(now)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates scheduled status
+func (e *Entry) Validate() error {
+	switch {
+	case e == nil:
+		return ErrNilEntry
+	case e.UserID == 0 && e.Tag == "":
+		return ErrNoTarget
+	case e.UserID != 0 && e.Tag != "":
+		return ErrBothTargets
+	case e.Emoji == "" && e.Title == "":
+		return ErrEmptyStatus
+	case e.Start.IsZero():
+		return ErrEmptyStart
+	case !e.End.IsZero() && !e.End.After(e.Start):
+		return ErrInvalidEnd
+	}
+
+	return nil
+}
+
+// Status returns Pachca status for entry
+func (e *Entry) Status() *pachca.Status {
+	if e == nil {
+		return nil
+	}
+
+	status := &pachca.Status{
+		Emoji:     e.Emoji,
+		Title:     e.Title,
+		ExpiresAt: pachca.Date{Time: e.End},
+		IsAway:    e.IsAway,
+	}
+
+	if e.AwayMessage != "" {
+		status.AwayMessage = &pachca.AwayMessage{Text: e.AwayMessage}
+	}
+
+	return status
+}
+
+// IsDue returns true if status must be applied at given time
+func (e *Entry) IsDue(now time.Time) bool {
+	return e != nil && !now.Before(e.Start) && !e.IsExpired(now)
+}
+
+// IsExpired returns true if status must be removed at given time
+func (e *Entry) IsExpired(now time.Time) bool {
+	return e != nil && !e.End.IsZero() && !now.Before(e.End)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns entry with given ID or nil if there is no such entry
+func (s *MemoryStore) Get(id string) (*Entry, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data[id], nil
+}
+
+// Set saves entry
+func (s *MemoryStore) Set(entry *Entry) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case entry == nil:
+		return ErrNilEntry
+	}
+
+	s.mu.Lock()
+	s.data[entry.ID] = entry
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Delete removes entry with given ID
+func (s *MemoryStore) Delete(id string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	delete(s.data, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns all entries
+func (s *MemoryStore) List() ([]*Entry, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Entry, 0, len(s.data))
+
+	for _, entry := range s.data {
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns entry with given ID or nil if there is no such entry
+func (s *FileStore) Get(id string) (*Entry, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data[id], nil
+}
+
+// Set saves entry
+func (s *FileStore) Set(entry *Entry) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case entry == nil:
+		return ErrNilEntry
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[entry.ID] = entry
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// Delete removes entry with given ID
+func (s *FileStore) Delete(id string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[id] == nil {
+		return nil
+	}
+
+	delete(s.data, id)
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// List returns all entries
+func (s *FileStore) List() ([]*Entry, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Entry, 0, len(s.data))
+
+	for _, entry := range s.data {
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkEntry applies or removes scheduled status
+func (s *Scheduler) checkEntry(entry *Entry, now time.Time) error {
+	switch {
+	case entry.IsExpired(now):
+		if len(entry.Applied) > 0 {
+			err := s.clear(entry)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.store.Delete(entry.ID)
+
+	case entry.State == STATE_PENDING && entry.IsDue(now):
+		return s.apply(entry)
+	}
+
+	return nil
+}
+
+// apply sets status for all target users. Users with already applied status
+// are skipped, so apply can be safely retried.
+func (s *Scheduler) apply(entry *Entry) error {
+	users, err := s.getTargets(entry)
+
+	if err != nil {
+		return err
+	}
+
+	errs := errors.NewBundle()
+	status := entry.Status()
+
+	for _, userID := range users {
+		if slices.Contains(entry.Applied, userID) {
+			continue
+		}
+
+		_, err = s.client.UpdateStatus(userID, status)
+
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+
+		entry.Applied = append(entry.Applied, userID)
+	}
+
+	if errs.IsEmpty() {
+		entry.State = STATE_ACTIVE
+	}
+
+	err = s.store.Set(entry)
+
+	if err != nil {
+		errs.Add(fmt.Errorf("can't save scheduled status: %w", err))
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// clear removes status from users. Status is removed only if user still has
+// status set by scheduler.
+func (s *Scheduler) clear(entry *Entry) error {
+	errs := errors.NewBundle()
+
+	var pending []uint
+
+	for _, userID := range entry.Applied {
+		err := ClearIfMatch(s.client, userID, entry.Status())
+
+		if err != nil {
+			errs.Add(err)
+			pending = append(pending, userID)
+		}
+	}
+
+	if !errs.IsEmpty() {
+		entry.Applied = pending
+		errs.Add(s.store.Set(entry))
+
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// getTargets returns IDs of target users
+func (s *Scheduler) getTargets(entry *Entry) ([]uint, error) {
+	if entry.UserID != 0 {
+		return []uint{entry.UserID}, nil
+	}
+
+	tags, err := s.client.GetTags()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get tags: %w", err)
+	}
+
+	tag := tags.Find(entry.Tag)
+
+	if tag == nil {
+		return nil, fmt.Errorf("%w: %q", ErrTagNotFound, entry.Tag)
+	}
+
+	users, err := s.client.GetTagUsers(tag.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get users with tag %q: %w", entry.Tag, err)
+	}
+
+	var result []uint
+
+	for _, user := range users {
+		if user.IsActive() && !user.IsBot {
+			result = append(result, user.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// clone returns copy of entry
+func (e *Entry) clone() *Entry {
+	c := *e
+	c.Applied = slices.Clone(e.Applied)
+
+	return &c
+}
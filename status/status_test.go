@@ -0,0 +1,529 @@
+package status
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type fakeClient struct {
+	statuses map[uint]*pachca.Status
+	tagUsers pachca.Users
+	users    pachca.Users
+
+	failUpdate map[uint]bool
+	failTags   bool
+	failGet    bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type StatusSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&StatusSuite{})
+
+var (
+	_ Client     = (*pachca.Client)(nil)
+	_ AwayClient = (*pachca.Client)(nil)
+)
+
+var start = time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+const testICS = "\ufeffBEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1\r\n" +
+	"SUMMARY:Vacation\\, Bali\r\n" +
+	"DESCRIPTION:Line 1\\nLine 2\r\n" +
+	"DTSTART;VALUE=DATE:20261102\r\n" +
+	"DTEND;VALUE=DATE:20261107\r\n" +
+	"ATTENDEE;CN=\"Doe, John\";ROLE=REQ-PARTICIPANT:mailto:John@Domain.com\r\n" +
+	"ATTENDEE;CN=Ghost:MAILTO:ghost@domain.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2\r\n" +
+	"SUMMARY:Business trip\r\n" +
+	"DTSTART;TZID=Europe/Moscow:20261102T100000\r\n" +
+	"DTEND;TZID=Europe/Moscow:20261102T\r\n" +
+	" 190000\r\n" +
+	"ORGANIZER;CN=Jane:mailto:jane@domain.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:3\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20261102T080000Z\r\n" +
+	"DTEND:20261102T180000Z\r\n" +
+	"ATTENDEE:mailto:bob@domain.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:4\r\n" +
+	"DTSTART:20261102T080000\r\n" +
+	"ATTENDEE:mailto:bob@domain.com\r\n" +
+	"ATTENDEE:invalid\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *StatusSuite) TestValidate(c *C) {
+	var e *Entry
+
+	c.Assert(e.Validate(), Equals, ErrNilEntry)
+	c.Assert(e.Status(), IsNil)
+	c.Assert(e.IsDue(start), Equals, false)
+	c.Assert(e.IsExpired(start), Equals, false)
+
+	c.Assert((&Entry{}).Validate(), Equals, ErrNoTarget)
+	c.Assert((&Entry{UserID: 1, Tag: "dev"}).Validate(), Equals, ErrBothTargets)
+	c.Assert((&Entry{UserID: 1}).Validate(), Equals, ErrEmptyStatus)
+	c.Assert((&Entry{UserID: 1, Emoji: "🌴"}).Validate(), Equals, ErrEmptyStart)
+	c.Assert((&Entry{UserID: 1, Emoji: "🌴", Start: start, End: start}).Validate(), Equals, ErrInvalidEnd)
+	c.Assert((&Entry{UserID: 1, Emoji: "🌴", Start: start}).Validate(), IsNil)
+
+	e = &Entry{UserID: 1, Emoji: "🌴", Title: "Vacation", AwayMessage: "Back soon", IsAway: true, Start: start, End: start.Add(time.Hour)}
+
+	c.Assert(e.Status(), DeepEquals, &pachca.Status{
+		Emoji: "🌴", Title: "Vacation", IsAway: true,
+		ExpiresAt:   pachca.Date{Time: start.Add(time.Hour)},
+		AwayMessage: &pachca.AwayMessage{Text: "Back soon"},
+	})
+
+	c.Assert(e.IsDue(start.Add(-time.Minute)), Equals, false)
+	c.Assert(e.IsDue(start), Equals, true)
+	c.Assert(e.IsDue(start.Add(time.Hour)), Equals, false)
+	c.Assert(e.IsExpired(start.Add(time.Hour)), Equals, true)
+}
+
+func (s *StatusSuite) TestScheduler(c *C) {
+	client := newFakeClient()
+
+	_, err := New(nil, NewMemoryStore())
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = New(client, nil)
+	c.Assert(err, Equals, ErrNilStore)
+
+	file := c.MkDir() + "/schedule.json"
+	store, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	sc, err := New(client, store)
+	c.Assert(err, IsNil)
+
+	_, err = sc.Schedule(&Entry{})
+	c.Assert(err, Equals, ErrNoTarget)
+
+	vacation, err := sc.Schedule(&Entry{
+		UserID: 1, Emoji: "🌴", Title: "Vacation", IsAway: true,
+		Start: start, End: start.Add(48 * time.Hour),
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(vacation.ID, Not(Equals), "")
+	c.Assert(vacation.State, Equals, STATE_PENDING)
+
+	onCall, err := sc.Schedule(&Entry{
+		Tag: "ops", Emoji: "📟", Title: "On-call",
+		Start: start.Add(time.Hour), End: start.Add(24 * time.Hour),
+	})
+
+	c.Assert(err, IsNil)
+
+	// Schedule survives restart
+	store, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+	sc, _ = New(client, store)
+
+	entries, err := sc.List()
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].ID, Equals, vacation.ID)
+	c.Assert(entries[1].ID, Equals, onCall.ID)
+
+	c.Assert(sc.Check(start.Add(-time.Minute)), IsNil)
+	c.Assert(client.statuses, HasLen, 0)
+
+	c.Assert(sc.Check(start), IsNil)
+	c.Assert(client.statuses[1].Title, Equals, "Vacation")
+	c.Assert(client.statuses[1].ExpiresAt.Equal(start.Add(48*time.Hour)), Equals, true)
+
+	// One of tag users fails, so update is retried on the next check
+	client.failUpdate[3] = true
+
+	var failed *Entry
+
+	sc.OnError = func(entry *Entry, err error) { failed = entry }
+
+	c.Assert(sc.Check(start.Add(time.Hour)), ErrorMatches, `can't process scheduled status .*: update error`)
+	c.Assert(failed.ID, Equals, onCall.ID)
+	c.Assert(client.statuses[2].Title, Equals, "On-call")
+	c.Assert(client.statuses[3], IsNil)
+
+	client.failUpdate[3] = false
+	client.statuses[2] = &pachca.Status{Emoji: "🤒", Title: "Sick"}
+
+	c.Assert(sc.Check(start.Add(2*time.Hour)), IsNil)
+	c.Assert(client.statuses[2].Title, Equals, "Sick")
+	c.Assert(client.statuses[3].Title, Equals, "On-call")
+
+	entry, _ := store.Get(onCall.ID)
+	c.Assert(entry.State, Equals, STATE_ACTIVE)
+	c.Assert(entry.Applied, DeepEquals, []uint{2, 3})
+
+	// Manually changed status is kept
+	c.Assert(sc.Check(start.Add(24*time.Hour)), IsNil)
+	c.Assert(client.statuses[2].Title, Equals, "Sick")
+	c.Assert(client.statuses[3], IsNil)
+
+	entries, _ = sc.List()
+	c.Assert(entries, HasLen, 1)
+
+	c.Assert(sc.Cancel(vacation.ID), IsNil)
+	c.Assert(client.statuses[1], IsNil)
+	c.Assert(sc.Cancel(vacation.ID), Equals, ErrEntryNotFound)
+
+	entries, _ = sc.List()
+	c.Assert(entries, HasLen, 0)
+
+	// Expired entries which were never applied are removed
+	_, err = sc.Schedule(&Entry{UserID: 1, Title: "Trip", Start: start, End: start.Add(time.Hour)})
+	c.Assert(err, IsNil)
+	c.Assert(sc.Check(start.Add(2*time.Hour)), IsNil)
+	c.Assert(client.statuses[1], IsNil)
+
+	entries, _ = sc.List()
+	c.Assert(entries, HasLen, 0)
+}
+
+func (s *StatusSuite) TestErrors(c *C) {
+	client := newFakeClient()
+	sc, _ := New(client, NewMemoryStore())
+
+	e, _ := sc.Schedule(&Entry{Tag: "unknown", Title: "Test", Start: start})
+	c.Assert(sc.Check(start), ErrorMatches, `.*tag not found: "unknown"`)
+	sc.Cancel(e.ID)
+
+	client.failTags = true
+	e, _ = sc.Schedule(&Entry{Tag: "ops", Title: "Test", Start: start})
+	c.Assert(sc.Check(start), ErrorMatches, `.*can't get tags: tags error`)
+	sc.Cancel(e.ID)
+
+	client.failTags = false
+	e, _ = sc.Schedule(&Entry{UserID: 1, Title: "Test", Start: start, End: start.Add(time.Hour)})
+	c.Assert(sc.Check(start), IsNil)
+
+	client.failGet = true
+	c.Assert(sc.Cancel(e.ID), ErrorMatches, `get error`)
+	c.Assert(sc.Check(start.Add(time.Hour)), ErrorMatches, `.*get error`)
+
+	client.failGet = false
+	c.Assert(sc.Check(start.Add(time.Hour)), IsNil)
+
+	c.Assert(ClearIfMatch(nil, 1, nil), Equals, ErrNilClient)
+	c.Assert(IsSame(nil, &pachca.Status{}), Equals, false)
+
+	c.Assert(sc.Run(0, nil), Equals, ErrInvalidInterval)
+
+	var ns *Scheduler
+
+	_, err := ns.Schedule(&Entry{})
+	c.Assert(err, Equals, ErrNilScheduler)
+	c.Assert(ns.Cancel("1"), Equals, ErrNilScheduler)
+	_, err = ns.List()
+	c.Assert(err, Equals, ErrNilScheduler)
+	c.Assert(ns.Check(start), Equals, ErrNilScheduler)
+	c.Assert(ns.Run(time.Second, nil), Equals, ErrNilScheduler)
+}
+
+func (s *StatusSuite) TestParseICS(c *C) {
+	events, err := ParseICS(strings.NewReader(testICS), nil)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 4)
+
+	msk, _ := time.LoadLocation("Europe/Moscow")
+
+	c.Assert(events[0].UID, Equals, "1")
+	c.Assert(events[0].Summary, Equals, "Vacation, Bali")
+	c.Assert(events[0].Description, Equals, "Line 1\nLine 2")
+	c.Assert(events[0].IsAllDay, Equals, true)
+	c.Assert(events[0].Start, Equals, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	c.Assert(events[0].End, Equals, time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC))
+	c.Assert(events[0].Attendees, DeepEquals, []string{"john@domain.com", "ghost@domain.com"})
+
+	c.Assert(events[1].Start.Equal(time.Date(2026, 11, 2, 10, 0, 0, 0, msk)), Equals, true)
+	c.Assert(events[1].End.Equal(time.Date(2026, 11, 2, 19, 0, 0, 0, msk)), Equals, true)
+	c.Assert(events[1].Attendees, DeepEquals, []string{"jane@domain.com"})
+
+	c.Assert(events[2].IsCancelled, Equals, true)
+	c.Assert(events[2].IsActive(start), Equals, false)
+
+	c.Assert(events[3].Start, Equals, events[3].End)
+	c.Assert(events[3].Attendees, DeepEquals, []string{"bob@domain.com"})
+
+	c.Assert(events.Active("JOHN@domain.com", start), HasLen, 1)
+	c.Assert(events.Active("bob@domain.com", start), HasLen, 0)
+	c.Assert(events[0].HasAttendee(""), Equals, false)
+
+	events, err = ParseICS(strings.NewReader(testICS), msk)
+	c.Assert(err, IsNil)
+	c.Assert(events[0].Start.Equal(time.Date(2026, 11, 2, 0, 0, 0, 0, msk)), Equals, true)
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nUID:5\nDTSTART:2026\nEND:VEVENT"), nil)
+	c.Assert(err, ErrorMatches, `can't parse DTSTART of event "5": .*`)
+
+	_, err = ParseICS(strings.NewReader(strings.Repeat("A", 2*1024*1024)), nil)
+	c.Assert(err, ErrorMatches, `can't read calendar data: .*`)
+
+	file := c.MkDir() + "/calendar.ics"
+	os.WriteFile(file, []byte(testICS), 0644)
+
+	events, err = ReadICSFile(file, nil)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 4)
+
+	_, err = ReadICSFile("/_unknown_", nil)
+	c.Assert(err, NotNil)
+
+	var e *Event
+	c.Assert(e.IsActive(start), Equals, false)
+	c.Assert(e.HasAttendee("john@domain.com"), Equals, false)
+}
+
+func (s *StatusSuite) TestFetchICS(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendar.ics" {
+			w.WriteHeader(404)
+			return
+		}
+
+		fmt.Fprint(w, testICS)
+	}))
+
+	defer srv.Close()
+
+	events, err := FetchICS(context.Background(), srv.URL+"/calendar.ics", nil)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 4)
+
+	_, err = FetchICS(context.Background(), srv.URL+"/unknown.ics", nil)
+	c.Assert(err, ErrorMatches, `can't fetch calendar: server returned status code 404`)
+
+	_, err = FetchICS(context.Background(), "http://127.0.0.1:1/calendar.ics", nil)
+	c.Assert(err, ErrorMatches, `can't fetch calendar: .*`)
+}
+
+func (s *StatusSuite) TestAwaySync(c *C) {
+	client := newFakeClient()
+	client.users = pachca.Users{
+		{ID: 1, Email: "john@domain.com", InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 2, Email: "jane@domain.com", InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 3, Email: "bob@domain.com", InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 4, Email: "alice@domain.com", InviteStatus: pachca.INVITE_CONFIRMED},
+		{ID: 5, Email: "bot@domain.com", InviteStatus: pachca.INVITE_CONFIRMED, IsBot: true},
+	}
+
+	_, err := NewAwaySync(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	as, err := NewAwaySync(client)
+	c.Assert(err, IsNil)
+
+	as.AwayMessage = "{summary} until {until}"
+
+	events, _ := ParseICS(strings.NewReader(testICS), nil)
+	now := time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	// Bob has manual status, Alice has outdated out-of-office status
+	client.statuses[3] = &pachca.Status{Emoji: "🤒", Title: "Sick"}
+	client.statuses[4] = &pachca.Status{Emoji: DEFAULT_AWAY_EMOJI, Title: DEFAULT_AWAY_TITLE}
+
+	events = append(events, &Event{
+		Start: now.Add(-time.Hour), End: now.Add(time.Hour),
+		Attendees: []string{"bob@domain.com"},
+	})
+
+	report, err := as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Set, DeepEquals, []uint{1, 2})
+	c.Assert(report.Skipped, DeepEquals, []uint{3})
+	c.Assert(report.Cleared, DeepEquals, []uint{4})
+	c.Assert(report.Unknown, DeepEquals, []string{"ghost@domain.com"})
+
+	c.Assert(client.statuses[1].IsAway, Equals, true)
+	c.Assert(client.statuses[1].AwayMessageText(), Equals, "Vacation, Bali until 2026-11-06")
+	c.Assert(client.statuses[1].ExpiresAt.Equal(time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC)), Equals, true)
+	c.Assert(client.statuses[2].AwayMessageText(), Equals, "Business trip until 2026-11-02")
+	c.Assert(client.statuses[3].Title, Equals, "Sick")
+	c.Assert(client.statuses[4], IsNil)
+
+	// Nothing changed
+	report, err = as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Set, HasLen, 0)
+
+	// Vacation was extended
+	events[0].End = events[0].End.AddDate(0, 0, 2)
+
+	report, err = as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Set, DeepEquals, []uint{1})
+	c.Assert(client.statuses[1].AwayMessageText(), Equals, "Vacation, Bali until 2026-11-08")
+
+	// Vacation was cancelled
+	events[0].IsCancelled = true
+
+	report, err = as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Cleared, DeepEquals, []uint{1})
+	c.Assert(client.statuses[1], IsNil)
+
+	// Expired status is replaced
+	events[0].IsCancelled = false
+	client.statuses[1] = &pachca.Status{Emoji: "🤒", Title: "Sick", ExpiresAt: pachca.Date{Time: now.Add(-time.Minute)}}
+
+	report, err = as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(report.Set, DeepEquals, []uint{1})
+
+	as.AwayMessage = ""
+	delete(client.statuses, 1)
+
+	_, err = as.Sync(events, now)
+	c.Assert(err, IsNil)
+	c.Assert(client.statuses[1].AwayMessage, IsNil)
+
+	client.failGet = true
+	_, err = as.Sync(events, now)
+	c.Assert(err, ErrorMatches, `(?s)can't sync status of user 1: get error.*`)
+
+	client.users = nil
+	_, err = as.Sync(events, now)
+	c.Assert(err, ErrorMatches, `can't get users: users error`)
+
+	var na *AwaySync
+	_, err = na.Sync(events, now)
+	c.Assert(err, Equals, ErrNilAwaySync)
+}
+
+func (s *StatusSuite) TestStores(c *C) {
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	file := c.MkDir() + "/schedule.json"
+	os.WriteFile(file, []byte("{"), 0600)
+
+	_, err = NewFileStore(file)
+	c.Assert(err, ErrorMatches, `can't read store file .*`)
+
+	var ms *MemoryStore
+	var fs *FileStore
+
+	_, err = ms.Get("1")
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(ms.Set(&Entry{}), Equals, ErrNilStore)
+	c.Assert(ms.Delete("1"), Equals, ErrNilStore)
+	_, err = ms.List()
+	c.Assert(err, Equals, ErrNilStore)
+
+	_, err = fs.Get("1")
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(fs.Set(&Entry{}), Equals, ErrNilStore)
+	c.Assert(fs.Delete("1"), Equals, ErrNilStore)
+	_, err = fs.List()
+	c.Assert(err, Equals, ErrNilStore)
+
+	c.Assert(NewMemoryStore().Set(nil), Equals, ErrNilEntry)
+
+	fs, _ = NewFileStore(c.MkDir() + "/schedule.json")
+	c.Assert(fs.Set(nil), Equals, ErrNilEntry)
+	c.Assert(fs.Delete("1"), IsNil)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		statuses:   map[uint]*pachca.Status{},
+		failUpdate: map[uint]bool{},
+		tagUsers: pachca.Users{
+			{ID: 2, InviteStatus: pachca.INVITE_CONFIRMED},
+			{ID: 3, InviteStatus: pachca.INVITE_CONFIRMED},
+			{ID: 4, InviteStatus: pachca.INVITE_CONFIRMED, IsSuspended: true},
+			{ID: 5, InviteStatus: pachca.INVITE_CONFIRMED, IsBot: true},
+		},
+	}
+}
+
+func (c *fakeClient) GetTags(names ...string) (pachca.Tags, error) {
+	if c.failTags {
+		return nil, errors.New("tags error")
+	}
+
+	return pachca.Tags{{ID: 10, Name: "Ops"}}, nil
+}
+
+func (c *fakeClient) GetTagUsers(groupTagID uint) (pachca.Users, error) {
+	return c.tagUsers, nil
+}
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	if c.users == nil {
+		return nil, errors.New("users error")
+	}
+
+	var result pachca.Users
+
+	for _, u := range c.users {
+		user := *u
+		user.Status = c.statuses[u.ID]
+		result = append(result, &user)
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) GetStatus(userID uint) (*pachca.Status, error) {
+	if c.failGet {
+		return nil, errors.New("get error")
+	}
+
+	return c.statuses[userID], nil
+}
+
+func (c *fakeClient) UpdateStatus(userID uint, status *pachca.Status) (*pachca.Status, error) {
+	if c.failUpdate[userID] {
+		return nil, errors.New("update error")
+	}
+
+	c.statuses[userID] = status
+
+	return status, nil
+}
+
+func (c *fakeClient) DeleteStatus(userID uint) error {
+	delete(c.statuses, userID)
+	return nil
+}
@@ -0,0 +1,438 @@
+package templates
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// EXT is extension of template files
+const EXT = ".tmpl"
+
+// BUTTONS_SUFFIX is suffix of template name with message buttons
+const BUTTONS_SUFFIX = ".buttons"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by renderer
+type Client interface {
+	GetUsers(searchQuery ...string) (pachca.Users, error)
+	GetChat(chatID uint) (*pachca.Chat, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Renderer renders messages from templates
+type Renderer struct {
+	// Location is default time zone used for dates formatting
+	Location *time.Location
+
+	client Client
+	tmpl   *template.Template
+	users  pachca.Users
+	chats  map[uint]*pachca.Chat
+
+	mu sync.RWMutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient       = errors.New("client is nil")
+	ErrNilRenderer     = errors.New("renderer is nil")
+	ErrEmptyName       = errors.New("template name is empty")
+	ErrEmptyDir        = errors.New("templates directory path is empty")
+	ErrEmptyContent    = errors.New("rendered message is empty")
+	ErrEmptyUserQuery  = errors.New("user email or nickname is empty")
+	ErrInvalidChatID   = errors.New("chat ID must be greater than 0")
+	ErrUnsupportedUser = errors.New("unsupported user value")
+)
+
+// markdownEscaper is replacer for escaping markdown control characters
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`",
+	"[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"#", `\#`, ">", `\>`, "|", `\|`,
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new renderer
+func New(client Client) (*Renderer, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	r := &Renderer{
+		Location: time.UTC,
+		client:   client,
+		chats:    map[uint]*pachca.Chat{},
+	}
+
+	r.tmpl = template.New("").Funcs(r.funcs())
+
+	return r, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Parse parses template with given name
+func (r *Renderer) Parse(name, text string) error {
+	switch {
+	case r == nil:
+		return ErrNilRenderer
+	case name == "":
+		return ErrEmptyName
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.tmpl.New(name).Parse(text)
+
+	if err != nil {
+		return fmt.Errorf("can't parse template %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// LoadDir loads all templates (*.tmpl) from given directory. Name of every
+// template is a file name without extension. Buttons for message can be
+// defined in template with ".buttons" suffix (e.g. "deploy.buttons.tmpl").
+func (r *Renderer) LoadDir(dir string) error {
+	switch {
+	case r == nil:
+		return ErrNilRenderer
+	case dir == "":
+		return ErrEmptyDir
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+EXT))
+
+	if err != nil {
+		return fmt.Errorf("can't list templates in %q: %w", dir, err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+
+		if err != nil {
+			return fmt.Errorf("can't read template %q: %w", file, err)
+		}
+
+		err = r.Parse(strings.TrimSuffix(filepath.Base(file), EXT), string(data))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Has returns true if renderer has template with given name
+func (r *Renderer) Has(name string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tmpl.Lookup(name) != nil
+}
+
+// Render renders message with given template. If there is a template with the
+// same name and ".buttons" suffix, it is used to render message buttons as JSON
+// (e.g. [[{"text":"Open","url":"https://…"}]]).
+func (r *Renderer) Render(name string, data any) (*pachca.MessageRequest, error) {
+	switch {
+	case r == nil:
+		return nil, ErrNilRenderer
+	case name == "":
+		return nil, ErrEmptyName
+	}
+
+	content, err := r.execute(name, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+
+	if content == "" {
+		return nil, ErrEmptyContent
+	}
+
+	msg := &pachca.MessageRequest{Content: content}
+
+	if !r.Has(name + BUTTONS_SUFFIX) {
+		return msg, nil
+	}
+
+	buttonsData, err := r.execute(name+BUTTONS_SUFFIX, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(buttonsData) != "" {
+		err = json.Unmarshal([]byte(buttonsData), &msg.Buttons)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't decode buttons from template %q: %w", name+BUTTONS_SUFFIX, err)
+		}
+	}
+
+	return msg, nil
+}
+
+// RenderString renders template with given name as a plain string
+func (r *Renderer) RenderString(name string, data any) (string, error) {
+	switch {
+	case r == nil:
+		return "", ErrNilRenderer
+	case name == "":
+		return "", ErrEmptyName
+	}
+
+	return r.execute(name, data)
+}
+
+// Reset drops cached users and chats info
+func (r *Renderer) Reset() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.users = nil
+	r.chats = map[uint]*pachca.Chat{}
+	r.mu.Unlock()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Escape escapes markdown control characters in given text
+func Escape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// execute executes template with given name
+func (r *Renderer) execute(name string, data any) (string, error) {
+	r.mu.RLock()
+	t := r.tmpl.Lookup(name)
+	r.mu.RUnlock()
+
+	if t == nil {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+
+	var buf bytes.Buffer
+
+	err := t.Execute(&buf, data)
+
+	if err != nil {
+		return "", fmt.Errorf("can't render template %q: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// funcs returns map with template functions
+func (r *Renderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"user":     r.findUser,
+		"mention":  r.mention,
+		"chatURL":  chatURL,
+		"chatLink": r.chatLink,
+		"date":     r.formatDate,
+		"userDate": r.formatUserDate,
+		"escape":   Escape,
+		"json":     toJSON,
+	}
+}
+
+// findUser finds user by email or nickname
+func (r *Renderer) findUser(query string) (*pachca.User, error) {
+	if query == "" {
+		return nil, ErrEmptyUserQuery
+	}
+
+	r.mu.RLock()
+	users := r.users
+	r.mu.RUnlock()
+
+	if users == nil {
+		var err error
+
+		users, err = r.client.GetUsers()
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.mu.Lock()
+		r.users = users
+		r.mu.Unlock()
+	}
+
+	user := users.Find(strings.TrimPrefix(query, "@"))
+
+	if user == nil {
+		return nil, fmt.Errorf("unknown user %q", query)
+	}
+
+	return user, nil
+}
+
+// mention returns mention for user with given email or nickname
+func (r *Renderer) mention(query string) (string, error) {
+	user, err := r.findUser(query)
+
+	if err != nil {
+		return "", err
+	}
+
+	return user.Mention(), nil
+}
+
+// chatLink returns markdown link to the chat with given ID
+func (r *Renderer) chatLink(chatID uint) (string, error) {
+	if chatID == 0 {
+		return "", ErrInvalidChatID
+	}
+
+	r.mu.RLock()
+	chat := r.chats[chatID]
+	r.mu.RUnlock()
+
+	if chat == nil {
+		var err error
+
+		chat, err = r.client.GetChat(chatID)
+
+		if err != nil {
+			return "", err
+		}
+
+		r.mu.Lock()
+		r.chats[chatID] = chat
+		r.mu.Unlock()
+	}
+
+	return fmt.Sprintf("[%s](%s)", Escape(chat.Name), chat.URL()), nil
+}
+
+// formatDate formats date using default location
+func (r *Renderer) formatDate(layout string, d any) (string, error) {
+	t, err := toTime(d)
+
+	if err != nil {
+		return "", err
+	}
+
+	loc := r.Location
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return t.In(loc).Format(layout), nil
+}
+
+// formatUserDate formats date using user time zone. User can be defined
+// as *pachca.User or as email/nickname.
+func (r *Renderer) formatUserDate(user any, layout string, d any) (string, error) {
+	var u *pachca.User
+	var err error
+
+	switch t := user.(type) {
+	case *pachca.User:
+		u = t
+	case string:
+		u, err = r.findUser(t)
+
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", ErrUnsupportedUser
+	}
+
+	t, err := toTime(d)
+
+	if err != nil {
+		return "", err
+	}
+
+	loc, err := time.LoadLocation(u.TimeZone)
+
+	if u.TimeZone == "" || err != nil {
+		return r.formatDate(layout, t)
+	}
+
+	return t.In(loc).Format(layout), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// chatURL returns URL of the chat with given ID
+func chatURL(chatID uint) string {
+	return (&pachca.Chat{ID: chatID}).URL()
+}
+
+// toJSON encodes given value to JSON
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// toTime converts given value to time
+func toTime(d any) (time.Time, error) {
+	switch t := d.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t != nil {
+			return *t, nil
+		}
+		return time.Time{}, nil
+	case pachca.Date:
+		return t.Time, nil
+	case *pachca.Date:
+		if t != nil {
+			return t.Time, nil
+		}
+		return time.Time{}, nil
+	case int64:
+		return time.Unix(t, 0), nil
+	case int:
+		return time.Unix(int64(t), 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported date value %T", d)
+}
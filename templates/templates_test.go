@@ -0,0 +1,170 @@
+package templates
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type fakeClient struct {
+	usersCalls int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type TemplatesSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&TemplatesSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *TemplatesSuite) TestRender(c *C) {
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	fc := &fakeClient{}
+	r, err := New(fc)
+	c.Assert(err, IsNil)
+
+	c.Assert(r.Parse("", ""), Equals, ErrEmptyName)
+	c.Assert(r.Parse("broken", "{{ .Test "), NotNil)
+
+	c.Assert(r.Parse(
+		"deploy",
+		`{{ mention "john@domain.com" }} deployed *{{ escape .Service }}* to {{ chatLink 10 }} `+
+			`at {{ userDate "@bob" "15:04" .Date }} ({{ date "15:04" .Date }})`,
+	), IsNil)
+
+	c.Assert(r.Parse(
+		"deploy.buttons",
+		`[[{"text":"Open","url":{{ json (chatURL 10) }}}]]`,
+	), IsNil)
+
+	m, err := r.Render("deploy", map[string]any{
+		"Service": "api_gw",
+		"Date":    time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(m, NotNil)
+	c.Assert(m.Content, Equals, `<@1> deployed *api\_gw* to [Ops \#1](https://app.pachca.com/chats/10) at 15:00 (12:00)`)
+	c.Assert(m.Buttons, HasLen, 1)
+	c.Assert(m.Buttons[0][0].URL, Equals, "https://app.pachca.com/chats/10")
+	c.Assert(fc.usersCalls, Equals, 1)
+
+	r.Reset()
+
+	str, err := r.RenderString("deploy.buttons", nil)
+	c.Assert(err, IsNil)
+	c.Assert(str, Not(Equals), "")
+
+	c.Assert(r.Parse("empty", `  `), IsNil)
+	_, err = r.Render("empty", nil)
+	c.Assert(err, Equals, ErrEmptyContent)
+
+	c.Assert(r.Parse("unknown", `{{ mention "unknown" }}`), IsNil)
+	_, err = r.Render("unknown", nil)
+	c.Assert(err, NotNil)
+
+	c.Assert(r.Parse("bad", `Test`), IsNil)
+	c.Assert(r.Parse("bad.buttons", `{`), IsNil)
+	_, err = r.Render("bad", nil)
+	c.Assert(err, NotNil)
+
+	_, err = r.Render("missing", nil)
+	c.Assert(err, NotNil)
+	_, err = r.Render("", nil)
+	c.Assert(err, Equals, ErrEmptyName)
+	_, err = r.RenderString("", nil)
+	c.Assert(err, Equals, ErrEmptyName)
+
+	var nr *Renderer
+	c.Assert(nr.Parse("a", "b"), Equals, ErrNilRenderer)
+	c.Assert(nr.LoadDir("a"), Equals, ErrNilRenderer)
+	c.Assert(nr.Has("a"), Equals, false)
+	_, err = nr.Render("a", nil)
+	c.Assert(err, Equals, ErrNilRenderer)
+	_, err = nr.RenderString("a", nil)
+	c.Assert(err, Equals, ErrNilRenderer)
+	c.Assert(func() { nr.Reset() }, NotPanics)
+}
+
+func (s *TemplatesSuite) TestLoadDir(c *C) {
+	r, _ := New(&fakeClient{})
+
+	dir := c.MkDir()
+	os.WriteFile(dir+"/hello.tmpl", []byte(`Hello, {{ .Name }}!`), 0644)
+	os.WriteFile(dir+"/readme.txt", []byte(`{{`), 0644)
+
+	c.Assert(r.LoadDir(""), Equals, ErrEmptyDir)
+	c.Assert(r.LoadDir(dir), IsNil)
+	c.Assert(r.Has("hello"), Equals, true)
+
+	m, err := r.Render("hello", map[string]string{"Name": "John"})
+	c.Assert(err, IsNil)
+	c.Assert(m.Content, Equals, "Hello, John!")
+
+	os.WriteFile(dir+"/broken.tmpl", []byte(`{{`), 0644)
+	c.Assert(r.LoadDir(dir), NotNil)
+}
+
+func (s *TemplatesSuite) TestHelpers(c *C) {
+	c.Assert(Escape("*bold* [link](url) `code`"), Equals, "\\*bold\\* \\[link\\]\\(url\\) \\`code\\`")
+
+	r, _ := New(&fakeClient{})
+	d := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, v := range []any{d, &d, pachca.Date{Time: d}, &pachca.Date{Time: d}, d.Unix(), int(d.Unix())} {
+		str, err := r.formatDate("2006-01-02", v)
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, "2026-01-01")
+	}
+
+	_, err := r.formatDate("2006", "test")
+	c.Assert(err, NotNil)
+
+	r.Location = nil
+	str, _ := r.formatUserDate(&pachca.User{}, "15:04", d)
+	c.Assert(str, Equals, "12:00")
+
+	_, err = r.formatUserDate(1, "15:04", d)
+	c.Assert(err, Equals, ErrUnsupportedUser)
+	_, err = r.formatUserDate(&pachca.User{}, "15:04", "test")
+	c.Assert(err, NotNil)
+	_, err = r.formatUserDate("", "15:04", d)
+	c.Assert(err, Equals, ErrEmptyUserQuery)
+	_, err = r.chatLink(0)
+	c.Assert(err, Equals, ErrInvalidChatID)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) GetUsers(searchQuery ...string) (pachca.Users, error) {
+	c.usersCalls++
+
+	return pachca.Users{
+		{ID: 1, Email: "john@domain.com", Nickname: "john"},
+		{ID: 2, Email: "bob@domain.com", Nickname: "bob", TimeZone: "Europe/Moscow"},
+	}, nil
+}
+
+func (c *fakeClient) GetChat(chatID uint) (*pachca.Chat, error) {
+	return &pachca.Chat{ID: chatID, Name: "Ops #1"}, nil
+}
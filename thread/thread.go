@@ -0,0 +1,383 @@
+package thread
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by router
+type Client interface {
+	AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error)
+	NewThread(messageID uint) (*pachca.Thread, error)
+}
+
+// Store is storage for external key → thread mapping
+type Store interface {
+	// Get returns thread for given chat and key or nil if there is no such thread
+	Get(chatID uint, key string) (*pachca.Thread, error)
+
+	// Set saves thread for given chat and key
+	Set(chatID uint, key string, thread *pachca.Thread) error
+
+	// Delete removes thread for given chat and key
+	Delete(chatID uint, key string) error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Router routes messages related to external entities (PRs, tickets, pipelines)
+// into dedicated threads
+type Router struct {
+	client Client
+	store  Store
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	pending map[string]*pachca.Thread // threads which are not saved to store yet
+}
+
+// MemoryStore is in-memory threads store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string]*pachca.Thread
+}
+
+// FileStore is threads store which keeps mapping in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data map[string]*pachca.Thread
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient      = errors.New("client is nil")
+	ErrNilStore       = errors.New("store is nil")
+	ErrNilRouter      = errors.New("router is nil")
+	ErrNilRootMessage = errors.New("root message is nil")
+	ErrNilMessage     = errors.New("message is nil")
+	ErrEmptyKey       = errors.New("key is empty")
+	ErrEmptyFilePath  = errors.New("store file path is empty")
+	ErrInvalidChatID  = errors.New("chat ID must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewRouter creates new thread router
+func NewRouter(client Client, store Store) (*Router, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	}
+
+	return &Router{
+		client:  client,
+		store:   store,
+		locks:   map[string]*sync.Mutex{},
+		pending: map[string]*pachca.Thread{},
+	}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string]*pachca.Thread{}}
+}
+
+// NewFileStore creates new file store and loads mapping from given file
+// if it exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file, data: map[string]*pachca.Thread{}}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Thread returns thread for given key. If there is no thread for this key yet,
+// the root message is sent to the chat and a new thread is created for it. If
+// thread creation or saving fails, the next call reuses already sent root message.
+func (r *Router) Thread(chatID uint, key string, root *pachca.MessageRequest) (*pachca.Thread, error) {
+	switch {
+	case r == nil:
+		return nil, ErrNilRouter
+	case chatID == 0:
+		return nil, ErrInvalidChatID
+	case key == "":
+		return nil, ErrEmptyKey
+	}
+
+	lock := r.getLock(chatID, key)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	thread, err := r.store.Get(chatID, key)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get thread for key %q: %w", key, err)
+	}
+
+	if thread != nil {
+		return thread, nil
+	}
+
+	// Thread created on previous failed attempt is reused, so retry will not
+	// post another root message
+	thread = r.getPending(chatID, key)
+
+	if thread == nil {
+		if root == nil {
+			return nil, ErrNilRootMessage
+		}
+
+		rootMsg := *root
+		rootMsg.EntityType = pachca.ENTITY_TYPE_DISCUSSION
+		rootMsg.EntityID = chatID
+
+		msg, err := r.client.AddMessage(&rootMsg)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't send root message for key %q: %w", key, err)
+		}
+
+		thread = &pachca.Thread{MessageID: msg.ID, MessageChatID: chatID}
+		r.setPending(chatID, key, thread)
+	}
+
+	if thread.ID == 0 {
+		newThread, err := r.client.NewThread(thread.MessageID)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't create thread for key %q: %w", key, err)
+		}
+
+		thread = newThread
+		r.setPending(chatID, key, thread)
+	}
+
+	err = r.store.Set(chatID, key, thread)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't save thread for key %q: %w", key, err)
+	}
+
+	r.setPending(chatID, key, nil)
+
+	return thread, nil
+}
+
+// Post sends message to the thread for given key. Root message is used only if
+// thread for the key doesn't exist yet.
+func (r *Router) Post(chatID uint, key string, root, message *pachca.MessageRequest) (*pachca.Message, error) {
+	switch {
+	case r == nil:
+		return nil, ErrNilRouter
+	case message == nil:
+		return nil, ErrNilMessage
+	}
+
+	thread, err := r.Thread(chatID, key, root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	msg := *message
+	msg.EntityType = pachca.ENTITY_TYPE_THREAD
+	msg.EntityID = thread.ID
+
+	return r.client.AddMessage(&msg)
+}
+
+// PostText sends message with given text to the thread for given key
+func (r *Router) PostText(chatID uint, key, rootText, text string) (*pachca.Message, error) {
+	return r.Post(
+		chatID, key,
+		&pachca.MessageRequest{Content: rootText},
+		&pachca.MessageRequest{Content: text},
+	)
+}
+
+// Forget removes mapping for given key, so the next post will create a new thread
+func (r *Router) Forget(chatID uint, key string) error {
+	switch {
+	case r == nil:
+		return ErrNilRouter
+	case chatID == 0:
+		return ErrInvalidChatID
+	case key == "":
+		return ErrEmptyKey
+	}
+
+	lock := r.getLock(chatID, key)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	r.setPending(chatID, key, nil)
+
+	return r.store.Delete(chatID, key)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns thread for given chat and key or nil if there is no such thread
+func (s *MemoryStore) Get(chatID uint, key string) (*pachca.Thread, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data[getStoreKey(chatID, key)], nil
+}
+
+// Set saves thread for given chat and key
+func (s *MemoryStore) Set(chatID uint, key string, thread *pachca.Thread) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	s.data[getStoreKey(chatID, key)] = thread
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Delete removes thread for given chat and key
+func (s *MemoryStore) Delete(chatID uint, key string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	delete(s.data, getStoreKey(chatID, key))
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns thread for given chat and key or nil if there is no such thread
+func (s *FileStore) Get(chatID uint, key string) (*pachca.Thread, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data[getStoreKey(chatID, key)], nil
+}
+
+// Set saves thread for given chat and key
+func (s *FileStore) Set(chatID uint, key string, thread *pachca.Thread) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[getStoreKey(chatID, key)] = thread
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// Delete removes thread for given chat and key
+func (s *FileStore) Delete(chatID uint, key string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, getStoreKey(chatID, key))
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getLock returns lock for given chat and key
+func (r *Router) getLock(chatID uint, key string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := getStoreKey(chatID, key)
+	lock := r.locks[k]
+
+	if lock == nil {
+		lock = &sync.Mutex{}
+		r.locks[k] = lock
+	}
+
+	return lock
+}
+
+// getPending returns thread which is not saved to store yet
+func (r *Router) getPending(chatID uint, key string) *pachca.Thread {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.pending[getStoreKey(chatID, key)]
+}
+
+// setPending saves thread which is not saved to store yet, nil removes it
+func (r *Router) setPending(chatID uint, key string, thread *pachca.Thread) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if thread == nil {
+		delete(r.pending, getStoreKey(chatID, key))
+	} else {
+		r.pending[getStoreKey(chatID, key)] = thread
+	}
+}
+
+// getStoreKey returns key for storing thread info
+func getStoreKey(chatID uint, key string) string {
+	return fmt.Sprintf("%d:%s", chatID, key)
+}
@@ -0,0 +1,201 @@
+package thread
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type fakeClient struct {
+	messages   []*pachca.MessageRequest
+	threads    int
+	fail       bool
+	failThread bool
+}
+
+type failStore struct {
+	*MemoryStore
+	fail bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ThreadSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ThreadSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ThreadSuite) TestRouter(c *C) {
+	_, err := NewRouter(nil, NewMemoryStore())
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = NewRouter(&fakeClient{}, nil)
+	c.Assert(err, Equals, ErrNilStore)
+
+	fc := &fakeClient{}
+	r, err := NewRouter(fc, NewMemoryStore())
+	c.Assert(err, IsNil)
+
+	m, err := r.PostText(10, "PR-1", "PR #1 opened", "Build passed")
+	c.Assert(err, IsNil)
+	c.Assert(m, NotNil)
+	c.Assert(fc.threads, Equals, 1)
+	c.Assert(fc.messages, HasLen, 2)
+	c.Assert(fc.messages[0].EntityType, Equals, pachca.ENTITY_TYPE_DISCUSSION)
+	c.Assert(fc.messages[0].EntityID, Equals, uint(10))
+	c.Assert(fc.messages[1].EntityType, Equals, pachca.ENTITY_TYPE_THREAD)
+	c.Assert(fc.messages[1].EntityID, Equals, uint(1001))
+
+	_, err = r.PostText(10, "PR-1", "PR #1 opened", "Build failed")
+	c.Assert(err, IsNil)
+	c.Assert(fc.threads, Equals, 1)
+	c.Assert(fc.messages, HasLen, 3)
+	c.Assert(fc.messages[2].EntityID, Equals, uint(1001))
+
+	_, err = r.PostText(10, "PR-2", "PR #2 opened", "Build passed")
+	c.Assert(err, IsNil)
+	c.Assert(fc.threads, Equals, 2)
+
+	c.Assert(r.Forget(10, "PR-1"), IsNil)
+
+	_, err = r.PostText(10, "PR-1", "PR #1 reopened", "Build passed")
+	c.Assert(err, IsNil)
+	c.Assert(fc.threads, Equals, 3)
+
+	_, err = r.Thread(10, "PR-3", nil)
+	c.Assert(err, Equals, ErrNilRootMessage)
+	_, err = r.Thread(0, "PR-3", nil)
+	c.Assert(err, Equals, ErrInvalidChatID)
+	_, err = r.Thread(10, "", nil)
+	c.Assert(err, Equals, ErrEmptyKey)
+	_, err = r.Post(10, "PR-1", nil, nil)
+	c.Assert(err, Equals, ErrNilMessage)
+	c.Assert(r.Forget(0, "PR-1"), Equals, ErrInvalidChatID)
+	c.Assert(r.Forget(10, ""), Equals, ErrEmptyKey)
+
+	fc.fail = true
+	_, err = r.PostText(10, "PR-4", "PR #4 opened", "Build passed")
+	c.Assert(err, NotNil)
+
+	var nr *Router
+	_, err = nr.Thread(1, "A", nil)
+	c.Assert(err, Equals, ErrNilRouter)
+	_, err = nr.Post(1, "A", nil, nil)
+	c.Assert(err, Equals, ErrNilRouter)
+	c.Assert(nr.Forget(1, "A"), Equals, ErrNilRouter)
+}
+
+func (s *ThreadSuite) TestFileStore(c *C) {
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	file := c.MkDir() + "/threads.json"
+	fs, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	c.Assert(fs.Set(1, "A", &pachca.Thread{ID: 100}), IsNil)
+	c.Assert(fs.Set(1, "B", &pachca.Thread{ID: 200}), IsNil)
+	c.Assert(fs.Delete(1, "B"), IsNil)
+
+	fs, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	t, err := fs.Get(1, "A")
+	c.Assert(err, IsNil)
+	c.Assert(t, NotNil)
+	c.Assert(t.ID, Equals, uint(100))
+
+	t, err = fs.Get(1, "B")
+	c.Assert(err, IsNil)
+	c.Assert(t, IsNil)
+
+	_, err = NewFileStore(c.MkDir())
+	c.Assert(err, NotNil)
+
+	var nfs *FileStore
+	_, err = nfs.Get(1, "A")
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nfs.Set(1, "A", nil), Equals, ErrNilStore)
+	c.Assert(nfs.Delete(1, "A"), Equals, ErrNilStore)
+
+	var nms *MemoryStore
+	_, err = nms.Get(1, "A")
+	c.Assert(err, Equals, ErrNilStore)
+	c.Assert(nms.Set(1, "A", nil), Equals, ErrNilStore)
+	c.Assert(nms.Delete(1, "A"), Equals, ErrNilStore)
+}
+
+func (s *ThreadSuite) TestRouterRetry(c *C) {
+	fc := &fakeClient{failThread: true}
+	fs := &failStore{MemoryStore: NewMemoryStore(), fail: true}
+	r, err := NewRouter(fc, fs)
+	c.Assert(err, IsNil)
+
+	root := &pachca.MessageRequest{Content: "PR #1 opened"}
+
+	_, err = r.Thread(10, "PR-1", root)
+	c.Assert(err, ErrorMatches, `can't create thread for key "PR-1": API error`)
+	c.Assert(fc.messages, HasLen, 1)
+
+	fc.failThread = false
+
+	_, err = r.Thread(10, "PR-1", root)
+	c.Assert(err, ErrorMatches, `can't save thread for key "PR-1": store error`)
+	c.Assert(fc.messages, HasLen, 1)
+	c.Assert(fc.threads, Equals, 1)
+
+	fs.fail = false
+
+	t, err := r.Thread(10, "PR-1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(t.ID, Equals, uint(1001))
+	c.Assert(t.MessageID, Equals, uint(1))
+	c.Assert(fc.messages, HasLen, 1)
+	c.Assert(fc.threads, Equals, 1)
+	c.Assert(r.pending, HasLen, 0)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) AddMessage(message *pachca.MessageRequest, withPreview ...bool) (*pachca.Message, error) {
+	if c.fail {
+		return nil, errors.New("API error")
+	}
+
+	c.messages = append(c.messages, message)
+
+	return &pachca.Message{ID: uint(len(c.messages))}, nil
+}
+
+func (c *fakeClient) NewThread(messageID uint) (*pachca.Thread, error) {
+	if c.failThread {
+		return nil, errors.New("API error")
+	}
+
+	c.threads++
+	return &pachca.Thread{ID: 1000 + uint(c.threads), MessageID: messageID}, nil
+}
+
+func (s *failStore) Set(chatID uint, key string, thread *pachca.Thread) error {
+	if s.fail {
+		return errors.New("store error")
+	}
+
+	return s.MemoryStore.Set(chatID, key, thread)
+}
@@ -0,0 +1,423 @@
+package unfurl
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/req"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	DEFAULT_MAX_PAGE_SIZE   = 1024 * 1024     // 1 MB
+	DEFAULT_MAX_IMAGE_SIZE  = 5 * 1024 * 1024 // 5 MB
+	DEFAULT_MAX_DESCRIPTION = 300
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// OpenGraph is generic handler which creates previews using OpenGraph, Twitter
+// card and basic HTML meta tags
+type OpenGraph struct {
+	// Engine is HTTP engine used for fetching pages and images
+	Engine *req.Engine
+
+	// Domains is list of domains allowed for unfurling (e.g. "example.com" or
+	// "*.example.com"). Nothing is unfurled if list is empty.
+	Domains []string
+
+	// AllowPrivateIPs allows connections to private, loopback, link-local and
+	// shared (100.64.0.0/10) addresses. Such connections are refused by default
+	// to prevent requests to internal services. Addresses are checked only by
+	// engine created by NewOpenGraph, which also ignores proxy settings from
+	// environment, because proxy would hide target address.
+	AllowPrivateIPs bool
+
+	// MaxPageSize is maximum number of bytes read from page (DEFAULT_MAX_PAGE_SIZE
+	// is used if not set)
+	MaxPageSize int64
+
+	// MaxImageSize is maximum size of image which can be uploaded. Images
+	// which are bigger than limit are added by URL (DEFAULT_MAX_IMAGE_SIZE is
+	// used if not set).
+	MaxImageSize int64
+
+	// MaxDescription is maximum length of description in symbols (0 means
+	// no limit)
+	MaxDescription int
+
+	// UploadImage enables downloading and uploading images to Pachca
+	UploadImage bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ogTagRegex   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	ogAttrRegex  = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+	ogTitleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	ogSpaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// sharedAddressSpace is shared address space used by carrier-grade NAT (RFC 6598)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewOpenGraph creates new OpenGraph handler for given domains
+func NewOpenGraph(domains ...string) *OpenGraph {
+	h := &OpenGraph{
+		Domains:        domains,
+		MaxPageSize:    DEFAULT_MAX_PAGE_SIZE,
+		MaxImageSize:   DEFAULT_MAX_IMAGE_SIZE,
+		MaxDescription: DEFAULT_MAX_DESCRIPTION,
+		UploadImage:    true,
+	}
+
+	h.Engine = &req.Engine{
+		Dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   h.checkAddress,
+		},
+		Transport: &http.Transport{
+			Proxy:                 nil, // Proxy would hide target address from checkAddress
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+
+	h.Engine.Init()
+	h.Engine.SetUserAgent("EK|Pachca.go", "1")
+	h.Engine.Client.CheckRedirect = h.checkRedirect
+
+	return h
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Unfurl fetches page and creates preview from its meta tags
+func (h *OpenGraph) Unfurl(ctx context.Context, link *url.URL) (*Preview, error) {
+	switch {
+	case h == nil:
+		return nil, ErrNilHandler
+	case link == nil:
+		return nil, nil
+	case link.Scheme != "http" && link.Scheme != "https":
+		return nil, nil
+	case !h.IsAllowed(link.Hostname()):
+		return nil, nil
+	}
+
+	resp, err := h.engine().Get(req.Request{
+		URL:         link.String(),
+		Accept:      "text/html,application/xhtml+xml",
+		Ctx:         ctx,
+		AutoDiscard: true,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != req.STATUS_OK {
+		return nil, fmt.Errorf("server returned status code %d", resp.StatusCode)
+	}
+
+	pageURL := resp.Request.URL
+
+	if !h.IsAllowed(pageURL.Hostname()) || !isHTML(resp.Header.Get("Content-Type")) {
+		return nil, nil
+	}
+
+	limit := h.MaxPageSize
+
+	if limit <= 0 {
+		limit = DEFAULT_MAX_PAGE_SIZE
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read page: %w", err)
+	}
+
+	meta := parseMeta(string(data))
+	preview := &Preview{}
+
+	preview.Title = meta.Get("og:title", "twitter:title", "title")
+	preview.Description = truncate(
+		meta.Get("og:description", "twitter:description", "description"),
+		h.MaxDescription,
+	)
+
+	if preview.Title == "" && preview.Description == "" {
+		return nil, nil
+	}
+
+	image := meta.Get(
+		"og:image:secure_url", "og:image:url", "og:image",
+		"twitter:image", "twitter:image:src",
+	)
+
+	if image == "" {
+		return preview, nil
+	}
+
+	imageURL, err := pageURL.Parse(image)
+
+	if err != nil || (imageURL.Scheme != "http" && imageURL.Scheme != "https") {
+		return preview, nil
+	}
+
+	preview.ImageURL = imageURL.String()
+
+	if h.UploadImage && h.IsAllowed(imageURL.Hostname()) {
+		preview.ImageData, preview.ImageName, err = h.fetchImage(ctx, imageURL)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't download image: %w", err)
+		}
+
+		if preview.ImageData != nil {
+			preview.ImageURL = ""
+		}
+	}
+
+	return preview, nil
+}
+
+// IsAllowed returns true if given domain is allowed for unfurling
+func (h *OpenGraph) IsAllowed(host string) bool {
+	if h == nil || host == "" {
+		return false
+	}
+
+	host = strings.ToLower(host)
+
+	for _, d := range h.Domains {
+		if matchHost(strings.ToLower(d), host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// metaTags contains values of meta tags
+type metaTags map[string]string
+
+// Get returns the first non-empty value of given tags
+func (m metaTags) Get(names ...string) string {
+	for _, n := range names {
+		if m[n] != "" {
+			return m[n]
+		}
+	}
+
+	return ""
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// engine returns HTTP engine
+func (h *OpenGraph) engine() *req.Engine {
+	if h.Engine != nil {
+		return h.Engine
+	}
+
+	return req.Global
+}
+
+// checkRedirect prevents redirects to domains which are not allowed
+func (h *OpenGraph) checkRedirect(r *http.Request, via []*http.Request) error {
+	switch {
+	case len(via) >= 10:
+		return fmt.Errorf("stopped after 10 redirects")
+	case !h.IsAllowed(r.URL.Hostname()):
+		return fmt.Errorf("redirect to domain %q is not allowed", r.URL.Hostname())
+	}
+
+	return nil
+}
+
+// checkAddress prevents connections to private, loopback and link-local addresses
+func (h *OpenGraph) checkAddress(network, address string, _ syscall.RawConn) error {
+	if h.AllowPrivateIPs {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+
+	switch {
+	case ip == nil:
+		return fmt.Errorf("invalid address %q", address)
+	case ip.IsPrivate(), ip.IsLoopback(), ip.IsLinkLocalUnicast(),
+		ip.IsLinkLocalMulticast(), ip.IsUnspecified(), sharedAddressSpace.Contains(ip):
+		return fmt.Errorf("connection to private address %s is not allowed", ip)
+	}
+
+	return nil
+}
+
+// fetchImage downloads image. It returns nil data if image is too big or
+// content isn't an image.
+func (h *OpenGraph) fetchImage(ctx context.Context, u *url.URL) ([]byte, string, error) {
+	resp, err := h.engine().Get(req.Request{
+		URL:         u.String(),
+		Accept:      "image/*",
+		Ctx:         ctx,
+		AutoDiscard: true,
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != req.STATUS_OK {
+		return nil, "", fmt.Errorf("server returned status code %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	if !strings.HasPrefix(mediaType, "image/") {
+		return nil, "", nil
+	}
+
+	limit := h.MaxImageSize
+
+	if limit <= 0 {
+		limit = DEFAULT_MAX_IMAGE_SIZE
+	}
+
+	if resp.ContentLength > limit {
+		return nil, "", nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, "", nil
+	}
+
+	return data, imageName(u, mediaType), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseMeta extracts meta tags and title from HTML page
+func parseMeta(page string) metaTags {
+	head, _, _ := strings.Cut(page, "</head>")
+	result := metaTags{}
+
+	for _, tag := range ogTagRegex.FindAllString(head, -1) {
+		var name, content string
+
+		for _, attr := range ogAttrRegex.FindAllStringSubmatch(tag, -1) {
+			value := strings.Trim(attr[2], `"'`)
+
+			switch strings.ToLower(attr[1]) {
+			case "property", "name":
+				name = strings.ToLower(value)
+			case "content":
+				content = cleanText(value)
+			}
+		}
+
+		if name != "" && content != "" && result[name] == "" {
+			result[name] = content
+		}
+	}
+
+	title := ogTitleRegex.FindStringSubmatch(head)
+
+	if len(title) == 2 {
+		result["title"] = cleanText(title[1])
+	}
+
+	return result
+}
+
+// cleanText unescapes HTML entities and collapses whitespaces
+func cleanText(text string) string {
+	text = html.UnescapeString(text)
+	return strings.TrimSpace(ogSpaceRegex.ReplaceAllString(text, " "))
+}
+
+// truncate truncates text to given number of symbols
+func truncate(text string, size int) string {
+	if size <= 0 {
+		return text
+	}
+
+	r := []rune(text)
+
+	if len(r) <= size {
+		return text
+	}
+
+	return strings.TrimSpace(string(r[:size-1])) + "…"
+}
+
+// isHTML returns true if content type is HTML
+func isHTML(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// imageName returns name of image file
+func imageName(u *url.URL, mediaType string) string {
+	name := path.Base(u.Path)
+
+	if name == "" || name == "." || name == "/" {
+		name = "image"
+	}
+
+	if path.Ext(name) == "" {
+		exts, _ := mime.ExtensionsByType(mediaType)
+
+		if len(exts) != 0 {
+			name += exts[0]
+		}
+	}
+
+	return name
+}
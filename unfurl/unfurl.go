@@ -0,0 +1,409 @@
+package unfurl
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	DEFAULT_TIMEOUT     = 5 * time.Second
+	DEFAULT_CONCURRENCY = 4
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by dispatcher
+type Client interface {
+	AddLinkPreview(messageID uint, previews pachca.LinkPreviews) error
+	UploadFile(file string) (*pachca.File, error)
+}
+
+// Handler creates preview for link. Handler can return nil preview if link
+// can't be unfurled.
+type Handler interface {
+	Unfurl(ctx context.Context, link *url.URL) (*Preview, error)
+}
+
+// HandlerFunc is function which implements Handler interface
+type HandlerFunc func(ctx context.Context, link *url.URL) (*Preview, error)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Preview is link preview
+type Preview struct {
+	pachca.LinkPreview
+
+	ImageFile string // Path to image file which must be uploaded
+	ImageData []byte // Image data which must be uploaded
+	ImageName string // Name of image file (used with ImageData)
+}
+
+// Dispatcher routes shared links to matching handlers and adds previews
+// to messages
+type Dispatcher struct {
+	// Timeout is maximum duration of single handler execution
+	Timeout time.Duration
+
+	// Concurrency is maximum number of links processed simultaneously
+	Concurrency int
+
+	// Fallback is handler for links which don't match any pattern
+	Fallback Handler
+
+	client Client
+	routes []*route
+}
+
+// route contains link pattern and handler
+type route struct {
+	host   string
+	path   string
+	regex  *regexp.Regexp
+	handle Handler
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient        = errors.New("client is nil")
+	ErrNilDispatcher    = errors.New("dispatcher is nil")
+	ErrNilHandler       = errors.New("handler is nil")
+	ErrNilMessage       = errors.New("message is nil")
+	ErrEmptyPattern     = errors.New("pattern is empty")
+	ErrNilRegexp        = errors.New("regular expression is nil")
+	ErrInvalidMessageID = errors.New("message ID must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new dispatcher
+func New(client Client) (*Dispatcher, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	return &Dispatcher{
+		Timeout:     DEFAULT_TIMEOUT,
+		Concurrency: DEFAULT_CONCURRENCY,
+		client:      client,
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Handle registers handler for given pattern. Pattern contains domain and
+// optional path (e.g. "example.com", "*.example.com" or "example.com/issues/*").
+// Domain with "*." prefix matches domain itself and all subdomains. Path with
+// "*" suffix matches all paths with the same prefix. Handlers are checked in
+// registration order.
+func (d *Dispatcher) Handle(pattern string, handler Handler) error {
+	switch {
+	case d == nil:
+		return ErrNilDispatcher
+	case pattern == "":
+		return ErrEmptyPattern
+	case handler == nil:
+		return ErrNilHandler
+	}
+
+	host, path, _ := strings.Cut(strings.ToLower(pattern), "/")
+
+	if path != "" {
+		path = "/" + path
+	}
+
+	d.routes = append(d.routes, &route{host: host, path: path, handle: handler})
+
+	return nil
+}
+
+// HandleFunc registers handler function for given pattern
+func (d *Dispatcher) HandleFunc(pattern string, fn func(ctx context.Context, link *url.URL) (*Preview, error)) error {
+	if fn == nil {
+		return ErrNilHandler
+	}
+
+	return d.Handle(pattern, HandlerFunc(fn))
+}
+
+// HandleRegexp registers handler for links which match given regular expression
+func (d *Dispatcher) HandleRegexp(re *regexp.Regexp, handler Handler) error {
+	switch {
+	case d == nil:
+		return ErrNilDispatcher
+	case re == nil:
+		return ErrNilRegexp
+	case handler == nil:
+		return ErrNilHandler
+	}
+
+	d.routes = append(d.routes, &route{regex: re, handle: handler})
+
+	return nil
+}
+
+// Unfurl creates previews for all links from link_shared webhook and adds
+// them to the message. Previews which were created successfully are added even
+// if some of handlers returned errors.
+func (d *Dispatcher) Unfurl(msg *webhook.Message) error {
+	switch {
+	case d == nil:
+		return ErrNilDispatcher
+	case msg == nil:
+		return ErrNilMessage
+	case msg.MessageID == 0:
+		return ErrInvalidMessageID
+	}
+
+	links := make([]string, 0, len(msg.Links))
+
+	for _, l := range msg.Links {
+		if l != nil {
+			links = append(links, l.URL)
+		}
+	}
+
+	previews, err := d.Previews(links...)
+
+	if len(previews) != 0 {
+		errs := errors.NewBundle()
+		errs.Add(err, d.client.AddLinkPreview(msg.MessageID, previews))
+		err = errs.Join()
+	}
+
+	return err
+}
+
+// Previews creates previews for given links
+func (d *Dispatcher) Previews(links ...string) (pachca.LinkPreviews, error) {
+	if d == nil {
+		return nil, ErrNilDispatcher
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	result := pachca.LinkPreviews{}
+	errs := errors.NewBundle()
+	sem := make(chan struct{}, max(d.Concurrency, 1))
+
+	for i, link := range links {
+		if slices.Contains(links[:i], link) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			preview, err := d.unfurlLink(link)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs.Add(fmt.Errorf("can't unfurl link %q: %w", link, err))
+			} else if preview != nil {
+				result[link] = preview
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(result) == 0 {
+		result = nil
+	}
+
+	if !errs.IsEmpty() {
+		return result, errs.Join()
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Unfurl calls handler function
+func (f HandlerFunc) Unfurl(ctx context.Context, link *url.URL) (*Preview, error) {
+	return f(ctx, link)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// unfurlLink runs handler for link and uploads preview image
+func (d *Dispatcher) unfurlLink(link string) (*pachca.LinkPreview, error) {
+	u, err := url.Parse(link)
+
+	if err != nil {
+		return nil, err
+	}
+
+	handler := d.findHandler(u)
+
+	if handler == nil {
+		return nil, nil
+	}
+
+	timeout := d.Timeout
+
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type result struct {
+		preview *Preview
+		err     error
+	}
+
+	ch := make(chan result, 1)
+
+	go func() {
+		preview, err := handler.Unfurl(ctx, u)
+		ch <- result{preview, err}
+	}()
+
+	var preview *Preview
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("handler timed out after %v", timeout)
+	case r := <-ch:
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		preview = r.preview
+	}
+
+	if preview == nil {
+		return nil, nil
+	}
+
+	err = d.uploadImage(preview)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &preview.LinkPreview, nil
+}
+
+// findHandler returns handler for given URL
+func (d *Dispatcher) findHandler(u *url.URL) Handler {
+	for _, r := range d.routes {
+		if r.Match(u) {
+			return r.handle
+		}
+	}
+
+	return d.Fallback
+}
+
+// uploadImage uploads preview image if required
+func (d *Dispatcher) uploadImage(p *Preview) error {
+	file := p.ImageFile
+
+	if p.Image != nil || (file == "" && len(p.ImageData) == 0) {
+		return nil
+	}
+
+	if file == "" {
+		dir, err := os.MkdirTemp("", "unfurl-")
+
+		if err != nil {
+			return fmt.Errorf("can't create temporary directory: %w", err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		name := filepath.Base(p.ImageName)
+
+		if name == "" || name == "." || name == ".." || name == "/" {
+			name = "image"
+		}
+
+		file = filepath.Join(dir, name)
+		err = os.WriteFile(file, p.ImageData, 0600)
+
+		if err != nil {
+			return fmt.Errorf("can't save image: %w", err)
+		}
+	}
+
+	image, err := d.client.UploadFile(file)
+
+	if err != nil {
+		return fmt.Errorf("can't upload image: %w", err)
+	}
+
+	p.Image = image
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Match returns true if URL matches route
+func (r *route) Match(u *url.URL) bool {
+	if r.regex != nil {
+		return r.regex.MatchString(u.String())
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	if !matchHost(r.host, host) {
+		return false
+	}
+
+	switch {
+	case r.path == "":
+		return true
+	case strings.HasSuffix(r.path, "*"):
+		return strings.HasPrefix(strings.ToLower(u.Path), strings.TrimSuffix(r.path, "*"))
+	}
+
+	return strings.ToLower(strings.TrimSuffix(u.Path, "/")) == strings.TrimSuffix(r.path, "/")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// matchHost returns true if host matches host pattern
+func matchHost(pattern, host string) bool {
+	if strings.HasPrefix(pattern, "*.") {
+		domain := pattern[2:]
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+
+	return host == pattern
+}
@@ -0,0 +1,465 @@
+package unfurl
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+
+	"github.com/essentialkaos/pachca"
+	"github.com/essentialkaos/pachca/webhook"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type UnfurlSuite struct{}
+
+type fakeClient struct {
+	mu         sync.Mutex
+	previews   map[uint]pachca.LinkPreviews
+	uploads    []string
+	failUpload bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&UnfurlSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *UnfurlSuite) TestRouting(c *C) {
+	d, err := New(&fakeClient{})
+	c.Assert(err, IsNil)
+
+	c.Assert(d.HandleFunc("github.com/*/pull/*", titleHandler("pr")), IsNil)
+	c.Assert(d.HandleFunc("github.com", titleHandler("github")), IsNil)
+	c.Assert(d.HandleFunc("*.atlassian.net/browse/*", titleHandler("jira")), IsNil)
+	c.Assert(d.HandleFunc("kaos.sh/about", titleHandler("about")), IsNil)
+	c.Assert(d.HandleRegexp(regexp.MustCompile(`^https://[^/]+/docs/`), titleHandler("docs")), IsNil)
+
+	previews, err := d.Previews(
+		"https://GitHub.com/essentialkaos/pachca",
+		"https://essentialkaos.atlassian.net/browse/PCH-1",
+		"https://atlassian.net/browse/PCH-2",
+		"https://kaos.sh/about/",
+		"https://kaos.sh/about/team",
+		"https://domain.com/docs/api",
+		"https://unknown.com",
+		"https://GitHub.com/essentialkaos/pachca",
+	)
+	c.Assert(err, IsNil)
+	c.Assert(previews, HasLen, 5)
+	c.Assert(previews["https://GitHub.com/essentialkaos/pachca"].Title, Equals, "github")
+	c.Assert(previews["https://essentialkaos.atlassian.net/browse/PCH-1"].Title, Equals, "jira")
+	c.Assert(previews["https://atlassian.net/browse/PCH-2"].Title, Equals, "jira")
+	c.Assert(previews["https://kaos.sh/about/"].Title, Equals, "about")
+	c.Assert(previews["https://domain.com/docs/api"].Title, Equals, "docs")
+
+	d.Fallback = titleHandler("fallback")
+
+	previews, err = d.Previews("https://unknown.com", "https://github.com/a/b/pull/1")
+	c.Assert(err, IsNil)
+	c.Assert(previews["https://unknown.com"].Title, Equals, "fallback")
+	c.Assert(previews["https://github.com/a/b/pull/1"].Title, Equals, "github")
+
+	previews, err = d.Previews()
+	c.Assert(err, IsNil)
+	c.Assert(previews, IsNil)
+}
+
+func (s *UnfurlSuite) TestUnfurl(c *C) {
+	client := &fakeClient{previews: map[uint]pachca.LinkPreviews{}}
+	d, _ := New(client)
+
+	imageFile := filepath.Join(c.MkDir(), "image.png")
+	os.WriteFile(imageFile, []byte("PNG"), 0644)
+
+	d.HandleFunc("a.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return &Preview{
+			LinkPreview: pachca.LinkPreview{Title: "A", Description: "Site A"},
+			ImageFile:   imageFile,
+		}, nil
+	})
+
+	d.HandleFunc("b.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return &Preview{
+			LinkPreview: pachca.LinkPreview{Title: "B"},
+			ImageData:   []byte("PNG"),
+			ImageName:   "../logo.png",
+		}, nil
+	})
+
+	d.HandleFunc("c.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return nil, fmt.Errorf("not found")
+	})
+
+	d.HandleFunc("d.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return nil, nil
+	})
+
+	d.HandleFunc("e.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return &Preview{LinkPreview: pachca.LinkPreview{Title: "E", ImageURL: "https://e.com/i.png"}}, nil
+	})
+
+	d.HandleFunc("f.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return &Preview{
+			LinkPreview: pachca.LinkPreview{Title: "F"},
+			ImageData:   []byte("PNG"),
+			ImageName:   "..",
+		}, nil
+	})
+
+	err := d.Unfurl(&webhook.Message{
+		MessageID: 1,
+		Event:     webhook.EVENT_LINK_SHARED,
+		Links: []*webhook.UnfurlLink{
+			{URL: "https://a.com"}, {URL: "https://b.com"}, {URL: "https://c.com"},
+			{URL: "https://d.com"}, {URL: "https://e.com"}, {URL: "https://f.com"}, nil,
+		},
+	})
+
+	c.Assert(err, ErrorMatches, `can't unfurl link "https://c.com": not found`)
+	c.Assert(client.previews[1], HasLen, 4)
+	c.Assert(client.previews[1]["https://a.com"].Image.Name, Equals, "image.png")
+	c.Assert(client.previews[1]["https://b.com"].Image.Name, Equals, "logo.png")
+	c.Assert(client.previews[1]["https://e.com"].Image, IsNil)
+	c.Assert(client.previews[1]["https://f.com"].Image.Name, Equals, "image")
+	c.Assert(client.uploads, HasLen, 3)
+
+	// Nothing to add
+	err = d.Unfurl(&webhook.Message{MessageID: 2, Links: []*webhook.UnfurlLink{{URL: "https://d.com"}}})
+	c.Assert(err, IsNil)
+	c.Assert(client.previews[2], IsNil)
+
+	client.failUpload = true
+	err = d.Unfurl(&webhook.Message{MessageID: 3, Links: []*webhook.UnfurlLink{{URL: "https://b.com"}}})
+	c.Assert(err, ErrorMatches, `can't unfurl link "https://b.com": can't upload image: upload error`)
+
+	_, err = d.Previews("://broken")
+	c.Assert(err, NotNil)
+}
+
+func (s *UnfurlSuite) TestTimeout(c *C) {
+	d, _ := New(&fakeClient{})
+	d.Timeout = 20 * time.Millisecond
+	d.Concurrency = 0
+
+	canceled := make(chan struct{})
+
+	d.HandleFunc("slow.com", func(ctx context.Context, link *url.URL) (*Preview, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	})
+
+	d.HandleFunc("fast.com", titleHandler("fast"))
+
+	previews, err := d.Previews("https://slow.com", "https://fast.com")
+	c.Assert(err, ErrorMatches, `can't unfurl link "https://slow.com": handler timed out after 20ms`)
+	c.Assert(previews, HasLen, 1)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		c.Fatal("handler context wasn't canceled")
+	}
+}
+
+func (s *UnfurlSuite) TestErrors(c *C) {
+	_, err := New(nil)
+	c.Assert(err, Equals, ErrNilClient)
+
+	d, _ := New(&fakeClient{})
+
+	c.Assert(d.Handle("", titleHandler("")), Equals, ErrEmptyPattern)
+	c.Assert(d.Handle("a.com", nil), Equals, ErrNilHandler)
+	c.Assert(d.HandleFunc("a.com", nil), Equals, ErrNilHandler)
+	c.Assert(d.HandleRegexp(nil, titleHandler("")), Equals, ErrNilRegexp)
+	c.Assert(d.HandleRegexp(regexp.MustCompile(`.*`), nil), Equals, ErrNilHandler)
+	c.Assert(d.Unfurl(nil), Equals, ErrNilMessage)
+	c.Assert(d.Unfurl(&webhook.Message{}), Equals, ErrInvalidMessageID)
+
+	var nd *Dispatcher
+	c.Assert(nd.Handle("a.com", titleHandler("")), Equals, ErrNilDispatcher)
+	c.Assert(nd.HandleRegexp(regexp.MustCompile(`.*`), titleHandler("")), Equals, ErrNilDispatcher)
+	c.Assert(nd.Unfurl(&webhook.Message{}), Equals, ErrNilDispatcher)
+	_, err = nd.Previews("https://a.com")
+	c.Assert(err, Equals, ErrNilDispatcher)
+}
+
+func (s *UnfurlSuite) TestOpenGraph(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(ogHandler))
+	defer srv.Close()
+
+	srvURL, _ := url.Parse(srv.URL)
+
+	h := NewOpenGraph("127.0.0.1")
+	h.AllowPrivateIPs = true
+	h.MaxImageSize = 32
+	h.MaxDescription = 20
+
+	p, err := h.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p, NotNil)
+	c.Assert(p.Title, Equals, "Pachca & Go")
+	c.Assert(p.Description, Equals, "Go client for Pachc…")
+	c.Assert(p.ImageURL, Equals, "")
+	c.Assert(string(p.ImageData), Equals, "PNG")
+	c.Assert(p.ImageName, Equals, "logo.png")
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/twitter"))
+	c.Assert(err, IsNil)
+	c.Assert(p.Title, Equals, "Twitter Title")
+	c.Assert(p.Description, Equals, "Twitter text")
+	c.Assert(p.ImageURL, Equals, srv.URL+"/big.png")
+	c.Assert(p.ImageData, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/html"))
+	c.Assert(err, IsNil)
+	c.Assert(p.Title, Equals, "Simple Page")
+	c.Assert(p.Description, Equals, "Basic description")
+	c.Assert(p.ImageURL, Equals, "https://cdn.domain.com/image.jpg")
+	c.Assert(p.ImageData, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/redirect"))
+	c.Assert(err, IsNil)
+	c.Assert(p.Title, Equals, "Simple Page")
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/empty"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/file.pdf"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/unknown"))
+	c.Assert(err, ErrorMatches, `server returned status code 404`)
+	c.Assert(p, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/broken-image"))
+	c.Assert(err, ErrorMatches, `can't download image: server returned status code 500`)
+	c.Assert(p, IsNil)
+
+	_, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/external"))
+	c.Assert(err, ErrorMatches, `.*redirect to domain "domain.com" is not allowed`)
+
+	p, err = h.Unfurl(context.Background(), mustParse("https://domain.com/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	p, err = h.Unfurl(context.Background(), mustParse("ftp://127.0.0.1/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	p, err = h.Unfurl(context.Background(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	h.UploadImage = false
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p.ImageURL, Equals, srv.URL+"/logo.png")
+	c.Assert(p.ImageData, IsNil)
+
+	h.MaxPageSize = 64
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/html"))
+	c.Assert(err, IsNil)
+	c.Assert(p.Title, Equals, "Simple Page")
+	c.Assert(p.Description, Equals, "")
+
+	h.MaxPageSize = 16
+	p, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/html"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = h.Unfurl(ctx, mustParse(srv.URL+"/og"))
+	c.Assert(err, NotNil)
+
+	c.Assert(h.IsAllowed(srvURL.Hostname()), Equals, true)
+	c.Assert(h.IsAllowed(""), Equals, false)
+	c.Assert(NewOpenGraph().IsAllowed("domain.com"), Equals, false)
+	c.Assert(NewOpenGraph("*.domain.com").IsAllowed("wiki.Domain.com"), Equals, true)
+	c.Assert(NewOpenGraph("*.domain.com").IsAllowed("domain.org"), Equals, false)
+
+	var nh *OpenGraph
+	_, err = nh.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, Equals, ErrNilHandler)
+	c.Assert(nh.IsAllowed("domain.com"), Equals, false)
+
+	nh = &OpenGraph{}
+	p, err = nh.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	nh = &OpenGraph{Domains: []string{"127.0.0.1"}}
+	p, err = nh.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, IsNil)
+	c.Assert(p.ImageURL, Equals, srv.URL+"/logo.png")
+
+	// Private addresses are refused by default
+	h = NewOpenGraph("127.0.0.1", "localhost")
+	_, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, ErrorMatches, `.*connection to private address 127.0.0.1 is not allowed`)
+
+	for _, addr := range []string{"10.0.0.1:80", "169.254.169.254:80", "[::1]:443", "[fe80::1]:80", "0.0.0.0:80", "100.64.0.1:80", "100.127.255.254:443"} {
+		c.Assert(h.checkAddress("tcp", addr, nil), NotNil, Commentf("Address: %s", addr))
+	}
+
+	c.Assert(h.checkAddress("tcp", "1.1.1.1:443", nil), IsNil)
+	c.Assert(h.checkAddress("tcp", "100.128.0.1:443", nil), IsNil)
+
+	// Proxy from environment must not bypass address check
+	os.Setenv("HTTP_PROXY", "http://1.1.1.1:3128")
+	defer os.Unsetenv("HTTP_PROXY")
+
+	h = NewOpenGraph("127.0.0.1")
+	c.Assert(h.Engine.Transport.Proxy, IsNil)
+	_, err = h.Unfurl(context.Background(), mustParse(srv.URL+"/og"))
+	c.Assert(err, ErrorMatches, `.*connection to private address 127.0.0.1 is not allowed`)
+	c.Assert(h.checkAddress("tcp", "1.1.1.1", nil), NotNil)
+}
+
+func (s *UnfurlSuite) TestOpenGraphDispatcher(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(ogHandler))
+	defer srv.Close()
+
+	client := &fakeClient{previews: map[uint]pachca.LinkPreviews{}}
+	d, _ := New(client)
+	og := NewOpenGraph("127.0.0.1")
+	og.AllowPrivateIPs = true
+	d.Fallback = og
+
+	err := d.Unfurl(&webhook.Message{
+		MessageID: 1,
+		Links:     []*webhook.UnfurlLink{{URL: srv.URL + "/og"}, {URL: srv.URL + "/empty"}},
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(client.previews[1], HasLen, 1)
+	c.Assert(client.previews[1][srv.URL+"/og"].Title, Equals, "Pachca & Go")
+	c.Assert(client.previews[1][srv.URL+"/og"].Image.Name, Equals, "logo.png")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) AddLinkPreview(messageID uint, previews pachca.LinkPreviews) error {
+	c.previews[messageID] = previews
+	return nil
+}
+
+func (c *fakeClient) UploadFile(file string) (*pachca.File, error) {
+	if c.failUpload {
+		return nil, fmt.Errorf("upload error")
+	}
+
+	data, err := os.ReadFile(file)
+
+	if err != nil || string(data) != "PNG" {
+		return nil, fmt.Errorf("invalid file")
+	}
+
+	c.mu.Lock()
+	c.uploads = append(c.uploads, file)
+	c.mu.Unlock()
+
+	return &pachca.File{Key: "uploads/" + filepath.Base(file), Name: filepath.Base(file)}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func titleHandler(title string) HandlerFunc {
+	return func(ctx context.Context, link *url.URL) (*Preview, error) {
+		return &Preview{LinkPreview: pachca.LinkPreview{Title: title}}, nil
+	}
+}
+
+func mustParse(link string) *url.URL {
+	u, _ := url.Parse(link)
+	return u
+}
+
+func ogHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/og":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<!DOCTYPE html><html><head>
+<title>Ignored</title>
+<meta property="og:title" content="Pachca &amp; Go">
+<meta property='og:description' content="Go client   for Pachca
+messenger API">
+<meta property="og:image" content="/logo.png" />
+</head><body><meta property="og:title" content="Body"></body></html>`)
+
+	case "/twitter":
+		w.Header().Set("Content-Type", "application/xhtml+xml")
+		fmt.Fprint(w, `<html><head>
+<META NAME="twitter:title" CONTENT="Twitter Title">
+<meta name="twitter:description" content="Twitter text">
+<meta name="twitter:image" content="big.png">
+</head></html>`)
+
+	case "/html":
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head>
+<title>
+  Simple Page
+</title>
+<meta name="description" content="Basic description">
+<meta property="og:image" content="https://cdn.domain.com/image.jpg">
+</head></html>`)
+
+	case "/empty":
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head></head><body>Nothing</body></html>`)
+
+	case "/broken-image":
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Broken</title><meta property="og:image" content="/error.png"></head></html>`)
+
+	case "/redirect":
+		http.Redirect(w, r, "/html", http.StatusFound)
+
+	case "/external":
+		http.Redirect(w, r, "https://domain.com/og", http.StatusFound)
+
+	case "/file.pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		fmt.Fprint(w, "PDF")
+
+	case "/logo.png":
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "PNG")
+
+	case "/big.png":
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, strings.Repeat("PNG", 100))
+
+	case "/error.png":
+		w.WriteHeader(500)
+
+	default:
+		w.WriteHeader(404)
+	}
+}
@@ -0,0 +1,421 @@
+package worktime
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"cmp"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+	"github.com/essentialkaos/ek/v14/jsonutil"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client is the subset of Pachca API client methods used by deferred sender
+type Client interface {
+	GetUser(userID uint) (*pachca.User, error)
+	SendMessageToUser(userID uint, text string) (*pachca.Message, error)
+}
+
+// Store is storage for deferred messages
+type Store interface {
+	// Add adds message to the queue
+	Add(msg *Message) error
+
+	// Delete removes message with given ID from the queue
+	Delete(id string) error
+
+	// List returns all queued messages
+	List() ([]*Message, error)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Message is deferred direct message
+type Message struct {
+	ID        string    `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Text      string    `json:"text"`
+	SendAt    time.Time `json:"send_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sender sends direct messages only within recipient working hours. Messages
+// sent outside working hours are queued until working hours begin.
+type Sender struct {
+	// Hours is working hours (default working hours are used if nil)
+	Hours *Hours
+
+	// Location is time zone for users without time zone (UTC by default)
+	Location *time.Location
+
+	// OnError is callback for errors which occurred during background delivery
+	OnError func(msg *Message, err error)
+
+	client Client
+	store  Store
+
+	mu sync.Mutex
+}
+
+// MemoryStore is in-memory queue store
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string]*Message
+}
+
+// FileStore is queue store which keeps messages in JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	file string
+	data map[string]*Message
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilClient       = errors.New("client is nil")
+	ErrNilStore        = errors.New("store is nil")
+	ErrNilSender       = errors.New("sender is nil")
+	ErrNilMessage      = errors.New("message is nil")
+	ErrEmptyFilePath   = errors.New("store file path is empty")
+	ErrEmptyText       = errors.New("message text is empty")
+	ErrInvalidUserID   = errors.New("user ID must be greater than 0")
+	ErrInvalidInterval = errors.New("check interval must be greater than 0")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewSender creates new deferred sender
+func NewSender(client Client, store Store) (*Sender, error) {
+	switch {
+	case client == nil:
+		return nil, ErrNilClient
+	case store == nil:
+		return nil, ErrNilStore
+	}
+
+	return &Sender{client: client, store: store}, nil
+}
+
+// NewMemoryStore creates new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string]*Message{}}
+}
+
+// NewFileStore creates new file store and loads messages from given file if it
+// exists
+func NewFileStore(file string) (*FileStore, error) {
+	if file == "" {
+		return nil, ErrEmptyFilePath
+	}
+
+	s := &FileStore{file: file, data: map[string]*Message{}}
+
+	_, err := os.Stat(file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("can't check store file: %w", err)
+	}
+
+	err = jsonutil.Read(file, &s.data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read store file %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Send sends direct message to the user if it is working time for the user,
+// otherwise message is queued until working hours begin. Returned message is
+// nil if message was queued.
+func (s *Sender) Send(userID uint, text string) (*pachca.Message, error) {
+	return s.send(userID, text, time.Now())
+}
+
+// Pending returns queued messages sorted by delivery time
+func (s *Sender) Pending() ([]*Message, error) {
+	if s == nil {
+		return nil, ErrNilSender
+	}
+
+	msgs, err := s.store.List()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't list queued messages: %w", err)
+	}
+
+	slices.SortStableFunc(msgs, func(m1, m2 *Message) int {
+		return cmp.Or(m1.SendAt.Compare(m2.SendAt), m1.CreatedAt.Compare(m2.CreatedAt))
+	})
+
+	return msgs, nil
+}
+
+// Flush sends all queued messages which are due at given time. Messages which
+// can't be sent stay in the queue.
+func (s *Sender) Flush(now time.Time) error {
+	if s == nil {
+		return ErrNilSender
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs, err := s.Pending()
+
+	if err != nil {
+		return err
+	}
+
+	errs := errors.NewBundle()
+
+	for _, msg := range msgs {
+		if msg.SendAt.After(now) {
+			break
+		}
+
+		err = s.deliver(msg)
+
+		if err != nil {
+			errs.Add(fmt.Errorf("can't send message %s to user %d: %w", msg.ID, msg.UserID, err))
+
+			if s.OnError != nil {
+				s.OnError(msg, err)
+			}
+		}
+	}
+
+	if !errs.IsEmpty() {
+		return errs.Join()
+	}
+
+	return nil
+}
+
+// Run periodically sends queued messages until stop channel is closed
+func (s *Sender) Run(interval time.Duration, stop <-chan struct{}) error {
+	switch {
+	case s == nil:
+		return ErrNilSender
+	case interval <= 0:
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			s.Flush(now)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds message to the queue
+func (s *MemoryStore) Add(msg *Message) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case msg == nil:
+		return ErrNilMessage
+	}
+
+	s.mu.Lock()
+	s.data[msg.ID] = msg
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Delete removes message with given ID from the queue
+func (s *MemoryStore) Delete(id string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	delete(s.data, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns all queued messages
+func (s *MemoryStore) List() ([]*Message, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Message, 0, len(s.data))
+
+	for _, msg := range s.data {
+		result = append(result, msg)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds message to the queue
+func (s *FileStore) Add(msg *Message) error {
+	switch {
+	case s == nil:
+		return ErrNilStore
+	case msg == nil:
+		return ErrNilMessage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[msg.ID] = msg
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// Delete removes message with given ID from the queue
+func (s *FileStore) Delete(id string) error {
+	if s == nil {
+		return ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[id] == nil {
+		return nil
+	}
+
+	delete(s.data, id)
+
+	return jsonutil.Write(s.file, s.data, 0600)
+}
+
+// List returns all queued messages
+func (s *FileStore) List() ([]*Message, error) {
+	if s == nil {
+		return nil, ErrNilStore
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Message, 0, len(s.data))
+
+	for _, msg := range s.data {
+		result = append(result, msg)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// send sends or queues message using given time as current time
+func (s *Sender) send(userID uint, text string, now time.Time) (*pachca.Message, error) {
+	switch {
+	case s == nil:
+		return nil, ErrNilSender
+	case userID == 0:
+		return nil, ErrInvalidUserID
+	case text == "":
+		return nil, ErrEmptyText
+	}
+
+	user, err := s.client.GetUser(userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't get user %d: %w", userID, err)
+	}
+
+	// Bots don't have working hours
+	if user.IsBot {
+		return s.client.SendMessageToUser(userID, text)
+	}
+
+	sendAt, err := s.Hours.Next(now.In(s.getLocation(user)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !sendAt.After(now) {
+		return s.client.SendMessageToUser(userID, text)
+	}
+
+	err = s.store.Add(&Message{
+		ID:        rand.Text(),
+		UserID:    userID,
+		Text:      text,
+		SendAt:    sendAt.UTC(),
+		CreatedAt: now.UTC(),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't queue message: %w", err)
+	}
+
+	return nil, nil
+}
+
+// deliver sends queued message and removes it from the queue
+func (s *Sender) deliver(msg *Message) error {
+	_, err := s.client.SendMessageToUser(msg.UserID, msg.Text)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Delete(msg.ID)
+
+	if err != nil {
+		return fmt.Errorf("can't remove message from queue: %w", err)
+	}
+
+	return nil
+}
+
+// getLocation returns location of user
+func (s *Sender) getLocation(user *pachca.User) *time.Location {
+	loc, err := Location(user)
+
+	if err == nil {
+		return loc
+	}
+
+	if s.Location == nil {
+		return time.UTC
+	}
+
+	return s.Location
+}
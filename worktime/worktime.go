@@ -0,0 +1,256 @@
+package worktime
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	DEFAULT_START = 9 * time.Hour  // Default start of working day
+	DEFAULT_END   = 18 * time.Hour // Default end of working day
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Hours contains working hours configuration. Start and End are offsets from
+// the midnight in user local time.
+type Hours struct {
+	Start time.Duration  // Start of working day
+	End   time.Duration  // End of working day
+	Days  []time.Weekday // Working days (Monday-Friday if empty)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DefaultHours is default working hours (9:00-18:00, Monday-Friday)
+var DefaultHours = &Hours{
+	Start: DEFAULT_START,
+	End:   DEFAULT_END,
+}
+
+// workDays is default working days
+var workDays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilUser         = errors.New("user is nil")
+	ErrEmptyTimeZone   = errors.New("user time zone is empty")
+	ErrInvalidHours    = errors.New("working hours must be within one day and end after start")
+	ErrNoWorkingWindow = errors.New("can't find next working day")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Location returns time zone of user. Both IANA names ("Europe/Moscow") and
+// UTC offsets ("+03:00", "UTC+3") are supported.
+func Location(user *pachca.User) (*time.Location, error) {
+	switch {
+	case user == nil:
+		return nil, ErrNilUser
+	case strings.TrimSpace(user.TimeZone) == "":
+		return nil, ErrEmptyTimeZone
+	}
+
+	return ParseTimeZone(user.TimeZone)
+}
+
+// ParseTimeZone parses time zone name or UTC offset
+func ParseTimeZone(tz string) (*time.Location, error) {
+	tz = strings.TrimSpace(tz)
+
+	if tz == "" {
+		return nil, ErrEmptyTimeZone
+	}
+
+	if loc, ok := parseOffset(tz); ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't parse time zone %q: %w", tz, err)
+	}
+
+	return loc, nil
+}
+
+// LocalTime returns given moment in user local time
+func LocalTime(user *pachca.User, t time.Time) (time.Time, error) {
+	loc, err := Location(user)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.In(loc), nil
+}
+
+// IsWorkingTime returns true if given moment is inside user working hours. If
+// hours is nil, default working hours are used.
+func IsWorkingTime(user *pachca.User, t time.Time, hours *Hours) (bool, error) {
+	t, err := LocalTime(user, t)
+
+	if err != nil {
+		return false, err
+	}
+
+	return hours.orDefault().Contains(t), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates working hours configuration
+func (h *Hours) Validate() error {
+	switch {
+	case h == nil:
+		return nil
+	case h.Start < 0 || h.End > 24*time.Hour || h.End <= h.Start:
+		return ErrInvalidHours
+	}
+
+	return nil
+}
+
+// Contains returns true if given moment is inside working hours. Moment is
+// checked in its own location, so it must be converted to user local time
+// first.
+func (h *Hours) Contains(t time.Time) bool {
+	h = h.orDefault()
+
+	if !slices.Contains(h.days(), t.Weekday()) {
+		return false
+	}
+
+	offset := clock(t)
+
+	return offset >= h.Start && offset < h.End
+}
+
+// Next returns the nearest moment inside working hours starting from given
+// moment. If moment is already inside working hours, it is returned as is.
+func (h *Hours) Next(t time.Time) (time.Time, error) {
+	h = h.orDefault()
+
+	err := h.Validate()
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if h.Contains(t) {
+		return t, nil
+	}
+
+	days := h.days()
+	y, m, d := t.Date()
+
+	for i := range 8 {
+		start := time.Date(y, m, d+i, 0, 0, 0, 0, t.Location()).Add(h.Start)
+
+		if slices.Contains(days, start.Weekday()) && start.After(t) {
+			return start, nil
+		}
+	}
+
+	return time.Time{}, ErrNoWorkingWindow
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// orDefault returns default hours if hours is nil
+func (h *Hours) orDefault() *Hours {
+	if h == nil {
+		return DefaultHours
+	}
+
+	return h
+}
+
+// days returns working days
+func (h *Hours) days() []time.Weekday {
+	if len(h.Days) == 0 {
+		return workDays
+	}
+
+	return h.Days
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// clock returns offset of moment from the midnight
+func clock(t time.Time) time.Duration {
+	hour, min, sec := t.Clock()
+
+	return time.Duration(hour)*time.Hour +
+		time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+// parseOffset parses UTC offset in formats "+03:00", "-0530", "UTC+3" or
+// "GMT+03:00"
+func parseOffset(tz string) (*time.Location, bool) {
+	name := tz
+	upper := strings.ToUpper(tz)
+
+	switch {
+	case upper == "UTC" || upper == "GMT" || upper == "Z":
+		return time.UTC, true
+	case strings.HasPrefix(upper, "UTC"), strings.HasPrefix(upper, "GMT"):
+		tz = tz[3:]
+	}
+
+	if len(tz) < 2 || (tz[0] != '+' && tz[0] != '-') {
+		return nil, false
+	}
+
+	sign := 1
+
+	if tz[0] == '-' {
+		sign = -1
+	}
+
+	hh, mm, hasSep := strings.Cut(tz[1:], ":")
+
+	if !hasSep && len(hh) == 4 {
+		hh, mm = hh[:2], hh[2:]
+	}
+
+	hours, err := strconv.Atoi(hh)
+
+	if err != nil || hours < 0 || hours > 14 || len(hh) > 2 {
+		return nil, false
+	}
+
+	minutes := 0
+
+	if mm != "" {
+		minutes, err = strconv.Atoi(mm)
+
+		if err != nil || minutes < 0 || minutes > 59 || len(mm) != 2 {
+			return nil, false
+		}
+	}
+
+	return time.FixedZone(name, sign*(hours*3600+minutes*60)), true
+}
@@ -0,0 +1,311 @@
+package worktime
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2026 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/ek/v14/errors"
+
+	"github.com/essentialkaos/pachca"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type fakeClient struct {
+	users   map[uint]*pachca.User
+	sent    map[uint][]string
+	sendErr error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type WorktimeSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&WorktimeSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *WorktimeSuite) TestLocation(c *C) {
+	_, err := Location(nil)
+	c.Assert(err, Equals, ErrNilUser)
+
+	_, err = Location(&pachca.User{})
+	c.Assert(err, Equals, ErrEmptyTimeZone)
+
+	_, err = ParseTimeZone(" ")
+	c.Assert(err, Equals, ErrEmptyTimeZone)
+
+	_, err = Location(&pachca.User{TimeZone: "Mars/Olympus"})
+	c.Assert(err, ErrorMatches, `can't parse time zone "Mars/Olympus": .*`)
+
+	loc, err := Location(&pachca.User{TimeZone: "Europe/Moscow"})
+	c.Assert(err, IsNil)
+	c.Assert(loc.String(), Equals, "Europe/Moscow")
+
+	for tz, offset := range map[string]int{
+		"UTC":       0,
+		"gmt":       0,
+		"+03:00":    3 * 3600,
+		"-0530":     -(5*3600 + 30*60),
+		"UTC+3":     3 * 3600,
+		"GMT-04:30": -(4*3600 + 30*60),
+	} {
+		loc, err = ParseTimeZone(tz)
+		c.Assert(err, IsNil, Commentf("Time zone: %s", tz))
+		_, off := time.Date(2026, 1, 1, 0, 0, 0, 0, loc).Zone()
+		c.Assert(off, Equals, offset, Commentf("Time zone: %s", tz))
+	}
+
+	for _, tz := range []string{"+", "+15", "+3:5", "+03:60", "+-3", "UTC+abc", "+123"} {
+		_, err = ParseTimeZone(tz)
+		c.Assert(err, NotNil, Commentf("Time zone: %s", tz))
+	}
+
+	now := time.Date(2026, 10, 19, 6, 30, 0, 0, time.UTC)
+
+	_, err = LocalTime(nil, now)
+	c.Assert(err, Equals, ErrNilUser)
+
+	lt, err := LocalTime(&pachca.User{TimeZone: "Europe/Moscow"}, now)
+	c.Assert(err, IsNil)
+	c.Assert(lt.Hour(), Equals, 9)
+	c.Assert(lt.Minute(), Equals, 30)
+}
+
+func (s *WorktimeSuite) TestHours(c *C) {
+	user := &pachca.User{TimeZone: "+03:00"}
+
+	// Monday, 06:30 UTC → 09:30 local
+	ok, err := IsWorkingTime(user, time.Date(2026, 10, 19, 6, 30, 0, 0, time.UTC), nil)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	// Monday, 05:30 UTC → 08:30 local
+	ok, err = IsWorkingTime(user, time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC), nil)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	_, err = IsWorkingTime(nil, time.Now(), nil)
+	c.Assert(err, Equals, ErrNilUser)
+
+	h := &Hours{Start: 10 * time.Hour, End: 14 * time.Hour, Days: []time.Weekday{time.Saturday}}
+	sat := time.Date(2026, 10, 24, 12, 0, 0, 0, time.UTC)
+
+	c.Assert(h.Contains(sat), Equals, true)
+	c.Assert(h.Contains(sat.Add(2*time.Hour)), Equals, false)
+	c.Assert(h.Contains(sat.Add(-24*time.Hour)), Equals, false)
+
+	var nilHours *Hours
+
+	c.Assert(nilHours.Validate(), IsNil)
+	c.Assert(nilHours.Contains(sat.Add(-24*time.Hour)), Equals, true)
+	c.Assert((&Hours{Start: 10 * time.Hour, End: 9 * time.Hour}).Validate(), Equals, ErrInvalidHours)
+	c.Assert((&Hours{Start: -time.Hour, End: 9 * time.Hour}).Validate(), Equals, ErrInvalidHours)
+	c.Assert((&Hours{Start: time.Hour, End: 25 * time.Hour}).Validate(), Equals, ErrInvalidHours)
+
+	_, err = (&Hours{}).Next(sat)
+	c.Assert(err, Equals, ErrInvalidHours)
+
+	// Inside working hours
+	next, err := h.Next(sat)
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, sat)
+
+	// Before start on working day
+	next, err = h.Next(sat.Add(-3 * time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, time.Date(2026, 10, 24, 10, 0, 0, 0, time.UTC))
+
+	// After end on working day
+	next, err = h.Next(sat.Add(3 * time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, time.Date(2026, 10, 31, 10, 0, 0, 0, time.UTC))
+
+	// Friday evening with default hours
+	next, err = nilHours.Next(time.Date(2026, 10, 23, 19, 0, 0, 0, time.UTC))
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, time.Date(2026, 10, 26, 9, 0, 0, 0, time.UTC))
+}
+
+func (s *WorktimeSuite) TestSender(c *C) {
+	client := &fakeClient{
+		users: map[uint]*pachca.User{
+			1: {ID: 1, TimeZone: "+03:00"},
+			2: {ID: 2},
+			3: {ID: 3, IsBot: true},
+		},
+		sent: map[uint][]string{},
+	}
+
+	_, err := NewSender(nil, NewMemoryStore())
+	c.Assert(err, Equals, ErrNilClient)
+	_, err = NewSender(client, nil)
+	c.Assert(err, Equals, ErrNilStore)
+
+	file := c.MkDir() + "/queue.json"
+	store, err := NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	sender, err := NewSender(client, store)
+	c.Assert(err, IsNil)
+
+	sender.Location = time.FixedZone("UTC+5", 5*3600)
+
+	// Monday, 05:30 UTC → 08:30 for user 1, 10:30 for user 2
+	now := time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC)
+
+	msg, err := sender.send(1, "Hello 1", now)
+	c.Assert(err, IsNil)
+	c.Assert(msg, IsNil)
+
+	msg, err = sender.send(2, "Hello 2", now)
+	c.Assert(err, IsNil)
+	c.Assert(msg, NotNil)
+
+	msg, err = sender.send(3, "Hello 3", now.Add(-5*time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(msg, NotNil)
+
+	c.Assert(client.sent[1], HasLen, 0)
+	c.Assert(client.sent[2], DeepEquals, []string{"Hello 2"})
+	c.Assert(client.sent[3], DeepEquals, []string{"Hello 3"})
+
+	// Deferred messages survive restart
+	store, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+
+	sender, err = NewSender(client, store)
+	c.Assert(err, IsNil)
+
+	pending, err := sender.Pending()
+	c.Assert(err, IsNil)
+	c.Assert(pending, HasLen, 1)
+	c.Assert(pending[0].UserID, Equals, uint(1))
+	c.Assert(pending[0].SendAt, Equals, time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC))
+
+	c.Assert(sender.Flush(now), IsNil)
+	c.Assert(client.sent[1], HasLen, 0)
+
+	client.sendErr = errors.New("API error")
+
+	var failed *Message
+
+	sender.OnError = func(msg *Message, err error) { failed = msg }
+
+	c.Assert(sender.Flush(now.Add(time.Hour)), ErrorMatches, `can't send message .* to user 1: API error`)
+	c.Assert(failed, NotNil)
+
+	pending, _ = sender.Pending()
+	c.Assert(pending, HasLen, 1)
+
+	client.sendErr = nil
+
+	c.Assert(sender.Flush(now.Add(time.Hour)), IsNil)
+	c.Assert(client.sent[1], DeepEquals, []string{"Hello 1"})
+
+	pending, _ = sender.Pending()
+	c.Assert(pending, HasLen, 0)
+
+	store, err = NewFileStore(file)
+	c.Assert(err, IsNil)
+	msgs, _ := store.List()
+	c.Assert(msgs, HasLen, 0)
+}
+
+func (s *WorktimeSuite) TestSenderErrors(c *C) {
+	client := &fakeClient{users: map[uint]*pachca.User{}, sent: map[uint][]string{}}
+	sender, _ := NewSender(client, NewMemoryStore())
+
+	_, err := sender.Send(0, "Test")
+	c.Assert(err, Equals, ErrInvalidUserID)
+	_, err = sender.Send(1, "")
+	c.Assert(err, Equals, ErrEmptyText)
+	_, err = sender.Send(1, "Test")
+	c.Assert(err, ErrorMatches, `can't get user 1: user not found`)
+
+	sender.Hours = &Hours{}
+	client.users[1] = &pachca.User{ID: 1}
+
+	_, err = sender.Send(1, "Test")
+	c.Assert(err, Equals, ErrInvalidHours)
+
+	c.Assert(sender.Run(0, nil), Equals, ErrInvalidInterval)
+
+	var nilSender *Sender
+
+	_, err = nilSender.Send(1, "Test")
+	c.Assert(err, Equals, ErrNilSender)
+	_, err = nilSender.Pending()
+	c.Assert(err, Equals, ErrNilSender)
+	c.Assert(nilSender.Flush(time.Now()), Equals, ErrNilSender)
+	c.Assert(nilSender.Run(time.Second, nil), Equals, ErrNilSender)
+}
+
+func (s *WorktimeSuite) TestStores(c *C) {
+	_, err := NewFileStore("")
+	c.Assert(err, Equals, ErrEmptyFilePath)
+
+	file := c.MkDir() + "/queue.json"
+	os.WriteFile(file, []byte("{"), 0600)
+
+	_, err = NewFileStore(file)
+	c.Assert(err, ErrorMatches, `can't read store file .*`)
+
+	var ms *MemoryStore
+	var fs *FileStore
+
+	c.Assert(ms.Add(&Message{}), Equals, ErrNilStore)
+	c.Assert(ms.Delete("1"), Equals, ErrNilStore)
+	_, err = ms.List()
+	c.Assert(err, Equals, ErrNilStore)
+
+	c.Assert(fs.Add(&Message{}), Equals, ErrNilStore)
+	c.Assert(fs.Delete("1"), Equals, ErrNilStore)
+	_, err = fs.List()
+	c.Assert(err, Equals, ErrNilStore)
+
+	ms = NewMemoryStore()
+	c.Assert(ms.Add(nil), Equals, ErrNilMessage)
+	c.Assert(ms.Add(&Message{ID: "1"}), IsNil)
+	c.Assert(ms.Delete("1"), IsNil)
+
+	fs, _ = NewFileStore(c.MkDir() + "/queue.json")
+	c.Assert(fs.Add(nil), Equals, ErrNilMessage)
+	c.Assert(fs.Delete("1"), IsNil)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (c *fakeClient) GetUser(userID uint) (*pachca.User, error) {
+	user := c.users[userID]
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func (c *fakeClient) SendMessageToUser(userID uint, text string) (*pachca.Message, error) {
+	if c.sendErr != nil {
+		return nil, c.sendErr
+	}
+
+	c.sent[userID] = append(c.sent[userID], text)
+
+	return &pachca.Message{ID: uint(len(c.sent[userID]))}, nil
+}